@@ -3,9 +3,12 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
+	"slices"
 	"sort"
 	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 )
@@ -33,9 +36,28 @@ type QuestionAnswer struct {
 	Status         QAStatus
 	Timestamp      time.Time
 	ProcessingTime time.Duration
-	DirectoryKey   string // The directory this question belongs to
+	DirectoryKey   string    // The directory this question belongs to
+	RetryCount     int       // Times the question was re-queued after its specialist went away or silent
+	RequeueCount   int       // Of those, re-queues because the specialist did not answer within the processing timeout
+	MaxRetries     int       // Re-queue budget before the question fails
+	TargetName     string    // Only this specialist may pick the question up (empty = any)
+	ReplayOf       string    // ID of the question this one replays (replay_questions), if tagged
+	Deadline       time.Time // When the asker stops waiting (zero = no timeout); later answers are rejected
+	AnswerBy       time.Time // When the specialist's claim expires (--qa-processing-timeout, zero = never)
 }
 
+// askerGaveUp reports whether the asker's timeout has passed
+func (qa *QuestionAnswer) askerGaveUp(now time.Time) bool {
+	return !qa.Deadline.IsZero() && now.After(qa.Deadline)
+}
+
+const (
+	DefaultQuestionRetries = 3  // Times an abandoned question is re-queued by default
+	MaxQuestionRetries     = 10 // Upper bound for the retries argument
+
+	MaxPendingQuestions = 1000 // Questions a directory holds before anyone picks them up
+)
+
 // SpecialistAgent represents a registered specialist agent
 type SpecialistAgent struct {
 	ID          string
@@ -48,11 +70,24 @@ type SpecialistAgent struct {
 
 // SpecialistDirectory represents a directory where specialists can answer questions
 type SpecialistDirectory struct {
-	Key         string    // "<root_dir>-<specialty>"
-	RootDir     string    // Project root directory (physical folder path)
-	Specialty   string    // Area of expertise
-	Instruction string    // Usage guidance
-	CreatedAt   time.Time // When directory was created
+	Key                string    // "<root_dir>-<specialty>"
+	RootDir            string    // Project root directory (physical folder path)
+	Specialty          string    // Area of expertise
+	Instruction        string    // Usage guidance
+	InstructionVersion int       // Bumped every time the instructions change (0 = never set)
+	CreatedAt          time.Time // When directory was created
+	UpdatedAt          time.Time // When the instructions last changed
+}
+
+// setInstruction records new instructions, bumping the version only when they actually change
+func (dir *SpecialistDirectory) setInstruction(instructions string) bool {
+	if dir.Instruction == instructions {
+		return false
+	}
+	dir.Instruction = instructions
+	dir.InstructionVersion++
+	dir.UpdatedAt = time.Now()
+	return true
 }
 
 // ActiveWaiter tracks an active specialist waiting for questions
@@ -75,6 +110,15 @@ type AgentQARegistry struct {
 	dirConds    map[string]*sync.Cond // key: dirKey - wakes specialist when question arrives
 	answerConds map[string]*sync.Cond // key: questionID - wakes questioner when answer arrives
 
+	// Size limits for question/answer text (0 = unlimited)
+	maxQuestionBytes  int
+	maxAnswerBytes    int
+	truncateOversized bool // Truncate instead of rejecting oversized text
+
+	auditLog io.Writer // --qa-audit-log: one NDJSON record per answered or failed question (nil = off)
+
+	processingTimeout time.Duration // --qa-processing-timeout: re-queue questions a specialist sits on longer (0 = off)
+
 	mutex sync.Mutex // Must be Mutex (not RWMutex) for sync.Cond
 }
 
@@ -93,6 +137,62 @@ func NewAgentQARegistry() *AgentQARegistry {
 	return r
 }
 
+// SetSizeLimits configures the maximum question and answer sizes in bytes (0 = unlimited).
+// When truncate is true, oversized text is truncated instead of rejected.
+func (r *AgentQARegistry) SetSizeLimits(maxQuestionBytes, maxAnswerBytes int, truncate bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.maxQuestionBytes = maxQuestionBytes
+	r.maxAnswerBytes = maxAnswerBytes
+	r.truncateOversized = truncate
+}
+
+// SetProcessingTimeout sets how long a specialist may hold a question before it is re-queued
+// (or failed once its retries are used up). 0 disables the timeout.
+func (r *AgentQARegistry) SetProcessingTimeout(timeout time.Duration) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.processingTimeout = timeout
+}
+
+// SizeLimits returns the configured question/answer size limits (0 = unlimited)
+func (r *AgentQARegistry) SizeLimits() (maxQuestionBytes, maxAnswerBytes int, truncate bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.maxQuestionBytes, r.maxAnswerBytes, r.truncateOversized
+}
+
+// qaTruncationMarker is appended to text truncated by enforceSizeLimit
+const qaTruncationMarker = "\n[truncated by sidekick]"
+
+// enforceSizeLimit checks text against a byte limit, truncating or rejecting it.
+// Must be called with mutex held.
+func (r *AgentQARegistry) enforceSizeLimit(kind, text string, limit int) (string, error) {
+	if limit <= 0 || len(text) <= limit {
+		return text, nil
+	}
+
+	if !r.truncateOversized {
+		return "", fmt.Errorf("%s exceeds maximum size of %d bytes (got %d bytes)", kind, limit, len(text))
+	}
+
+	// Keep the result within the limit, including the marker when it fits
+	marker := qaTruncationMarker
+	if len(marker) >= limit {
+		marker = ""
+	}
+	cut := limit - len(marker)
+	// Back off to a UTF-8 rune boundary
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+
+	LogWarn("AgentQA", fmt.Sprintf("Truncated oversized %s", kind), fmt.Sprintf("Size: %d bytes, Limit: %d bytes", len(text), limit))
+	return text[:cut] + marker, nil
+}
+
 // getDirCond gets or creates a condition variable for a directory
 func (r *AgentQARegistry) getDirCond(dirKey string) *sync.Cond {
 	if r.dirConds[dirKey] == nil {
@@ -152,52 +252,267 @@ func (r *AgentQARegistry) ListDirectories() []*SpecialistDirectory {
 	return dirs
 }
 
-// askQuestionInternal is the core implementation for submitting questions to specialists.
-// If wait is true, blocks until answer is available (respecting timeout).
-// If wait is false, returns immediately with the question ID.
-// Questions are queued even if no specialist is currently waiting - a specialist can pick it up later.
-func (r *AgentQARegistry) askQuestionInternal(from, specialty, rootDir, question string, wait bool, timeout time.Duration) (*QuestionAnswer, error) {
-	r.mutex.Lock()
+// GlobalRootDir is the root_dir of global (org-wide) specialist directories, registered with
+// global: true. Their key is "*-<specialty>", and questions for a project without a directory
+// of its own fall back to them.
+const GlobalRootDir = "*"
 
-	// 1. Create directory key
-	dirKey := fmt.Sprintf("%s-%s", rootDir, specialty)
+// directoryKey returns the key of the directory for a specialty in rootDir
+func directoryKey(rootDir, specialty string) string {
+	return fmt.Sprintf("%s-%s", rootDir, specialty)
+}
 
-	// 2. Create or get directory
-	if r.directories[dirKey] == nil {
-		r.directories[dirKey] = &SpecialistDirectory{
+// ensureDirectory creates the directory and its question queue if needed.
+// Non-empty instructions replace the existing ones. Returns the directory and whether it was created.
+// Must be called with mutex held.
+func (r *AgentQARegistry) ensureDirectory(dirKey, rootDir, specialty, instructions string) (*SpecialistDirectory, bool) {
+	created := false
+	dir := r.directories[dirKey]
+	if dir == nil {
+		dir = &SpecialistDirectory{
 			Key:       dirKey,
 			RootDir:   rootDir,
 			Specialty: specialty,
 			CreatedAt: time.Now(),
 		}
-		LogInfo("AgentQA", fmt.Sprintf("Created directory '%s' for incoming question", dirKey))
+		r.directories[dirKey] = dir
+		created = true
+	}
+	if instructions != "" {
+		dir.setInstruction(instructions)
 	}
 
-	// 3. Initialize question queue for directory if needed
 	if r.questionQueues[dirKey] == nil {
 		r.questionQueues[dirKey] = make([]*QuestionAnswer, 0)
 	}
 
-	// 4. Create question entry
-	qa := &QuestionAnswer{
-		ID:           uuid.New().String(),
-		From:         from,
-		To:           specialty, // Will be updated by specialist who picks it up
-		Question:     question,
-		Status:       QAStatusPending,
-		Timestamp:    time.Now(),
-		DirectoryKey: dirKey,
+	return dir, created
+}
+
+// RegisterDirectory creates or updates a specialist directory without waiting for questions.
+// This lets askers discover the specialty via list_specialists before a specialist is waiting.
+func (r *AgentQARegistry) RegisterDirectory(specialty, rootDir, instructions string) (*SpecialistDirectory, bool) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	dirKey := directoryKey(rootDir, specialty)
+	dir, created := r.ensureDirectory(dirKey, rootDir, specialty, instructions)
+	if created {
+		LogInfo("AgentQA", fmt.Sprintf("Registered directory '%s'", dirKey))
+	} else {
+		LogInfo("AgentQA", fmt.Sprintf("Updated directory '%s'", dirKey))
 	}
 
-	// 5. Add to index for fast lookup
-	r.qaIndex[qa.ID] = qa
+	// Return a copy so callers can read it without holding the mutex
+	dirCopy := *dir
+	return &dirCopy, created
+}
 
-	// 6. Append to queue (NEVER removed - append-only)
-	r.questionQueues[dirKey] = append(r.questionQueues[dirKey], qa)
+// UpdateInstructions replaces the instructions of an existing directory, with or without an
+// active waiter. An empty string clears them. Returns a copy of the directory and whether it changed.
+func (r *AgentQARegistry) UpdateInstructions(specialty, rootDir, instructions string) (*SpecialistDirectory, bool, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
 
-	// 7. Wake up specialist waiting for THIS directory only
-	dirCond := r.getDirCond(dirKey)
-	dirCond.Signal() // Signal, not Broadcast - only one specialist per directory
+	dirKey := directoryKey(rootDir, specialty)
+	dir := r.directories[dirKey]
+	if dir == nil {
+		return nil, false, fmt.Errorf("no specialist directory for specialty '%s' in '%s' - use register_specialist first", specialty, rootDir)
+	}
+
+	changed := dir.setInstruction(instructions)
+	if changed {
+		LogInfo("AgentQA", fmt.Sprintf("Updated instructions for directory '%s'", dirKey),
+			fmt.Sprintf("Version: %d", dir.InstructionVersion))
+	}
+
+	dirCopy := *dir
+	return &dirCopy, changed, nil
+}
+
+// DirectoryDeletion summarizes what DeleteDirectory removed
+type DirectoryDeletion struct {
+	Key             string `json:"key"`
+	Questions       int    `json:"questions_removed"`
+	Unresolved      int    `json:"unresolved_failed"`          // Pending/processing questions failed by the deletion
+	WaiterCancelled string `json:"waiter_cancelled,omitempty"` // Specialist whose wait was cancelled
+}
+
+// DeleteDirectory removes a directory with its question queue and history. Unresolved questions
+// are failed and their waiting askers woken. A directory with a specialist blocked waiting is
+// only deleted with force, which cancels that wait.
+func (r *AgentQARegistry) DeleteDirectory(key string, force bool) (*DirectoryDeletion, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.directories[key] == nil {
+		return nil, fmt.Errorf("directory '%s' not found", key)
+	}
+
+	deletion := &DirectoryDeletion{Key: key}
+
+	if waiter := r.activeWaiters[key]; waiter != nil {
+		if waiter.Context.Err() == nil && !force {
+			return nil, fmt.Errorf("specialist '%s' is waiting for questions in directory '%s' - use force to delete anyway", waiter.Name, key)
+		}
+		if waiter.Cancel != nil {
+			waiter.Cancel()
+		}
+		delete(r.activeWaiters, key)
+		deletion.WaiterCancelled = waiter.Name
+	}
+
+	for _, qa := range r.questionQueues[key] {
+		if qa.Status == QAStatusPending || qa.Status == QAStatusProcessing {
+			qa.Status = QAStatusFailed
+			qa.Error = fmt.Sprintf("directory '%s' was deleted", key)
+			qa.ProcessingTime = time.Since(qa.Timestamp)
+			r.writeAuditRecord(qa)
+			deletion.Unresolved++
+		}
+		delete(r.qaIndex, qa.ID)
+		if cond, exists := r.answerConds[qa.ID]; exists {
+			cond.Broadcast() // Askers still waiting find the question gone
+			delete(r.answerConds, qa.ID)
+		}
+		deletion.Questions++
+	}
+
+	if cond, exists := r.dirConds[key]; exists {
+		cond.Broadcast()
+		delete(r.dirConds, key)
+	}
+	delete(r.questionQueues, key)
+	delete(r.directories, key)
+
+	LogInfo("AgentQA", fmt.Sprintf("Deleted directory '%s'", key),
+		fmt.Sprintf("Questions: %d, unresolved: %d, waiter cancelled: %q", deletion.Questions, deletion.Unresolved, deletion.WaiterCancelled))
+	return deletion, nil
+}
+
+// MaxReplayQuestions caps how many questions replay_questions re-submits at once
+const MaxReplayQuestions = 50
+
+// ReplayedQuestion pairs a replayed question with its original
+type ReplayedQuestion struct {
+	OriginalID string `json:"original_id"`
+	QuestionID string `json:"question_id"`
+	Question   string `json:"question"`
+}
+
+// ReplayQuestions re-submits the text of the directory's most recent completed questions as
+// new questions, oldest first, e.g. to compare a specialist's answers after an update.
+// An empty from keeps each original asker; tag records the original in ReplayOf.
+func (r *AgentQARegistry) ReplayQuestions(key string, count int, from string, tag bool) ([]ReplayedQuestion, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.directories[key] == nil {
+		return nil, fmt.Errorf("directory '%s' not found", key)
+	}
+
+	// Newest first, then flipped so the replays queue up in the original order
+	var originals []*QuestionAnswer
+	queue := r.questionQueues[key]
+	for i := len(queue) - 1; i >= 0 && len(originals) < count; i-- {
+		if queue[i].Status == QAStatusCompleted {
+			originals = append(originals, queue[i])
+		}
+	}
+	if len(originals) == 0 {
+		return nil, fmt.Errorf("directory '%s' has no completed questions to replay", key)
+	}
+	slices.Reverse(originals)
+
+	replayed := make([]ReplayedQuestion, 0, len(originals))
+	for _, original := range originals {
+		replay := &QuestionAnswer{
+			From:       original.From,
+			To:         r.directories[key].Specialty, // Will be updated by specialist who picks it up
+			Question:   original.Question,
+			MaxRetries: DefaultQuestionRetries,
+		}
+		if from != "" {
+			replay.From = from
+		}
+		if tag {
+			replay.ReplayOf = original.ID
+		}
+		r.enqueueQuestion(replay, key)
+		replayed = append(replayed, ReplayedQuestion{OriginalID: original.ID, QuestionID: replay.ID, Question: replay.Question})
+	}
+
+	LogInfo("AgentQA", fmt.Sprintf("Replayed %d questions in directory '%s'", len(replayed), key))
+	return replayed, nil
+}
+
+// askQuestionInternal is the core implementation for submitting questions to specialists.
+// If wait is true, blocks until answer is available (respecting timeout).
+// If wait is false, returns immediately with the question ID.
+// Questions are queued even if no specialist is currently waiting - a specialist can pick it up later -
+// unless enqueue is false, which fails the call instead.
+// A non-empty target directs the question to the specialist with that name; if it is not the
+// directory's active waiter, strict returns an error, otherwise the question goes to anyone.
+func (r *AgentQARegistry) askQuestionInternal(from, specialty, rootDir, question, target string, strict, enqueue, wait bool, timeout time.Duration, retries int) (*QuestionAnswer, error) {
+	r.mutex.Lock()
+
+	// 0. Enforce question size limit before touching any state
+	question, err := r.enforceSizeLimit("question", question, r.maxQuestionBytes)
+	if err != nil {
+		r.mutex.Unlock()
+		return nil, err
+	}
+
+	// 1. Create directory key, falling back to the specialty's global directory
+	// when the project has none of its own
+	dirKey, fallback := r.resolveDirectoryKey(specialty, rootDir)
+	if fallback {
+		LogInfo("AgentQA", fmt.Sprintf("No directory '%s', sending question to global directory '%s'", directoryKey(rootDir, specialty), dirKey))
+		rootDir = GlobalRootDir
+	}
+
+	// 1b. Resolve a directed question against the active waiter
+	// (the waiter entry stays while its specialist is busy answering, so only the name matters)
+	if target != "" {
+		if waiter, exists := r.activeWaiters[dirKey]; !exists || waiter.Name != target {
+			if strict {
+				r.mutex.Unlock()
+				return nil, fmt.Errorf("specialist '%s' is not active in directory '%s'", target, dirKey)
+			}
+			LogInfo("AgentQA", fmt.Sprintf("Specialist '%s' not active in directory '%s', sending question to any specialist", target, dirKey))
+			target = ""
+		}
+	}
+
+	// 1c. Without a live specialist the question either waits in the queue or fails now
+	if _, _, live := r.liveSpecialist(dirKey); !live && !enqueue {
+		r.mutex.Unlock()
+		return nil, fmt.Errorf("no active specialist waiting in directory '%s'", dirKey)
+	}
+	if pending := r.pendingCount(dirKey); pending >= MaxPendingQuestions {
+		r.mutex.Unlock()
+		return nil, fmt.Errorf("directory '%s' already has %d pending questions (max %d)", dirKey, pending, MaxPendingQuestions)
+	}
+
+	// 2-3. Create or get directory and its question queue
+	if _, created := r.ensureDirectory(dirKey, rootDir, specialty, ""); created {
+		LogInfo("AgentQA", fmt.Sprintf("Created directory '%s' for incoming question", dirKey))
+	}
+
+	// 4-7. Create the question, queue it and wake the directory's specialist
+	// A waiting asker's timeout becomes the question's deadline, visible to the specialist
+	var deadline time.Time
+	if wait && timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+	qa := r.enqueueQuestion(&QuestionAnswer{
+		From:       from,
+		To:         specialty, // Will be updated by specialist who picks it up
+		Question:   question,
+		MaxRetries: retries,
+		TargetName: target,
+		Deadline:   deadline,
+	}, dirKey)
 
 	// Log whether there's an active waiter
 	if waiter, exists := r.activeWaiters[dirKey]; exists {
@@ -223,10 +538,111 @@ func (r *AgentQARegistry) askQuestionInternal(from, specialty, rootDir, question
 	return r.waitForAnswer(qa.ID, timeout)
 }
 
+// resolveDirectoryKey returns the directory a question about rootDir goes to: the project's own,
+// or the specialty's global directory when the project has none. Must be called with mutex held.
+func (r *AgentQARegistry) resolveDirectoryKey(specialty, rootDir string) (string, bool) {
+	dirKey := directoryKey(rootDir, specialty)
+	if r.directories[dirKey] == nil && rootDir != GlobalRootDir {
+		if globalKey := directoryKey(GlobalRootDir, specialty); r.directories[globalKey] != nil {
+			return globalKey, true
+		}
+	}
+	return dirKey, false
+}
+
+// SpecialistAvailability describes whether a question asked now would reach a live specialist
+type SpecialistAvailability struct {
+	DirectoryKey     string
+	DirectoryExists  bool
+	RoutedToGlobal   bool   // The project has no directory; questions go to the global one
+	Available        bool   // A specialist is waiting for questions or answering one
+	SpecialistName   string // The active specialist, if any
+	Busy             bool   // The specialist is answering another question and will be back
+	PendingQuestions int
+}
+
+// CheckSpecialist reports whether a specialist is live in the directory ask_specialist would use
+// for specialty and rootDir, without queueing anything
+func (r *AgentQARegistry) CheckSpecialist(specialty, rootDir string) SpecialistAvailability {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	dirKey, fallback := r.resolveDirectoryKey(specialty, rootDir)
+	availability := SpecialistAvailability{
+		DirectoryKey:    dirKey,
+		DirectoryExists: r.directories[dirKey] != nil,
+		RoutedToGlobal:  fallback,
+	}
+
+	availability.SpecialistName, availability.Busy, availability.Available = r.liveSpecialist(dirKey)
+	availability.PendingQuestions = r.pendingCount(dirKey)
+	return availability
+}
+
+// liveSpecialist returns the directory's specialist if it is waiting for questions or busy
+// answering one. Must be called with mutex held.
+func (r *AgentQARegistry) liveSpecialist(dirKey string) (name string, busy, live bool) {
+	waiter := r.activeWaiters[dirKey]
+	if waiter == nil {
+		return "", false, false
+	}
+	for _, qa := range r.questionQueues[dirKey] {
+		if qa.Status == QAStatusProcessing && qa.To == waiter.Name {
+			busy = true
+			break
+		}
+	}
+
+	// The waiter's context ends when get_next_question returns, so a cancelled context only
+	// counts while its specialist is still answering
+	select {
+	case <-waiter.Context.Done():
+		live = busy
+	default:
+		live = true
+	}
+	if !live {
+		return "", false, false
+	}
+	return waiter.Name, busy, true
+}
+
+// pendingCount returns how many questions in the directory wait for a specialist.
+// Must be called with mutex held.
+func (r *AgentQARegistry) pendingCount(dirKey string) int {
+	pending := 0
+	for _, qa := range r.questionQueues[dirKey] {
+		if qa.Status == QAStatusPending {
+			pending++
+		}
+	}
+	return pending
+}
+
+// enqueueQuestion gives qa a new ID and Pending status, appends it to the directory's queue
+// and wakes the specialist waiting there. Must be called with mutex held.
+func (r *AgentQARegistry) enqueueQuestion(qa *QuestionAnswer, dirKey string) *QuestionAnswer {
+	qa.ID = uuid.New().String()
+	qa.Status = QAStatusPending
+	qa.Timestamp = time.Now()
+	qa.DirectoryKey = dirKey
+
+	// Add to index for fast lookup
+	r.qaIndex[qa.ID] = qa
+
+	// Append to queue (NEVER removed - append-only)
+	r.questionQueues[dirKey] = append(r.questionQueues[dirKey], qa)
+
+	// Wake up specialist waiting for THIS directory only
+	r.getDirCond(dirKey).Signal() // Signal, not Broadcast - only one specialist per directory
+	return qa
+}
+
 // waitForAnswer polls for an answer using condition variables
 // Questioners should prefer NO timeout (timeout=0). If timeout is set, it only
 // affects how long we wait - NOT the question status.
-// Question status is ONLY changed by the specialist (Completed/Failed).
+// Question status is changed by the specialist (Completed/Failed), or to Failed once the
+// asker's deadline passes or re-queue retries run out.
 func (r *AgentQARegistry) waitForAnswer(questionID string, timeout time.Duration) (*QuestionAnswer, error) {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
@@ -270,16 +686,17 @@ func (r *AgentQARegistry) waitForAnswer(questionID string, timeout time.Duration
 			return nil, fmt.Errorf("question ID '%s' disappeared", questionID)
 		}
 
-		// Check if answered (only specialist can set these statuses)
+		// Check if answered (set by the specialist, or Failed once re-queue retries are exhausted)
 		if qa.Status == QAStatusCompleted || qa.Status == QAStatusFailed {
 			return qa, nil
 		}
 
 		// Check timeout - DO NOT modify qa.Status!
-		// Status is specialist-only; we just return an error to the caller
+		// This wait's timeout is not the question's deadline; we just return an error to the caller
 		if timeout > 0 && time.Now().After(deadline) {
-			// Return current state with timeout error
-			// Questioner can call GetAnswer later to retrieve late answer
+			// Return current state with timeout error. Without a deadline (asked async or with
+			// no timeout) the questioner can call GetAnswer later; past the deadline the
+			// question fails and a late answer is rejected.
 			return qa, fmt.Errorf("timeout waiting for answer")
 		}
 
@@ -290,7 +707,22 @@ func (r *AgentQARegistry) waitForAnswer(questionID string, timeout time.Duration
 
 // AskQuestion submits a question to a specialist directory and waits for a response
 func (r *AgentQARegistry) AskQuestion(from, specialty, rootDir, question string, timeout time.Duration) (*QuestionAnswer, error) {
-	return r.askQuestionInternal(from, specialty, rootDir, question, true, timeout)
+	return r.askQuestionInternal(from, specialty, rootDir, question, "", false, true, true, timeout, DefaultQuestionRetries)
+}
+
+// AskQuestionWithRetries submits a question with an explicit re-queue budget.
+// If the specialist handling it goes away, the question is re-queued up to retries times
+// so a restarted specialist can pick it up, then fails.
+func (r *AgentQARegistry) AskQuestionWithRetries(from, specialty, rootDir, question string, wait bool, timeout time.Duration, retries int) (*QuestionAnswer, error) {
+	return r.askQuestionInternal(from, specialty, rootDir, question, "", false, true, wait, timeout, retries)
+}
+
+// AskSpecialist submits a question directed at the specialist named target.
+// If that specialist is not the directory's active waiter, strict fails the call;
+// otherwise the question falls back to whichever specialist picks it up.
+// With enqueue false, the call fails when no specialist is live in the directory.
+func (r *AgentQARegistry) AskSpecialist(from, specialty, rootDir, question, target string, strict, enqueue, wait bool, timeout time.Duration, retries int) (*QuestionAnswer, error) {
+	return r.askQuestionInternal(from, specialty, rootDir, question, target, strict, enqueue, wait, timeout, retries)
 }
 
 // WaitForQuestion waits for a question for a specialist (blocking)
@@ -300,7 +732,7 @@ func (r *AgentQARegistry) WaitForQuestion(name, specialty, rootDir, instructions
 
 // WaitForQuestionWithContext waits for a question for a specialist with context cancellation support
 func (r *AgentQARegistry) WaitForQuestionWithContext(ctx context.Context, name, specialty, rootDir, instructions string, timeout time.Duration) (*QuestionAnswer, error) {
-	dirKey := fmt.Sprintf("%s-%s", rootDir, specialty)
+	dirKey := directoryKey(rootDir, specialty)
 
 	r.mutex.Lock()
 
@@ -349,23 +781,9 @@ func (r *AgentQARegistry) WaitForQuestionWithContext(ctx context.Context, name,
 		}
 	}
 
-	// 2. Create or update directory
-	if r.directories[dirKey] == nil {
-		r.directories[dirKey] = &SpecialistDirectory{
-			Key:         dirKey,
-			RootDir:     rootDir,
-			Specialty:   specialty,
-			Instruction: instructions,
-			CreatedAt:   time.Now(),
-		}
+	// 2-3. Create or update directory and its question queue
+	if _, created := r.ensureDirectory(dirKey, rootDir, specialty, instructions); created {
 		LogInfo("AgentQA", fmt.Sprintf("Created new directory '%s'", dirKey))
-	} else if instructions != "" {
-		r.directories[dirKey].Instruction = instructions
-	}
-
-	// 3. Initialize question queue if needed
-	if r.questionQueues[dirKey] == nil {
-		r.questionQueues[dirKey] = make([]*QuestionAnswer, 0)
 	}
 
 	// 4. Register as active waiter (only if not already registered as same name)
@@ -451,11 +869,18 @@ func (r *AgentQARegistry) WaitForQuestionWithContext(ctx context.Context, name,
 		// Queue is APPEND-ONLY - never remove entries!
 		var foundQuestion *QuestionAnswer
 		if !hasInFlightQuestion {
+			now := time.Now()
 			for _, qa := range r.questionQueues[dirKey] {
-				if qa.Status == QAStatusPending {
+				if qa.Status == QAStatusPending && qa.askerGaveUp(now) {
+					// Nobody is waiting for this answer any more - don't hand it out
+					r.failTimedOutQuestion(qa, "asker timed out before a specialist picked the question up")
+					continue
+				}
+				if qa.Status == QAStatusPending && (qa.TargetName == "" || qa.TargetName == name) {
 					// Take this question (mark as Processing, don't remove from queue)
 					qa.Status = QAStatusProcessing
 					qa.To = name
+					r.startProcessingTimer(qa, now)
 					foundQuestion = qa
 					break // FIFO: take earliest pending
 				}
@@ -510,11 +935,9 @@ func (r *AgentQARegistry) recoverOrphanedQuestions(dirKey, previousSpecialistNam
 	recoveredCount := 0
 	for _, qa := range r.questionQueues[dirKey] {
 		if qa.Status == QAStatusProcessing && qa.To == previousSpecialistName {
-			// Reset to pending - DO NOT re-enqueue (it's already in the queue)
-			qa.Status = QAStatusPending
-			qa.To = ""
-			recoveredCount++
-			LogInfo("AgentQA", fmt.Sprintf("Recovered orphaned question %s for directory '%s'", qa.ID, dirKey))
+			if r.requeueAbandonedQuestion(qa, "went away without answering") {
+				recoveredCount++
+			}
 		}
 	}
 	if recoveredCount > 0 {
@@ -522,6 +945,64 @@ func (r *AgentQARegistry) recoverOrphanedQuestions(dirKey, previousSpecialistNam
 	}
 }
 
+// requeueAbandonedQuestion puts a question whose specialist went away back to Pending,
+// or fails it once its retry budget is exhausted so the asker stops waiting. why says what
+// the specialist did, for the error. Returns true if the question was re-queued. Called while holding mutex.
+func (r *AgentQARegistry) requeueAbandonedQuestion(qa *QuestionAnswer, why string) bool {
+	previousSpecialist := qa.To
+	qa.AnswerBy = time.Time{}
+
+	if qa.RetryCount >= qa.MaxRetries {
+		qa.Status = QAStatusFailed
+		qa.Error = fmt.Sprintf("specialist '%s' %s (retries exhausted: %d)", previousSpecialist, why, qa.RetryCount)
+		qa.ProcessingTime = time.Since(qa.Timestamp)
+		r.writeAuditRecord(qa)
+		if answerCond := r.answerConds[qa.ID]; answerCond != nil {
+			answerCond.Broadcast()
+		}
+		LogWarn("AgentQA", fmt.Sprintf("Question %s failed after %d retries", qa.ID, qa.RetryCount),
+			fmt.Sprintf("Directory: %s, Specialist: %s", qa.DirectoryKey, previousSpecialist))
+		return false
+	}
+
+	// Reset to pending - DO NOT re-enqueue (it's already in the queue)
+	// A directed question is opened up to any specialist once its target has gone away
+	qa.Status = QAStatusPending
+	qa.To = ""
+	qa.TargetName = ""
+	qa.RetryCount++
+	r.getDirCond(qa.DirectoryKey).Signal()
+	LogInfo("AgentQA", fmt.Sprintf("Recovered orphaned question %s for directory '%s'", qa.ID, qa.DirectoryKey),
+		fmt.Sprintf("Previous specialist: %s (%s), Retry: %d/%d", previousSpecialist, why, qa.RetryCount, qa.MaxRetries))
+	return true
+}
+
+// startProcessingTimer gives the specialist that just claimed qa until the processing timeout
+// to answer; a question still held by that claim then is re-queued. Called while holding mutex.
+func (r *AgentQARegistry) startProcessingTimer(qa *QuestionAnswer, now time.Time) {
+	if r.processingTimeout <= 0 {
+		return
+	}
+	timeout := r.processingTimeout
+	answerBy := now.Add(timeout)
+	qa.AnswerBy = answerBy
+
+	time.AfterFunc(timeout, func() {
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+
+		// Answered, failed, or re-queued and claimed again since
+		if qa.Status != QAStatusProcessing || !qa.AnswerBy.Equal(answerBy) {
+			return
+		}
+		LogWarn("AgentQA", fmt.Sprintf("Specialist '%s' did not answer question %s within %s", qa.To, qa.ID, timeout),
+			fmt.Sprintf("Directory: %s", qa.DirectoryKey))
+		if r.requeueAbandonedQuestion(qa, fmt.Sprintf("did not answer within %s", timeout)) {
+			qa.RequeueCount++
+		}
+	})
+}
+
 // AnswerQuestion provides an answer to a question. A question can only be answered once and only once.
 func (r *AgentQARegistry) AnswerQuestion(questionID, answer string, err error) error {
 	r.mutex.Lock()
@@ -542,6 +1023,17 @@ func (r *AgentQARegistry) AnswerQuestion(questionID, answer string, err error) e
 		return fmt.Errorf("question ID '%s' has already failed and cannot be answered", questionID)
 	}
 
+	if qa.askerGaveUp(time.Now()) {
+		r.failTimedOutQuestion(qa, "asker timed out before the answer arrived")
+		return fmt.Errorf("question ID '%s' expired: the asker stopped waiting at %s", questionID, qa.Deadline.Format(time.RFC3339))
+	}
+
+	// Enforce answer size limit (rejection leaves the question answerable)
+	answer, sizeErr := r.enforceSizeLimit("answer", answer, r.maxAnswerBytes)
+	if sizeErr != nil {
+		return sizeErr
+	}
+
 	// Update state (only specialist can change status)
 	qa.ProcessingTime = time.Since(qa.Timestamp)
 
@@ -553,6 +1045,7 @@ func (r *AgentQARegistry) AnswerQuestion(questionID, answer string, err error) e
 		qa.Answer = answer
 		qa.Error = "" // Clear any previous error
 	}
+	r.writeAuditRecord(qa)
 
 	// Wake up ALL questioners waiting for THIS answer
 	// (Use Broadcast because multiple GetAnswer calls may be waiting on same question)
@@ -565,6 +1058,20 @@ func (r *AgentQARegistry) AnswerQuestion(questionID, answer string, err error) e
 	return nil
 }
 
+// failTimedOutQuestion fails a question whose asker stopped waiting, waking anyone still
+// polling it with get_answer. Must be called with mutex held.
+func (r *AgentQARegistry) failTimedOutQuestion(qa *QuestionAnswer, reason string) {
+	qa.Status = QAStatusFailed
+	qa.Error = reason
+	qa.ProcessingTime = time.Since(qa.Timestamp)
+	r.writeAuditRecord(qa)
+
+	if answerCond := r.answerConds[qa.ID]; answerCond != nil {
+		answerCond.Broadcast()
+	}
+	LogInfo("AgentQA", fmt.Sprintf("Question %s failed: %s", qa.ID, reason))
+}
+
 // GetQA returns a specific Q&A entry
 func (r *AgentQARegistry) GetQA(id string) *QuestionAnswer {
 	r.mutex.Lock()
@@ -593,7 +1100,7 @@ func (r *AgentQARegistry) GetAllQAs() []*QuestionAnswer {
 
 // AskQuestionAsync submits a question to a specialist and returns immediately with question ID
 func (r *AgentQARegistry) AskQuestionAsync(from, specialty, rootDir, question string) (*QuestionAnswer, error) {
-	return r.askQuestionInternal(from, specialty, rootDir, question, false, 0)
+	return r.askQuestionInternal(from, specialty, rootDir, question, "", false, true, false, 0, DefaultQuestionRetries)
 }
 
 // GetAnswer retrieves the answer for a previously asked question
@@ -602,6 +1109,35 @@ func (r *AgentQARegistry) GetAnswer(questionID string, timeout time.Duration) (*
 	return r.waitForAnswer(questionID, timeout)
 }
 
+// GetAnswers waits for several questions in parallel under one shared deadline (timeout 0 = no timeout).
+// Results and errors are returned in the order of questionIDs.
+func (r *AgentQARegistry) GetAnswers(questionIDs []string, timeout time.Duration) ([]*QuestionAnswer, []error) {
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+
+	qas := make([]*QuestionAnswer, len(questionIDs))
+	errs := make([]error, len(questionIDs))
+
+	var wg sync.WaitGroup
+	for i, questionID := range questionIDs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			remaining := time.Duration(0)
+			if !deadline.IsZero() {
+				// A non-positive remainder would mean "no timeout", so keep at least 1ns
+				remaining = max(time.Until(deadline), time.Nanosecond)
+			}
+			qas[i], errs[i] = r.waitForAnswer(questionID, remaining)
+		}()
+	}
+	wg.Wait()
+
+	return qas, errs
+}
+
 // GetQAsByDirectory returns all Q&A entries for a specific directory, sorted by timestamp (newest first)
 func (r *AgentQARegistry) GetQAsByDirectory(key string) []*QuestionAnswer {
 	r.mutex.Lock()
@@ -857,9 +1393,10 @@ func (r *AgentQARegistry) checkSystemHealth() {
 					LogWarn("AgentQA", "Specialist still active but not responding",
 						fmt.Sprintf("Specialist: %s, Last seen: %v ago", qa.To, now.Sub(waiter.LastSeen)))
 				} else {
-					// Specialist is gone, this question is orphaned
+					// Specialist is gone, this question is orphaned - re-queue it (bounded by retries)
 					LogWarn("AgentQA", "Question is orphaned - specialist no longer active",
 						fmt.Sprintf("Question: %s, Missing specialist: %s", qa.ID, qa.To))
+					r.requeueAbandonedQuestion(qa, "went away without answering")
 				}
 			}
 		}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// QAAuditRecord is one line of the --qa-audit-log NDJSON file, written when a question is
+// answered or fails
+type QAAuditRecord struct {
+	ID               string    `json:"id"`
+	From             string    `json:"from"`
+	To               string    `json:"to"`
+	Directory        string    `json:"directory"`
+	Question         string    `json:"question"`
+	Answer           string    `json:"answer,omitempty"`
+	Error            string    `json:"error,omitempty"`
+	Status           QAStatus  `json:"status"`
+	ProcessingTime   string    `json:"processing_time"`
+	ProcessingTimeMs int64     `json:"processing_time_ms"`
+	AskedAt          time.Time `json:"asked_at"`
+	Timestamp        time.Time `json:"timestamp"` // When the outcome was recorded
+}
+
+// openQAAuditLog opens the audit log for appending, creating it if needed
+func openQAAuditLog(path string) (*os.File, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open Q&A audit log: %v", err)
+	}
+	return file, nil
+}
+
+// SetAuditLog makes the registry append a record to w for every answered or failed question
+// (nil disables auditing)
+func (r *AgentQARegistry) SetAuditLog(w io.Writer) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.auditLog = w
+}
+
+// writeAuditRecord appends the outcome of qa to the audit log, if one is configured.
+// Must be called with mutex held, which also keeps records from interleaving.
+func (r *AgentQARegistry) writeAuditRecord(qa *QuestionAnswer) {
+	if r.auditLog == nil {
+		return
+	}
+
+	record := QAAuditRecord{
+		ID:               qa.ID,
+		From:             qa.From,
+		To:               qa.To,
+		Directory:        qa.DirectoryKey,
+		Question:         qa.Question,
+		Answer:           qa.Answer,
+		Error:            qa.Error,
+		Status:           qa.Status,
+		ProcessingTime:   qa.ProcessingTime.String(),
+		ProcessingTimeMs: qa.ProcessingTime.Milliseconds(),
+		AskedAt:          qa.Timestamp,
+		Timestamp:        time.Now(),
+	}
+	line, err := json.Marshal(record)
+	if err != nil {
+		LogError("AgentQA", "Failed to encode audit record", fmt.Sprintf("Question: %s, error: %v", qa.ID, err))
+		return
+	}
+
+	// One write per record, so an O_APPEND file never gets a partial line from us
+	if _, err := r.auditLog.Write(append(line, '\n')); err != nil {
+		LogError("AgentQA", "Failed to write audit record", fmt.Sprintf("Question: %s, error: %v", qa.ID, err))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -47,8 +48,35 @@ func handleAnswerQuestion(ctx context.Context, request mcp.CallToolRequest) (*mc
 	return mcp.NewToolResultText(string(resultBytes)), nil
 }
 
+// nextQuestionResult builds the get_next_question result for a question. Questions from a waiting
+// asker carry its deadline so the specialist knows how long it has to answer.
+func nextQuestionResult(qa *QuestionAnswer) map[string]any {
+	result := map[string]any{
+		"question_id": qa.ID,
+		"from":        qa.From,
+		"question":    qa.Question,
+		"timestamp":   qa.Timestamp.Format(time.RFC3339),
+	}
+	if qa.ReplayOf != "" {
+		result["replay_of"] = qa.ReplayOf
+	}
+	if !qa.Deadline.IsZero() {
+		result["deadline"] = qa.Deadline.Format(time.RFC3339)
+		result["remaining_ms"] = max(time.Until(qa.Deadline).Milliseconds(), 0)
+	}
+	if !qa.AnswerBy.IsZero() {
+		// After this the question is re-queued for another specialist (--qa-processing-timeout)
+		result["answer_by"] = qa.AnswerBy.Format(time.RFC3339)
+	}
+	return result
+}
+
 // handleGetNextQuestion waits for and retrieves the next question for this specialist
 func handleGetNextQuestion(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if refused := maintenance.refuse("questions"); refused != nil {
+		return refused, nil
+	}
+
 	// Log full request for debugging
 	requestJSON, _ := json.Marshal(request.Params.Arguments)
 	LogInfo("AgentQA", "get_next_question called", fmt.Sprintf("Request: %s", string(requestJSON)))
@@ -66,10 +94,10 @@ func handleGetNextQuestion(ctx context.Context, request mcp.CallToolRequest) (*m
 		return mcp.NewToolResultError("Missing or invalid 'specialty' argument"), nil
 	}
 
-	rootDir, err := request.RequireString("root_dir")
+	rootDir, err := specialistRootDir(request)
 	if err != nil {
 		LogError("AgentQA", "get_next_question missing root_dir", fmt.Sprintf("Request: %s", string(requestJSON)))
-		return mcp.NewToolResultError("Missing or invalid 'root_dir' argument"), nil
+		return mcp.NewToolResultError(err.Error()), nil
 	}
 
 	// Get optional instructions
@@ -113,14 +141,7 @@ func handleGetNextQuestion(ctx context.Context, request mcp.CallToolRequest) (*m
 			return mcp.NewToolResultError("No questions available"), nil
 		}
 
-		result := map[string]any{
-			"question_id": qa.ID,
-			"from":        qa.From,
-			"question":    qa.Question,
-			"timestamp":   qa.Timestamp.Format(time.RFC3339),
-		}
-
-		resultBytes, _ := json.Marshal(result)
+		resultBytes, _ := json.Marshal(nextQuestionResult(qa))
 		return mcp.NewToolResultText(string(resultBytes)), nil
 	}
 
@@ -144,14 +165,7 @@ func handleGetNextQuestion(ctx context.Context, request mcp.CallToolRequest) (*m
 
 	LogInfo("AgentQA", "Question received", fmt.Sprintf("QuestionID: %s, From: %s", qa.ID, qa.From))
 
-	result := map[string]any{
-		"question_id": qa.ID,
-		"from":        qa.From,
-		"question":    qa.Question,
-		"timestamp":   qa.Timestamp.Format(time.RFC3339),
-	}
-
-	resultBytes, err := json.Marshal(result)
+	resultBytes, err := json.Marshal(nextQuestionResult(qa))
 	if err != nil {
 		LogError("AgentQA", "Failed to marshal response", fmt.Sprintf("Error: %v", err))
 		return mcp.NewToolResultError("Failed to marshal response"), nil
@@ -161,8 +175,153 @@ func handleGetNextQuestion(ctx context.Context, request mcp.CallToolRequest) (*m
 	return mcp.NewToolResultText(string(resultBytes)), nil
 }
 
+// handleRegisterSpecialist creates or updates a specialist directory without blocking
+func handleRegisterSpecialist(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	specialty, err := request.RequireString("specialty")
+	if err != nil {
+		return mcp.NewToolResultError("Missing or invalid 'specialty' argument"), nil
+	}
+
+	rootDir, err := specialistRootDir(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// Get optional instructions
+	instructions := ""
+	if arguments, ok := request.Params.Arguments.(map[string]any); ok {
+		if inst, exists := arguments["instructions"]; exists {
+			if instStr, ok := inst.(string); ok {
+				instructions = instStr
+			}
+		}
+	}
+
+	dir, created := agentQARegistry.RegisterDirectory(specialty, rootDir, instructions)
+
+	status := "updated"
+	if created {
+		status = "registered"
+	}
+
+	result := map[string]any{
+		"status":              status,
+		"key":                 dir.Key,
+		"root_dir":            dir.RootDir,
+		"specialty":           dir.Specialty,
+		"instruction":         dir.Instruction,
+		"instruction_version": dir.InstructionVersion,
+		"created_at":          dir.CreatedAt.Format(time.RFC3339),
+		"global":              dir.RootDir == GlobalRootDir,
+	}
+
+	resultBytes, _ := json.Marshal(result)
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// specialistRootDir returns the root_dir argument, or GlobalRootDir when global is true
+func specialistRootDir(request mcp.CallToolRequest) (string, error) {
+	if getBoolArg(request, "global", false) {
+		return GlobalRootDir, nil
+	}
+	rootDir, err := request.RequireString("root_dir")
+	if err != nil || rootDir == "" {
+		return "", fmt.Errorf("Missing or invalid 'root_dir' argument (or set global: true)")
+	}
+	return rootDir, nil
+}
+
+// handleUpdateSpecialistInstructions replaces a directory's instructions without an active waiter
+func handleUpdateSpecialistInstructions(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	specialty, err := request.RequireString("specialty")
+	if err != nil {
+		return mcp.NewToolResultError("Missing or invalid 'specialty' argument"), nil
+	}
+
+	rootDir, err := request.RequireString("root_dir")
+	if err != nil {
+		return mcp.NewToolResultError("Missing or invalid 'root_dir' argument"), nil
+	}
+
+	// Empty instructions are allowed and clear the guidance
+	instructions, err := request.RequireString("instructions")
+	if err != nil {
+		return mcp.NewToolResultError("Missing or invalid 'instructions' argument"), nil
+	}
+
+	dir, changed, err := agentQARegistry.UpdateInstructions(specialty, rootDir, instructions)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	result := map[string]any{
+		"key":                 dir.Key,
+		"instruction":         dir.Instruction,
+		"instruction_version": dir.InstructionVersion,
+		"changed":             changed,
+	}
+	if !dir.UpdatedAt.IsZero() {
+		result["updated_at"] = dir.UpdatedAt.Format(time.RFC3339)
+	}
+
+	resultBytes, _ := json.Marshal(result)
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// handleDeleteDirectory removes a specialist directory and all of its Q&As
+func handleDeleteDirectory(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	key, err := request.RequireString("key")
+	if err != nil || key == "" {
+		return mcp.NewToolResultError("Missing or invalid 'key' argument"), nil
+	}
+
+	deletion, err := agentQARegistry.DeleteDirectory(key, getBoolArg(request, "force", false))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	resultBytes, _ := json.Marshal(deletion)
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// handleReplayQuestions re-asks a directory's most recent completed questions
+func handleReplayQuestions(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	key, err := request.RequireString("key")
+	if err != nil || key == "" {
+		return mcp.NewToolResultError("Missing or invalid 'key' argument"), nil
+	}
+
+	count := getIntArg(request, "count", 0)
+	if count < 1 || count > MaxReplayQuestions {
+		return mcp.NewToolResultError(fmt.Sprintf("count must be between 1 and %d", MaxReplayQuestions)), nil
+	}
+
+	replayed, err := agentQARegistry.ReplayQuestions(key, count, getStringArg(request, "from", ""), getBoolArg(request, "tag_as_replay", true))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	questionIDs := make([]string, len(replayed))
+	for i, replay := range replayed {
+		questionIDs[i] = replay.QuestionID
+	}
+
+	result := map[string]any{
+		"key":          key,
+		"replayed":     len(replayed),
+		"question_ids": questionIDs,
+		"questions":    replayed,
+	}
+	resultBytes, _ := json.Marshal(result)
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
 // handleAskSpecialist asks a question to a specialist
 func handleAskSpecialist(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if refused := maintenance.refuse("questions"); refused != nil {
+		return refused, nil
+	}
+
 	specialty, err := request.RequireString("specialty")
 	if err != nil {
 		return mcp.NewToolResultError("Missing or invalid 'specialty' argument"), nil
@@ -201,6 +360,35 @@ func handleAskSpecialist(ctx context.Context, request mcp.CallToolRequest) (*mcp
 		}
 	}
 
+	// Get retries parameter (re-queue budget if the specialist goes away)
+	retries := DefaultQuestionRetries
+	if arguments, ok := request.Params.Arguments.(map[string]any); ok {
+		if r, exists := arguments["retries"]; exists {
+			if rFloat, ok := r.(float64); ok && rFloat >= 0 {
+				retries = min(int(rFloat), MaxQuestionRetries)
+			}
+		}
+	}
+
+	// Get specialist_name / strict parameters (direct the question at one specialist)
+	specialistName := ""
+	strict := false
+	if arguments, ok := request.Params.Arguments.(map[string]any); ok {
+		if n, exists := arguments["specialist_name"]; exists {
+			if nStr, ok := n.(string); ok {
+				specialistName = strings.TrimSpace(nStr)
+			}
+		}
+		if st, exists := arguments["strict"]; exists {
+			if stBool, ok := st.(bool); ok {
+				strict = stBool
+			}
+		}
+	}
+
+	// Queue the question for a later specialist (default) or fail when none is live
+	enqueue := getBoolArg(request, "enqueue_if_no_waiter", true)
+
 	// Extract session ID for "from" field
 	sessionID := ExtractSessionFromContext(ctx)
 	from := fmt.Sprintf("Session %s", sessionID)
@@ -211,21 +399,18 @@ func handleAskSpecialist(ctx context.Context, request mcp.CallToolRequest) (*mcp
 	var qa *QuestionAnswer
 	var err2 error
 
-	if !wait {
-		// Non-blocking mode: submit question and return immediately
-		qa, err2 = agentQARegistry.AskQuestionAsync(from, specialty, rootDir, question)
-	} else {
-		// Blocking mode: wait for answer
-		qa, err2 = agentQARegistry.AskQuestion(from, specialty, rootDir, question, timeout)
-	}
+	// Blocking mode waits for the answer; non-blocking returns immediately with the question ID
+	qa, err2 = agentQARegistry.AskSpecialist(from, specialty, rootDir, question, specialistName, strict, enqueue, wait, timeout, retries)
 
 	if err2 != nil {
 		// Still return the Q&A info even on error
 		if qa != nil {
 			result := map[string]any{
-				"question_id": qa.ID,
-				"status":      string(qa.Status),
-				"error":       err2.Error(),
+				"question_id":   qa.ID,
+				"status":        string(qa.Status),
+				"retry_count":   qa.RetryCount,
+				"requeue_count": qa.RequeueCount,
+				"error":         err2.Error(),
 			}
 			resultBytes, _ := json.Marshal(result)
 			return mcp.NewToolResultText(string(resultBytes)), nil
@@ -234,8 +419,13 @@ func handleAskSpecialist(ctx context.Context, request mcp.CallToolRequest) (*mcp
 	}
 
 	result := map[string]any{
-		"question_id": qa.ID,
-		"status":      string(qa.Status),
+		"question_id":   qa.ID,
+		"status":        string(qa.Status),
+		"retry_count":   qa.RetryCount,
+		"requeue_count": qa.RequeueCount,
+	}
+	if qa.DirectoryKey == directoryKey(GlobalRootDir, specialty) && rootDir != GlobalRootDir {
+		result["routed_to_global"] = true
 	}
 
 	// Only include answer if we waited for it and it's available
@@ -244,6 +434,43 @@ func handleAskSpecialist(ctx context.Context, request mcp.CallToolRequest) (*mcp
 		result["processing_time"] = qa.ProcessingTime.String()
 	}
 
+	// Surface why a waited-for question failed (e.g. retries exhausted)
+	if wait && qa.Status == QAStatusFailed && qa.Error != "" {
+		result["error"] = qa.Error
+	}
+
+	resultBytes, _ := json.Marshal(result)
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// handleIsSpecialistAvailable reports whether ask_specialist would reach a live specialist, so
+// clients can avoid blocking on a directory nobody is serving
+func handleIsSpecialistAvailable(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	specialty, err := request.RequireString("specialty")
+	if err != nil {
+		return mcp.NewToolResultError("Missing or invalid 'specialty' argument"), nil
+	}
+	rootDir, err := request.RequireString("root_dir")
+	if err != nil {
+		return mcp.NewToolResultError("Missing or invalid 'root_dir' argument"), nil
+	}
+
+	availability := agentQARegistry.CheckSpecialist(specialty, rootDir)
+
+	result := map[string]any{
+		"available":         availability.Available,
+		"directory":         availability.DirectoryKey,
+		"directory_exists":  availability.DirectoryExists,
+		"pending_questions": availability.PendingQuestions,
+	}
+	if availability.RoutedToGlobal {
+		result["routed_to_global"] = true
+	}
+	if availability.Available {
+		result["specialist_name"] = availability.SpecialistName
+		result["busy"] = availability.Busy
+	}
+
 	resultBytes, _ := json.Marshal(result)
 	return mcp.NewToolResultText(string(resultBytes)), nil
 }
@@ -265,14 +492,22 @@ func handleListSpecialists(ctx context.Context, request mcp.CallToolRequest) (*m
 			}
 		}
 
-		result = append(result, map[string]any{
-			"key":               dir.Key,
-			"root_dir":          dir.RootDir,
-			"specialty":         dir.Specialty,
-			"instruction":       dir.Instruction,
-			"pending_questions": pendingCount,
-			"created_at":        dir.CreatedAt.Format(time.RFC3339),
-		})
+		entry := map[string]any{
+			"key":                 dir.Key,
+			"root_dir":            dir.RootDir,
+			"specialty":           dir.Specialty,
+			"instruction":         dir.Instruction,
+			"instruction_version": dir.InstructionVersion,
+			"pending_questions":   pendingCount,
+			"created_at":          dir.CreatedAt.Format(time.RFC3339),
+		}
+		if dir.RootDir == GlobalRootDir {
+			entry["global"] = true
+		}
+		if !dir.UpdatedAt.IsZero() {
+			entry["updated_at"] = dir.UpdatedAt.Format(time.RFC3339)
+		}
+		result = append(result, entry)
 	}
 
 	resultBytes, _ := json.Marshal(result)
@@ -300,42 +535,100 @@ func handleGetAnswer(ctx context.Context, request mcp.CallToolRequest) (*mcp.Cal
 	}
 
 	qa, err := agentQARegistry.GetAnswer(questionID, timeout)
-	if err != nil {
-		// Still return the Q&A info even on error
-		if qa != nil {
-			result := map[string]any{
-				"question_id":     qa.ID,
-				"question":        qa.Question,
-				"status":          string(qa.Status),
-				"timestamp":       qa.Timestamp.Format(time.RFC3339),
-				"processing_time": qa.ProcessingTime.String(),
-				"error":           err.Error(),
-			}
-			if qa.Answer != "" {
-				result["answer"] = qa.Answer
-			}
-			resultBytes, _ := json.Marshal(result)
-			return mcp.NewToolResultText(string(resultBytes)), nil
-		}
+	if err != nil && qa == nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
+	// Still return the Q&A info even on error
+	resultBytes, _ := json.Marshal(answerResult(qa, err))
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// answerResult describes a question's current state for get_answer / get_answers.
+// A wait error (e.g. timeout) takes the place of the question's own error.
+func answerResult(qa *QuestionAnswer, err error) map[string]any {
 	result := map[string]any{
 		"question_id":     qa.ID,
 		"question":        qa.Question,
 		"status":          string(qa.Status),
 		"timestamp":       qa.Timestamp.Format(time.RFC3339),
 		"processing_time": qa.ProcessingTime.String(),
+		"retry_count":     qa.RetryCount,
+		"requeue_count":   qa.RequeueCount,
 	}
 
 	if qa.Answer != "" {
 		result["answer"] = qa.Answer
 	}
+	if qa.ReplayOf != "" {
+		result["replay_of"] = qa.ReplayOf
+	}
 
-	if qa.Error != "" {
+	if err != nil {
+		result["error"] = err.Error()
+	} else if qa.Error != "" {
 		result["error"] = qa.Error
 	}
 
+	return result
+}
+
+// MaxBatchQuestionIDs caps how many questions get_answers accepts at once
+const MaxBatchQuestionIDs = 100
+
+// handleGetAnswers collects the answers of several questions, waiting for all of them under one deadline
+func handleGetAnswers(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	questionIDs := getStringArrayArg(request, "question_ids")
+	if len(questionIDs) == 0 {
+		return mcp.NewToolResultError("Missing or invalid 'question_ids' argument (non-empty array of question IDs)"), nil
+	}
+	if len(questionIDs) > MaxBatchQuestionIDs {
+		return mcp.NewToolResultError(fmt.Sprintf("Too many question IDs: %d (max %d)", len(questionIDs), MaxBatchQuestionIDs)), nil
+	}
+
+	timeout := time.Duration(getInt64Arg(request, "timeout", 0)) * time.Millisecond
+	if timeout < 0 {
+		return mcp.NewToolResultError("Timeout cannot be negative"), nil
+	}
+
+	// Without waiting, report the current state of each question
+	var qas []*QuestionAnswer
+	var errs []error
+	if getBoolArg(request, "wait", true) {
+		qas, errs = agentQARegistry.GetAnswers(questionIDs, timeout)
+	} else {
+		qas = make([]*QuestionAnswer, len(questionIDs))
+		errs = make([]error, len(questionIDs))
+		for i, questionID := range questionIDs {
+			if qas[i] = agentQARegistry.GetQA(questionID); qas[i] == nil {
+				errs[i] = fmt.Errorf("question ID '%s' not found", questionID)
+			}
+		}
+	}
+
+	answers := make([]map[string]any, 0, len(questionIDs))
+	resolved := 0
+	for i, qa := range qas {
+		if qa == nil {
+			answers = append(answers, map[string]any{
+				"question_id": questionIDs[i],
+				"error":       errs[i].Error(),
+			})
+			continue
+		}
+		if qa.Status == QAStatusCompleted || qa.Status == QAStatusFailed {
+			resolved++
+		}
+		answers = append(answers, answerResult(qa, errs[i]))
+	}
+
+	result := map[string]any{
+		"answers":      answers,
+		"resolved":     resolved,
+		"unresolved":   len(questionIDs) - resolved,
+		"all_resolved": resolved == len(questionIDs),
+	}
+
 	resultBytes, _ := json.Marshal(result)
 	return mcp.NewToolResultText(string(resultBytes)), nil
 }
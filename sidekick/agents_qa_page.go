@@ -14,6 +14,7 @@ import (
 type AgentsQAPageView struct {
 	tuiApp               *TUIApp
 	view                 *tview.Flex
+	content              *tview.Flex // Table and detail view
 	qaTable              *tview.Table
 	detailView           *tview.TextView
 	statusBar            *tview.TextView
@@ -24,8 +25,15 @@ type AgentsQAPageView struct {
 	lastSpecialistStatus map[string]string            // Cache for specialist status tracking
 	currentDetailID      string
 	isInitialized        bool
+	filterInput          *tview.InputField
+	filterVisible        bool   // The filter input is shown and has focus
+	filterText           string // Active row filter (empty = show everything)
+	pendingYank          bool   // "y" was pressed; q or a completes the copy
 }
 
+// statusMessageDuration is how long a status bar message replaces the key hints
+const statusMessageDuration = 3 * time.Second
+
 // NewAgentsQAPageView creates a new agents Q&A page view
 func NewAgentsQAPageView(tuiApp *TUIApp) *AgentsQAPageView {
 	p := &AgentsQAPageView{
@@ -33,6 +41,7 @@ func NewAgentsQAPageView(tuiApp *TUIApp) *AgentsQAPageView {
 		qaTable:              tview.NewTable(),
 		detailView:           tview.NewTextView(),
 		statusBar:            tview.NewTextView(),
+		filterInput:          tview.NewInputField(),
 		selectedRow:          0,
 		focusedItem:          0,
 		lastQACount:          0,
@@ -44,6 +53,7 @@ func NewAgentsQAPageView(tuiApp *TUIApp) *AgentsQAPageView {
 	p.setupTable()
 	p.setupDetailView()
 	p.setupStatusBar()
+	p.setupFilterInput()
 	p.setupLayout()
 	p.Refresh()
 
@@ -77,21 +87,39 @@ func (p *AgentsQAPageView) setupDetailView() {
 // setupStatusBar configures the status bar
 func (p *AgentsQAPageView) setupStatusBar() {
 	p.statusBar.SetBorder(true).SetTitle(" Controls ").SetTitleAlign(tview.AlignLeft)
-	p.statusBar.SetText("[yellow]↑↓[white]: Navigate | [yellow]Enter[white]: View Details | [yellow]Tab[white]: Switch Focus | [yellow]Q[white]: Quit\n[grey]Pages: [yellow]1[white]: Processes | [yellow]2[white]: Notifications | [yellow]3[white]: Agents Q&A | [yellow]4[white]: Logs | [yellow]5[white]: Features[grey]")
+	p.statusBar.SetText(statusBarText(AgentsQAPage))
 	p.statusBar.SetTextAlign(tview.AlignCenter)
 	p.statusBar.SetDynamicColors(true)
 }
 
+// setupFilterInput configures the row filter input shown with /
+func (p *AgentsQAPageView) setupFilterInput() {
+	p.filterInput.SetBorder(true).SetTitle(" Filter (from, question, status or question ID) ").SetTitleAlign(tview.AlignLeft)
+	p.filterInput.SetBorderPadding(0, 0, 1, 1)
+	p.filterInput.SetFieldBackgroundColor(tcell.ColorDarkSlateGray)
+	p.filterInput.SetPlaceholder("Enter applies, empty clears, Esc cancels")
+	p.filterInput.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEnter:
+			text := p.filterInput.GetText()
+			p.hideFilterInput()
+			p.applyFilter(text)
+		case tcell.KeyEsc:
+			p.hideFilterInput()
+		}
+	})
+}
+
 // setupLayout creates the main layout
 func (p *AgentsQAPageView) setupLayout() {
 	// Main layout - 2 columns: table (60%) and detail view (40%)
-	mainContent := tview.NewFlex().SetDirection(tview.FlexColumn).
+	p.content = tview.NewFlex().SetDirection(tview.FlexColumn).
 		AddItem(p.qaTable, 0, 3, true).
 		AddItem(p.detailView, 0, 2, false)
 
 	// Vertical layout with status bar
 	p.view = tview.NewFlex().SetDirection(tview.FlexRow).
-		AddItem(mainContent, 0, 1, true).
+		AddItem(p.content, 0, 1, true).
 		AddItem(p.statusBar, 4, 0, false)
 
 	// Set up global key handlers
@@ -100,6 +128,11 @@ func (p *AgentsQAPageView) setupLayout() {
 
 // handleGlobalKeys handles global key events for this page
 func (p *AgentsQAPageView) handleGlobalKeys(event *tcell.EventKey) *tcell.EventKey {
+	// The filter input handles Enter/Esc via its DoneFunc
+	if p.filterVisible {
+		return event
+	}
+
 	switch event.Key() {
 	case tcell.KeyTab:
 		p.switchFocus()
@@ -122,10 +155,149 @@ func (p *AgentsQAPageView) handleTableKeys(event *tcell.EventKey) *tcell.EventKe
 	case tcell.KeyEnter:
 		p.showSelectedDetails()
 		return nil
+	case tcell.KeyRune:
+		if p.handleYankKeys(event) {
+			return nil
+		}
+		if event.Rune() == '/' {
+			p.showFilterInput()
+			return nil
+		}
 	}
 	return event
 }
 
+// handleYankKeys handles the two-key copy shortcuts: yq copies the question, ya the answer.
+// Returns true if the key was consumed.
+func (p *AgentsQAPageView) handleYankKeys(event *tcell.EventKey) bool {
+	if event.Key() != tcell.KeyRune {
+		p.pendingYank = false
+		return false
+	}
+
+	if !p.pendingYank {
+		if event.Rune() == 'y' {
+			p.pendingYank = true
+			return true
+		}
+		return false
+	}
+
+	p.pendingYank = false
+	switch event.Rune() {
+	case 'q':
+		p.copySelected("question")
+	case 'a':
+		p.copySelected("answer")
+	default:
+		return false
+	}
+	return true
+}
+
+// copySelected copies the question or answer of the question shown in the detail view
+func (p *AgentsQAPageView) copySelected(part string) {
+	qa := agentQARegistry.GetQA(p.currentDetailID)
+	if qa == nil {
+		p.showStatusMessage("[red]Select a question first[white]")
+		return
+	}
+
+	text := qa.Question
+	if part == "answer" {
+		text = qa.Answer
+		if text == "" {
+			p.showStatusMessage("[red]This question has no answer yet[white]")
+			return
+		}
+	}
+
+	if err := copyToClipboard(text); err != nil {
+		p.showStatusMessage(fmt.Sprintf("[red]Copy failed:[white] %s", tview.Escape(err.Error())))
+		return
+	}
+	p.showStatusMessage(fmt.Sprintf("[green]Copied %s to clipboard[white] (%d bytes)", part, len(text)))
+}
+
+// showStatusMessage replaces the key hints in the status bar for a few seconds
+func (p *AgentsQAPageView) showStatusMessage(message string) {
+	p.statusBar.SetText(message + "\n" + pagesStatusLine)
+	time.AfterFunc(statusMessageDuration, func() {
+		p.tuiApp.app.QueueUpdateDraw(func() {
+			p.statusBar.SetText(statusBarText(AgentsQAPage))
+		})
+	})
+}
+
+// showFilterInput shows the filter input below the table, pre-filled with the active filter
+func (p *AgentsQAPageView) showFilterInput() {
+	if p.filterVisible {
+		return
+	}
+	p.filterVisible = true
+	p.filterInput.SetText(p.filterText)
+
+	p.view.Clear()
+	p.view.AddItem(p.content, 0, 1, false)
+	p.view.AddItem(p.filterInput, 3, 0, true)
+	p.view.AddItem(p.statusBar, 4, 0, false)
+
+	p.tuiApp.app.SetFocus(p.filterInput)
+}
+
+// hideFilterInput removes the filter input and returns focus to the table
+func (p *AgentsQAPageView) hideFilterInput() {
+	if !p.filterVisible {
+		return
+	}
+	p.filterVisible = false
+
+	p.view.Clear()
+	p.view.AddItem(p.content, 0, 1, true)
+	p.view.AddItem(p.statusBar, 4, 0, false)
+
+	p.focusedItem = 0
+	p.tuiApp.app.SetFocus(p.qaTable)
+}
+
+// applyFilter filters the table rows, or jumps to the question if text is a question ID
+func (p *AgentsQAPageView) applyFilter(text string) {
+	text = strings.TrimSpace(text)
+
+	if text != "" && agentQARegistry.GetQA(text) != nil {
+		// Jump to the question, clearing any filter that could hide it
+		p.filterText = ""
+		p.Refresh()
+		p.selectQuestion(text)
+		return
+	}
+
+	p.filterText = text
+	p.Refresh()
+}
+
+// selectQuestion selects the table row of a question and shows its details
+func (p *AgentsQAPageView) selectQuestion(questionID string) {
+	for row := 1; row < p.qaTable.GetRowCount(); row++ {
+		if cell := p.qaTable.GetCell(row, 0); cell != nil && cell.GetReference() == questionID {
+			p.qaTable.Select(row, 0)
+			p.selectedRow = row
+			p.showSelectedDetails()
+			return
+		}
+	}
+}
+
+// qaMatchesFilter reports whether a question matches a case-insensitive filter
+// on its sender, question text, status, or ID prefix
+func qaMatchesFilter(qa *QuestionAnswer, filter string) bool {
+	filter = strings.ToLower(filter)
+	return strings.Contains(strings.ToLower(qa.From), filter) ||
+		strings.Contains(strings.ToLower(qa.Question), filter) ||
+		strings.Contains(strings.ToLower(string(qa.Status)), filter) ||
+		strings.HasPrefix(strings.ToLower(qa.ID), filter)
+}
+
 // handleDetailViewKeys handles key events for the detail view
 func (p *AgentsQAPageView) handleDetailViewKeys(event *tcell.EventKey) *tcell.EventKey {
 	switch event.Key() {
@@ -133,6 +305,9 @@ func (p *AgentsQAPageView) handleDetailViewKeys(event *tcell.EventKey) *tcell.Ev
 		p.switchFocus()
 		return nil
 	}
+	if p.handleYankKeys(event) {
+		return nil
+	}
 	return event
 }
 
@@ -272,7 +447,9 @@ func (p *AgentsQAPageView) populateTableIncremental() {
 	p.lastSpecialistStatus = p.copySpecialistStatus()
 }
 
-// getQAsBySpecialist returns Q&As grouped by directory
+// getQAsBySpecialist returns Q&As grouped by directory.
+// With a filter active, only matching Q&As and the directories containing them are returned,
+// so the incremental update logic sees filtered results like any other change.
 func (p *AgentsQAPageView) getQAsBySpecialist() map[string][]*QuestionAnswer {
 	allDirectories := agentQARegistry.ListDirectories()
 
@@ -281,6 +458,18 @@ func (p *AgentsQAPageView) getQAsBySpecialist() map[string][]*QuestionAnswer {
 	for _, dir := range allDirectories {
 		// Get Q&As for this directory
 		qas := agentQARegistry.GetQAsByDirectory(dir.Key)
+		if p.filterText != "" {
+			var matching []*QuestionAnswer
+			for _, qa := range qas {
+				if qaMatchesFilter(qa, p.filterText) {
+					matching = append(matching, qa)
+				}
+			}
+			if len(matching) == 0 {
+				continue
+			}
+			qas = matching
+		}
 		directoryGroups[dir.Key] = qas
 	}
 
@@ -535,6 +724,9 @@ func (p *AgentsQAPageView) updateTableTitle(specialistGroups map[string][]*Quest
 	}
 
 	title := fmt.Sprintf(" Q&A History (%d) ", totalQAs)
+	if p.filterText != "" {
+		title += fmt.Sprintf("Filter: %s ", tview.Escape(p.filterText))
+	}
 	if p.focusedItem == 0 {
 		title += "[FOCUSED]"
 	}
@@ -572,7 +764,7 @@ func (p *AgentsQAPageView) showDirectoryDetails(dirKey string) {
 	detail += fmt.Sprintf("[yellow]Created At:[white] %s\n", dir.CreatedAt.Format("15:04:05"))
 
 	if dir.Instruction != "" {
-		detail += "\n[yellow]Instructions:[white]\n"
+		detail += fmt.Sprintf("\n[yellow]Instructions (v%d, updated %s):[white]\n", dir.InstructionVersion, dir.UpdatedAt.Format("15:04:05"))
 		detail += dir.Instruction + "\n"
 	}
 
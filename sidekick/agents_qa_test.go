@@ -2,9 +2,15 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 	"time"
+	"unicode/utf8"
 )
 
 // TestContextCancellationHandling tests that the system properly handles context cancellation
@@ -325,21 +331,18 @@ func TestConcurrentQuestionsAndAnswers(t *testing.T) {
 	}
 }
 
-// TestLateAnswerRetrieval tests that late answers can be retrieved via GetAnswer
-func TestLateAnswerRetrieval(t *testing.T) {
+// TestAsyncAnswerRetrieval tests that the answer to a question asked without waiting can be
+// retrieved later via GetAnswer
+func TestAsyncAnswerRetrieval(t *testing.T) {
 	registry := NewAgentQARegistry()
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	// Ask a question with a short timeout
-	qa, err := registry.AskQuestion("TestUser", "testing", "/test", "Test question", 100*time.Millisecond)
-	if err == nil {
-		// Should timeout since no specialist is waiting
-		t.Error("Expected timeout error")
-	}
-	if qa == nil {
-		t.Fatal("Expected qa to be returned even on timeout")
+	// Ask without waiting; the asker comes back for the answer later
+	qa, err := registry.AskQuestionAsync("TestUser", "testing", "/test", "Test question")
+	if err != nil {
+		t.Fatalf("Failed to ask: %v", err)
 	}
 
 	// Start specialist to answer the question
@@ -525,3 +528,599 @@ func TestSameSpecialistReentryWithCancelledContext(t *testing.T) {
 	}
 	registry.mutex.Unlock()
 }
+
+// TestQuestionAndAnswerSizeLimits tests that oversized questions/answers are rejected or truncated
+func TestQuestionAndAnswerSizeLimits(t *testing.T) {
+	registry := NewAgentQARegistry()
+	registry.SetSizeLimits(32, 64, false)
+
+	// Oversized question is rejected without creating a directory
+	if _, err := registry.AskQuestionAsync("Asker", "testing", "/test", strings.Repeat("q", 33)); err == nil {
+		t.Error("Expected error for oversized question")
+	}
+	if len(registry.ListDirectories()) != 0 {
+		t.Error("Rejected question should not create a directory")
+	}
+
+	qa, err := registry.AskQuestionAsync("Asker", "testing", "/test", "short question")
+	if err != nil {
+		t.Fatalf("Failed to ask question: %v", err)
+	}
+
+	// Oversized answer is rejected and the question stays answerable
+	if err := registry.AnswerQuestion(qa.ID, strings.Repeat("a", 65), nil); err == nil {
+		t.Error("Expected error for oversized answer")
+	}
+	if got := registry.GetQA(qa.ID).Status; got != QAStatusPending {
+		t.Errorf("Expected question to remain Pending, got %s", got)
+	}
+
+	// With truncation enabled, the answer is accepted and capped at the limit
+	registry.SetSizeLimits(32, 64, true)
+	if err := registry.AnswerQuestion(qa.ID, strings.Repeat("é", 100), nil); err != nil {
+		t.Fatalf("Expected truncated answer to be accepted: %v", err)
+	}
+	answer := registry.GetQA(qa.ID).Answer
+	if len(answer) > 64 {
+		t.Errorf("Expected answer of at most 64 bytes, got %d", len(answer))
+	}
+	if !utf8.ValidString(answer) {
+		t.Error("Truncated answer is not valid UTF-8")
+	}
+	if !strings.HasSuffix(answer, qaTruncationMarker) {
+		t.Error("Expected truncation marker at end of answer")
+	}
+}
+
+// TestOrphanRetriesExhausted verifies that an abandoned question fails once its retry budget is used up
+func TestOrphanRetriesExhausted(t *testing.T) {
+	registry := NewAgentQARegistry()
+
+	// Blocking asker with a single retry
+	askerDone := make(chan *QuestionAnswer, 1)
+	go func() {
+		qa, _ := registry.AskQuestionWithRetries("TestUser", "testing", "/test", "Test question", true, 5*time.Second, 1)
+		askerDone <- qa
+	}()
+
+	// Each specialist takes the question and then "crashes"
+	for i, name := range []string{"Specialist1", "Specialist2"} {
+		ctx, cancel := context.WithCancel(context.Background())
+		received, err := registry.WaitForQuestionWithContext(ctx, name, "testing", "/test", "Instructions", 2*time.Second)
+		if err != nil {
+			t.Fatalf("%s failed to get question: %v", name, err)
+		}
+
+		registry.mutex.Lock()
+		if received.RetryCount != i {
+			t.Errorf("Expected retry count %d for %s, got %d", i, name, received.RetryCount)
+		}
+		registry.mutex.Unlock()
+
+		cancel()
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	// A third specialist replacing the crashed one exhausts the budget
+	ctx3, cancel3 := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel3()
+	if _, err := registry.WaitForQuestionWithContext(ctx3, "Specialist3", "testing", "/test", "Instructions", 0); err == nil {
+		t.Error("Expected no question for third specialist after retries were exhausted")
+	}
+
+	select {
+	case qa := <-askerDone:
+		if qa == nil {
+			t.Fatal("Asker returned no question")
+		}
+		registry.mutex.Lock()
+		defer registry.mutex.Unlock()
+		if qa.Status != QAStatusFailed {
+			t.Errorf("Expected Failed status, got %s", qa.Status)
+		}
+		if qa.RetryCount != 1 {
+			t.Errorf("Expected retry count 1, got %d", qa.RetryCount)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("Asker was not unblocked after retries were exhausted")
+	}
+}
+
+// TestAskNamedSpecialist tests directing a question at a specific specialist
+func TestAskNamedSpecialist(t *testing.T) {
+	registry := NewAgentQARegistry()
+
+	// Strict: the named specialist is not waiting
+	if _, err := registry.AskSpecialist("TestUser", "testing", "/test", "Strict question", "Alice", true, true, false, 0, 0); err == nil {
+		t.Error("Expected error for strict question to a specialist that is not waiting")
+	}
+
+	// Non-strict: falls back to any specialist
+	fallback, err := registry.AskSpecialist("TestUser", "testing", "/test", "Fallback question", "Alice", false, true, false, 0, 0)
+	if err != nil {
+		t.Fatalf("Non-strict question failed: %v", err)
+	}
+	received, err := registry.WaitForQuestionWithContext(context.Background(), "Bob", "testing", "/test", "Instructions", time.Second)
+	if err != nil || received.ID != fallback.ID {
+		t.Fatalf("Expected Bob to receive the fallback question, got %v (err: %v)", received, err)
+	}
+	if err := registry.AnswerQuestion(received.ID, "answer", nil); err != nil {
+		t.Fatalf("Failed to answer: %v", err)
+	}
+
+	// Strict to the active waiter is delivered to it
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	aliceGot := make(chan *QuestionAnswer, 1)
+	go func() {
+		qa, _ := registry.WaitForQuestionWithContext(ctx, "Alice", "testing", "/other", "Instructions", 2*time.Second)
+		aliceGot <- qa
+	}()
+	time.Sleep(100 * time.Millisecond)
+
+	directed, err := registry.AskSpecialist("TestUser", "testing", "/other", "Directed question", "Alice", true, true, false, 0, 0)
+	if err != nil {
+		t.Fatalf("Strict question to active specialist failed: %v", err)
+	}
+
+	select {
+	case qa := <-aliceGot:
+		if qa == nil || qa.ID != directed.ID {
+			t.Errorf("Expected Alice to receive question %s, got %v", directed.ID, qa)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("Alice did not receive the directed question")
+	}
+}
+
+// TestDirectedQuestionSkippedByOthers tests that other specialists leave a directed question alone
+func TestDirectedQuestionSkippedByOthers(t *testing.T) {
+	registry := NewAgentQARegistry()
+
+	qa, err := registry.AskQuestionAsync("TestUser", "testing", "/test", "For Alice only")
+	if err != nil {
+		t.Fatalf("Failed to ask: %v", err)
+	}
+	registry.mutex.Lock()
+	qa.TargetName = "Alice"
+	registry.mutex.Unlock()
+
+	if _, err := registry.WaitForQuestionWithContext(context.Background(), "Bob", "testing", "/test", "Instructions", 200*time.Millisecond); err == nil {
+		t.Error("Expected Bob not to receive a question directed at Alice")
+	}
+}
+
+// TestUpdateInstructions tests updating directory instructions without an active waiter
+func TestUpdateInstructions(t *testing.T) {
+	registry := NewAgentQARegistry()
+
+	if _, _, err := registry.UpdateInstructions("testing", "/test", "Guidance"); err == nil {
+		t.Error("Expected error for unknown directory")
+	}
+
+	registry.RegisterDirectory("testing", "/test", "Initial guidance")
+
+	dir, changed, err := registry.UpdateInstructions("testing", "/test", "Newer guidance")
+	if err != nil || !changed {
+		t.Fatalf("Expected instructions to change, got changed=%v err=%v", changed, err)
+	}
+	if dir.Instruction != "Newer guidance" || dir.InstructionVersion != 2 || dir.UpdatedAt.IsZero() {
+		t.Errorf("Unexpected directory after update: %+v", dir)
+	}
+
+	if _, changed, _ := registry.UpdateInstructions("testing", "/test", "Newer guidance"); changed {
+		t.Error("Expected identical instructions not to bump the version")
+	}
+
+	dir, _, _ = registry.UpdateInstructions("testing", "/test", "")
+	if dir.Instruction != "" || dir.InstructionVersion != 3 {
+		t.Errorf("Expected instructions cleared at version 3, got %q v%d", dir.Instruction, dir.InstructionVersion)
+	}
+}
+
+// TestGetAnswersSharedDeadline tests collecting several answers under one timeout
+func TestGetAnswersSharedDeadline(t *testing.T) {
+	registry := NewAgentQARegistry()
+
+	qa1, err := registry.AskQuestionAsync("TestUser", "testing", "/test", "Question 1")
+	if err != nil {
+		t.Fatalf("Failed to ask question: %v", err)
+	}
+	qa2, err := registry.AskQuestionAsync("TestUser", "testing", "/test", "Question 2")
+	if err != nil {
+		t.Fatalf("Failed to ask question: %v", err)
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = registry.AnswerQuestion(qa1.ID, "Answer 1", nil)
+	}()
+
+	start := time.Now()
+	qas, errs := registry.GetAnswers([]string{qa1.ID, qa2.ID, "missing"}, 300*time.Millisecond)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected one shared deadline, took %v", elapsed)
+	}
+
+	if errs[0] != nil || qas[0].Answer != "Answer 1" {
+		t.Errorf("Expected first question answered, got %v (err %v)", qas[0], errs[0])
+	}
+	if errs[1] == nil || qas[1] == nil || qas[1].Status != QAStatusPending {
+		t.Errorf("Expected second question to time out while pending, got err %v", errs[1])
+	}
+	if errs[2] == nil || qas[2] != nil {
+		t.Errorf("Expected unknown question to fail, got %v", qas[2])
+	}
+}
+
+// TestQAMatchesFilter tests the Q&A page row filter
+func TestQAMatchesFilter(t *testing.T) {
+	qa := &QuestionAnswer{ID: "5f2c9a1e-0000", From: "FrontendAgent", Question: "How is auth wired?", Status: QAStatusPending}
+
+	for _, filter := range []string{"frontend", "AUTH", "pend", "5f2c"} {
+		if !qaMatchesFilter(qa, filter) {
+			t.Errorf("Expected %q to match", filter)
+		}
+	}
+	for _, filter := range []string{"backend", "0000", "completed"} {
+		if qaMatchesFilter(qa, filter) {
+			t.Errorf("Expected %q not to match", filter)
+		}
+	}
+}
+
+// TestDeleteDirectory tests purging a directory, its Q&As, and guarding an active waiter
+func TestDeleteDirectory(t *testing.T) {
+	registry := NewAgentQARegistry()
+
+	if _, err := registry.DeleteDirectory("/missing-testing", false); err == nil {
+		t.Error("Expected error for unknown directory")
+	}
+
+	answered, _ := registry.AskQuestionAsync("TestUser", "testing", "/test", "Question 1")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if _, err := registry.WaitForQuestionWithContext(ctx, "TestSpecialist", "testing", "/test", "", time.Second); err != nil {
+		t.Fatalf("Failed to wait for question: %v", err)
+	}
+	_ = registry.AnswerQuestion(answered.ID, "Answer 1", nil)
+	pending, _ := registry.AskQuestionAsync("TestUser", "testing", "/test", "Question 2")
+
+	// The specialist takes question 2, then blocks waiting for more while it is unanswered
+	_, _ = registry.WaitForQuestionWithContext(ctx, "TestSpecialist", "testing", "/test", "", time.Second)
+	waitDone := make(chan error, 1)
+	go func() {
+		_, err := registry.WaitForQuestionWithContext(ctx, "TestSpecialist", "testing", "/test", "", 5*time.Second)
+		waitDone <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	askerDone := make(chan error, 1)
+	go func() {
+		_, err := registry.GetAnswer(pending.ID, 5*time.Second)
+		askerDone <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	key := "/test-testing"
+	if _, err := registry.DeleteDirectory(key, false); err == nil {
+		t.Fatal("Expected deletion to be refused while a specialist is waiting")
+	}
+
+	deletion, err := registry.DeleteDirectory(key, true)
+	if err != nil {
+		t.Fatalf("Forced deletion failed: %v", err)
+	}
+	if deletion.Questions != 2 || deletion.Unresolved != 1 || deletion.WaiterCancelled != "TestSpecialist" {
+		t.Errorf("Unexpected deletion summary: %+v", deletion)
+	}
+	if registry.GetDirectory(key) != nil || registry.GetQA(answered.ID) != nil {
+		t.Error("Expected the directory and its Q&As to be gone")
+	}
+
+	for name, done := range map[string]chan error{"specialist": waitDone, "asker": askerDone} {
+		select {
+		case err := <-done:
+			if err == nil {
+				t.Errorf("Expected the %s's wait to end with an error", name)
+			}
+		case <-time.After(2 * time.Second):
+			t.Errorf("Expected the %s's wait to be released", name)
+		}
+	}
+}
+
+// TestQAAuditLog verifies answered and failed questions are appended to the audit log
+func TestQAAuditLog(t *testing.T) {
+	registry := NewAgentQARegistry()
+	path := filepath.Join(t.TempDir(), "audit.ndjson")
+	auditFile, err := openQAAuditLog(path)
+	if err != nil {
+		t.Fatalf("Failed to open audit log: %v", err)
+	}
+	defer auditFile.Close()
+	registry.SetAuditLog(auditFile)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	answered, _ := registry.AskQuestionAsync("TestUser", "testing", "/test", "Question 1")
+	failed, _ := registry.AskQuestionAsync("TestUser", "testing", "/test", "Question 2")
+	for _, outcome := range []struct {
+		qa     *QuestionAnswer
+		answer string
+		err    error
+	}{{answered, "Answer 1", nil}, {failed, "", fmt.Errorf("cannot answer")}} {
+		if _, err := registry.WaitForQuestionWithContext(ctx, "TestSpecialist", "testing", "/test", "", time.Second); err != nil {
+			t.Fatalf("Failed to wait for question: %v", err)
+		}
+		_ = registry.AnswerQuestion(outcome.qa.ID, outcome.answer, outcome.err)
+	}
+	if err := registry.AnswerQuestion(answered.ID, "Again", nil); err == nil {
+		t.Error("Expected a second answer to be rejected")
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 audit records, got %d: %q", len(lines), content)
+	}
+
+	var records []QAAuditRecord
+	for _, line := range lines {
+		var record QAAuditRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Invalid audit record %q: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	if records[0].ID != answered.ID || records[0].From != "TestUser" || records[0].To != "TestSpecialist" ||
+		records[0].Question != "Question 1" || records[0].Answer != "Answer 1" || records[0].Status != QAStatusCompleted {
+		t.Errorf("Unexpected completed record: %+v", records[0])
+	}
+	if records[1].ID != failed.ID || records[1].Error != "cannot answer" || records[1].Status != QAStatusFailed {
+		t.Errorf("Unexpected failed record: %+v", records[1])
+	}
+
+	if _, err := openQAAuditLog(filepath.Join(t.TempDir(), "missing", "audit.ndjson")); err == nil {
+		t.Error("Expected an error for an audit log in a missing directory")
+	}
+}
+
+// TestReplayQuestions verifies the most recent completed questions are re-queued in order
+func TestReplayQuestions(t *testing.T) {
+	registry := NewAgentQARegistry()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var originals []*QuestionAnswer
+	for i := 1; i <= 3; i++ {
+		qa, _ := registry.AskQuestionAsync("TestUser", "testing", "/test", fmt.Sprintf("Question %d", i))
+		if _, err := registry.WaitForQuestionWithContext(ctx, "TestSpecialist", "testing", "/test", "", time.Second); err != nil {
+			t.Fatalf("Failed to wait for question: %v", err)
+		}
+		_ = registry.AnswerQuestion(qa.ID, fmt.Sprintf("Answer %d", i), nil)
+		originals = append(originals, qa)
+	}
+	_, _ = registry.AskQuestionAsync("TestUser", "testing", "/test", "Still pending")
+
+	if _, err := registry.ReplayQuestions("/missing-testing", 1, "", true); err == nil {
+		t.Error("Expected error for unknown directory")
+	}
+
+	replayed, err := registry.ReplayQuestions("/test-testing", 2, "Reviewer", true)
+	if err != nil {
+		t.Fatalf("ReplayQuestions failed: %v", err)
+	}
+	if len(replayed) != 2 || replayed[0].OriginalID != originals[1].ID || replayed[1].OriginalID != originals[2].ID {
+		t.Fatalf("Expected the last two completed questions in order, got %+v", replayed)
+	}
+
+	replay := registry.GetQA(replayed[0].QuestionID)
+	if replay == nil || replay.Question != "Question 2" || replay.From != "Reviewer" || replay.ReplayOf != originals[1].ID || replay.Status != QAStatusPending {
+		t.Errorf("Unexpected replayed question: %+v", replay)
+	}
+}
+
+// TestGlobalSpecialistFallback verifies questions go to the global directory only when the project has none
+func TestGlobalSpecialistFallback(t *testing.T) {
+	registry := NewAgentQARegistry()
+	registry.RegisterDirectory("security", GlobalRootDir, "Org-wide security reviews")
+	registry.RegisterDirectory("security", "/scoped", "")
+
+	fallback, err := registry.AskQuestionAsync("TestUser", "security", "/unscoped", "Is this safe?")
+	if err != nil {
+		t.Fatalf("Failed to ask: %v", err)
+	}
+	if fallback.DirectoryKey != "*-security" {
+		t.Errorf("Expected the question to fall back to '*-security', got '%s'", fallback.DirectoryKey)
+	}
+	if registry.GetDirectory("/unscoped-security") != nil {
+		t.Error("Expected no directory to be created for the unscoped project")
+	}
+
+	scoped, _ := registry.AskQuestionAsync("TestUser", "security", "/scoped", "Is this safe?")
+	if scoped.DirectoryKey != "/scoped-security" {
+		t.Errorf("Expected the project's own directory to win, got '%s'", scoped.DirectoryKey)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	picked, err := registry.WaitForQuestionWithContext(ctx, "Reviewer", "security", GlobalRootDir, "", time.Second)
+	if err != nil || picked.ID != fallback.ID {
+		t.Errorf("Expected the global specialist to receive the fallback question, got %v, %v", picked, err)
+	}
+}
+
+// TestAskerDeadline tests that specialists see the asker's deadline, that answers after it are
+// rejected, and that questions expiring in the queue are failed instead of handed out
+func TestAskerDeadline(t *testing.T) {
+	registry := NewAgentQARegistry()
+	registry.RegisterDirectory("testing", "/test", "")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	picked := make(chan *QuestionAnswer, 1)
+	go func() {
+		qa, err := registry.WaitForQuestionWithContext(ctx, "TestSpecialist", "testing", "/test", "", 2*time.Second)
+		if err == nil {
+			picked <- qa
+		}
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	asked := time.Now()
+	qa, err := registry.AskQuestion("TestUser", "testing", "/test", "Quick question", 150*time.Millisecond)
+	if err == nil {
+		t.Fatal("Expected the asker to time out")
+	}
+
+	received := <-picked
+	if received.Deadline.IsZero() || received.Deadline.Before(asked) || received.Deadline.After(asked.Add(time.Second)) {
+		t.Errorf("Expected the question to carry the asker's deadline, got %v", received.Deadline)
+	}
+	result := nextQuestionResult(received)
+	if _, ok := result["deadline"]; !ok {
+		t.Error("Expected get_next_question result to include deadline")
+	}
+	if remaining, ok := result["remaining_ms"].(int64); !ok || remaining != 0 {
+		t.Errorf("Expected remaining_ms 0 after the deadline, got %v", result["remaining_ms"])
+	}
+
+	if err := registry.AnswerQuestion(qa.ID, "Too late", nil); err == nil {
+		t.Error("Expected an answer after the deadline to be rejected")
+	}
+	late := registry.GetQA(qa.ID)
+	if late.Status != QAStatusFailed || late.Answer != "" {
+		t.Errorf("Expected the late question to be failed without an answer, got %s %q", late.Status, late.Answer)
+	}
+
+	// Questions that expire while queued are never handed out
+	expired, _ := registry.AskQuestion("TestUser", "testing", "/test", "Nobody is listening", 50*time.Millisecond)
+	waitCtx, waitCancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer waitCancel()
+	if next, err := registry.WaitForQuestionWithContext(waitCtx, "TestSpecialist", "testing", "/test", "", time.Second); err == nil {
+		t.Errorf("Expected no question to be handed out, got %s", next.ID)
+	}
+	if stale := registry.GetQA(expired.ID); stale.Status != QAStatusFailed {
+		t.Errorf("Expected the expired question to be failed, got %s", stale.Status)
+	}
+}
+
+// TestCheckSpecialist tests that availability reflects the directory, pending questions, and
+// whether a specialist is waiting or answering
+func TestCheckSpecialist(t *testing.T) {
+	registry := NewAgentQARegistry()
+
+	if availability := registry.CheckSpecialist("testing", "/test"); availability.Available || availability.DirectoryExists {
+		t.Errorf("Expected no directory and no specialist, got %+v", availability)
+	}
+
+	registry.RegisterDirectory("testing", "/test", "")
+	registry.AskQuestionAsync("TestUser", "testing", "/test", "Anyone there?")
+	if availability := registry.CheckSpecialist("testing", "/test"); availability.Available || availability.PendingQuestions != 1 {
+		t.Errorf("Expected a directory with one pending question and no specialist, got %+v", availability)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	qa, err := registry.WaitForQuestionWithContext(ctx, "TestSpecialist", "testing", "/test", "", time.Second)
+	if err != nil {
+		t.Fatalf("Failed to get question: %v", err)
+	}
+	cancel() // get_next_question returned; the specialist is now answering
+
+	availability := registry.CheckSpecialist("testing", "/test")
+	if !availability.Available || !availability.Busy || availability.SpecialistName != "TestSpecialist" {
+		t.Errorf("Expected the answering specialist to count as available and busy, got %+v", availability)
+	}
+
+	registry.AnswerQuestion(qa.ID, "Yes", nil)
+	if availability := registry.CheckSpecialist("testing", "/test"); availability.Available {
+		t.Errorf("Expected no specialist once the answered specialist's context ended, got %+v", availability)
+	}
+}
+
+// TestEnqueueIfNoWaiter tests that a question is rejected rather than queued when no specialist
+// is waiting and enqueue is disabled, and queued for a late specialist by default
+func TestEnqueueIfNoWaiter(t *testing.T) {
+	registry := NewAgentQARegistry()
+	registry.RegisterDirectory("testing", "/test", "")
+
+	if _, err := registry.AskSpecialist("TestUser", "testing", "/test", "Anyone?", "", false, false, false, 0, 0); err == nil {
+		t.Error("Expected an error with no specialist and enqueue disabled")
+	}
+	if pending := registry.CheckSpecialist("testing", "/test").PendingQuestions; pending != 0 {
+		t.Errorf("Expected the rejected question not to be queued, got %d pending", pending)
+	}
+
+	// The default queues the question for the next specialist to arrive
+	queued, err := registry.AskSpecialist("TestUser", "testing", "/test", "Anyone later?", "", false, true, false, 0, 0)
+	if err != nil {
+		t.Fatalf("Failed to queue: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	picked, err := registry.WaitForQuestionWithContext(ctx, "TestSpecialist", "testing", "/test", "", time.Second)
+	if err != nil || picked.ID != queued.ID {
+		t.Errorf("Expected the late specialist to receive the queued question, got %v, %v", picked, err)
+	}
+}
+
+// TestProcessingTimeout verifies a question its specialist sits on is re-queued, then failed once retries run out
+func TestProcessingTimeout(t *testing.T) {
+	registry := NewAgentQARegistry()
+	registry.SetProcessingTimeout(100 * time.Millisecond)
+	registry.RegisterDirectory("testing", "/test", "")
+
+	qa, err := registry.AskQuestionWithRetries("TestUser", "testing", "/test", "Anyone?", false, 0, 1)
+	if err != nil {
+		t.Fatalf("Failed to ask: %v", err)
+	}
+
+	waitForStatus := func(want QAStatus) *QuestionAnswer {
+		deadline := time.Now().Add(2 * time.Second)
+		for time.Now().Before(deadline) {
+			registry.mutex.Lock()
+			status := qa.Status
+			registry.mutex.Unlock()
+			if status == want {
+				return registry.GetQA(qa.ID)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("Question never reached %s", want)
+		return nil
+	}
+
+	picked, err := registry.WaitForQuestion("Silent", "testing", "/test", "", time.Second)
+	if err != nil || picked.ID != qa.ID {
+		t.Fatalf("Expected the specialist to pick the question, got %v", err)
+	}
+	if _, ok := nextQuestionResult(picked)["answer_by"]; !ok {
+		t.Error("Expected get_next_question to tell the specialist when the claim expires")
+	}
+
+	requeued := waitForStatus(QAStatusPending)
+	if requeued.RequeueCount != 1 || requeued.RetryCount != 1 || requeued.To != "" {
+		t.Errorf("Expected one re-queue for silence, got requeue %d retry %d to %q", requeued.RequeueCount, requeued.RetryCount, requeued.To)
+	}
+
+	// The retry budget is spent, so the next silent claim fails the question
+	if _, err := registry.WaitForQuestion("Silent", "testing", "/test", "", time.Second); err != nil {
+		t.Fatalf("Expected the re-queued question to be picked up again: %v", err)
+	}
+	start := time.Now()
+	answered, _ := registry.GetAnswer(qa.ID, 2*time.Second)
+	if answered.Status != QAStatusFailed || !strings.Contains(answered.Error, "did not answer within") {
+		t.Errorf("Expected the question to fail for silence, got %s %q", answered.Status, answered.Error)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("Expected get_answer to return as soon as the question failed")
+	}
+	if answered.RequeueCount != 1 {
+		t.Errorf("Expected the failed attempt not to count as a re-queue, got %d", answered.RequeueCount)
+	}
+}
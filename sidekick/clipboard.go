@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// clipboardTimeout bounds how long a clipboard tool may take
+const clipboardTimeout = 2 * time.Second
+
+// clipboardCandidates lists the clipboard tools to try for the current platform, in order
+func clipboardCandidates() [][]string {
+	switch runtime.GOOS {
+	case "darwin":
+		return [][]string{{"pbcopy"}}
+	case "windows":
+		return [][]string{{"clip"}}
+	default:
+		var candidates [][]string
+		if os.Getenv("WAYLAND_DISPLAY") != "" {
+			candidates = append(candidates, []string{"wl-copy"})
+		}
+		return append(candidates,
+			[]string{"xclip", "-selection", "clipboard"},
+			[]string{"xsel", "--clipboard", "--input"},
+		)
+	}
+}
+
+// copyToClipboard puts text on the system clipboard using the first available clipboard tool
+func copyToClipboard(text string) error {
+	for _, candidate := range clipboardCandidates() {
+		if _, err := exec.LookPath(candidate[0]); err != nil {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), clipboardTimeout)
+		defer cancel()
+
+		cmd := exec.CommandContext(ctx, candidate[0], candidate[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("%s failed: %v", candidate[0], err)
+		}
+		return nil
+	}
+
+	names := []string{}
+	for _, candidate := range clipboardCandidates() {
+		names = append(names, candidate[0])
+	}
+	return fmt.Errorf("no clipboard tool found (tried %s)", strings.Join(names, ", "))
+}
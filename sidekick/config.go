@@ -11,7 +11,23 @@ import (
 // SidekickConfig holds persistent configuration
 type SidekickConfig struct {
 	CursorKeybindingsWatcher CursorKeybindingsWatcherConfig `json:"cursor_keybindings_watcher"`
-	Discord                  DiscordConfig                   `json:"discord"`
+	Discord                  DiscordConfig                  `json:"discord"`
+	FilterPresets            map[string][][]string          `json:"filter_presets,omitempty"` // Custom output filter presets
+	Limits                   *LimitsConfig                  `json:"limits,omitempty"`         // Overrides the Q&A size limit flags
+	SpawnPolicy              *SpawnPolicyConfig             `json:"spawn_policy,omitempty"`   // Restrictions not already set by --allowed-workdir/--allowed-command
+}
+
+// LimitsConfig holds Q&A size limits that can be changed at runtime (nil = keep current value)
+type LimitsConfig struct {
+	MaxQuestionBytes    *int  `json:"max_question_bytes,omitempty"`
+	MaxAnswerBytes      *int  `json:"max_answer_bytes,omitempty"`
+	TruncateOversizedQA *bool `json:"truncate_oversized_qa,omitempty"`
+}
+
+// SpawnPolicyConfig confines spawned processes; empty lists mean unrestricted
+type SpawnPolicyConfig struct {
+	AllowedWorkdirs []string `json:"allowed_workdirs,omitempty"`
+	AllowedCommands []string `json:"allowed_commands,omitempty"`
 }
 
 // CursorKeybindingsWatcherConfig holds keybindings watcher preferences
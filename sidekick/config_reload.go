@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// applyReloadableConfig applies the settings that are safe to change while running:
+// filter presets, Q&A size limits, and the spawn policy. Everything is validated before
+// anything is applied, so an invalid config changes nothing. Returns the settings that changed.
+func applyReloadableConfig(cfg *SidekickConfig) ([]string, error) {
+	// Validate
+	maxQuestionBytes, maxAnswerBytes, truncate := agentQARegistry.SizeLimits()
+	newQuestionBytes, newAnswerBytes, newTruncate := maxQuestionBytes, maxAnswerBytes, truncate
+	if limits := cfg.Limits; limits != nil {
+		if limits.MaxQuestionBytes != nil {
+			newQuestionBytes = *limits.MaxQuestionBytes
+		}
+		if limits.MaxAnswerBytes != nil {
+			newAnswerBytes = *limits.MaxAnswerBytes
+		}
+		if limits.TruncateOversizedQA != nil {
+			newTruncate = *limits.TruncateOversizedQA
+		}
+		if newQuestionBytes < 0 || newAnswerBytes < 0 {
+			return nil, fmt.Errorf("limits.max_question_bytes and limits.max_answer_bytes cannot be negative")
+		}
+	}
+
+	var policy *SpawnPolicy
+	var overridden []string
+	if cfg.SpawnPolicy != nil {
+		// Restrictions given on the command line win over the config file
+		workdirs, commands := cfg.SpawnPolicy.AllowedWorkdirs, cfg.SpawnPolicy.AllowedCommands
+		if len(spawnPolicyFlags.AllowedWorkdirs) > 0 {
+			if len(workdirs) > 0 {
+				overridden = append(overridden, "allowed_workdirs (--allowed-workdir)")
+			}
+			workdirs = spawnPolicyFlags.AllowedWorkdirs
+		}
+		if len(spawnPolicyFlags.AllowedCommands) > 0 {
+			if len(commands) > 0 {
+				overridden = append(overridden, "allowed_commands (--allowed-command)")
+			}
+			commands = spawnPolicyFlags.AllowedCommands
+		}
+
+		policy = &SpawnPolicy{}
+		if err := policy.Configure(workdirs, commands); err != nil {
+			return nil, fmt.Errorf("spawn_policy: %v", err)
+		}
+	}
+
+	// Apply
+	changed := []string{}
+
+	if !reflect.DeepEqual(CustomFilterPresets(), cfg.FilterPresets) {
+		SetCustomFilterPresets(cfg.FilterPresets)
+		changed = append(changed, "filter_presets")
+	}
+
+	if newQuestionBytes != maxQuestionBytes {
+		changed = append(changed, "limits.max_question_bytes")
+	}
+	if newAnswerBytes != maxAnswerBytes {
+		changed = append(changed, "limits.max_answer_bytes")
+	}
+	if newTruncate != truncate {
+		changed = append(changed, "limits.truncate_oversized_qa")
+	}
+	agentQARegistry.SetSizeLimits(newQuestionBytes, newAnswerBytes, newTruncate)
+
+	if len(overridden) > 0 {
+		LogWarn("Config", "Ignoring spawn_policy settings set on the command line", strings.Join(overridden, ", "))
+	}
+	if policy != nil {
+		oldWorkdirs, oldCommands := spawnPolicy.Rules()
+		newWorkdirs, newCommands := policy.Rules()
+		if !slices.Equal(oldWorkdirs, newWorkdirs) || !slices.Equal(oldCommands, newCommands) {
+			if spawnPolicy.widens(policy) {
+				LogWarn("Config", "Spawn policy loosened by config",
+					fmt.Sprintf("Workdirs: [%s] → [%s], commands: [%s] → [%s]",
+						strings.Join(oldWorkdirs, ", "), strings.Join(newWorkdirs, ", "),
+						strings.Join(oldCommands, ", "), strings.Join(newCommands, ", ")))
+			}
+			spawnPolicy.replace(policy)
+			changed = append(changed, "spawn_policy")
+		}
+	}
+
+	return changed, nil
+}
+
+// reloadConfig re-reads ~/.sidekick/config.json and applies its reloadable settings
+func reloadConfig(trigger string) ([]string, error) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		LogError("Config", "Config reload failed", fmt.Sprintf("Trigger: %s, error: %v", trigger, err))
+		return nil, err
+	}
+
+	changed, err := applyReloadableConfig(cfg)
+	if err != nil {
+		LogError("Config", "Config reload rejected", fmt.Sprintf("Trigger: %s, error: %v", trigger, err))
+		return changed, err
+	}
+
+	if len(changed) == 0 {
+		LogInfo("Config", "Config reloaded, nothing changed", fmt.Sprintf("Trigger: %s", trigger))
+	} else {
+		LogInfo("Config", "Config reloaded", fmt.Sprintf("Trigger: %s, changed: %s", trigger, strings.Join(changed, ", ")))
+	}
+	return changed, nil
+}
+
+// handleReloadConfig re-reads the config file without dropping connections or processes
+func handleReloadConfig(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	changed, err := reloadConfig("reload_config")
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Config reload failed: %v", err)), nil
+	}
+
+	path, _ := configPath()
+	result := map[string]any{
+		"config_path": path,
+		"changed":     changed,
+	}
+
+	resultBytes, _ := json.Marshal(result)
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
@@ -86,7 +86,7 @@ func (p *FeaturesPageView) setupWebhookInput() {
 // setupStatusBar configures the status bar
 func (p *FeaturesPageView) setupStatusBar() {
 	p.statusBar.SetBorder(true).SetTitle(" Controls ").SetTitleAlign(tview.AlignLeft)
-	p.statusBar.SetText("[yellow]↑↓[white]: Navigate | [yellow]Enter[white]: Toggle/Edit | [yellow]Esc[white]: Back | [yellow]Q[white]: Quit\n[grey]Pages: [yellow]1[white]: Processes | [yellow]2[white]: Notifications | [yellow]3[white]: Agents Q&A | [yellow]4[white]: Logs | [yellow]5[white]: Features[grey]")
+	p.statusBar.SetText(statusBarText(FeaturesPage))
 	p.statusBar.SetTextAlign(tview.AlignCenter)
 	p.statusBar.SetDynamicColors(true)
 }
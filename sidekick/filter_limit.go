@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	DefaultMaxFilterConcurrency = 8               // Filter pipelines allowed to run at once (--max-filter-concurrency)
+	MaxFilterConcurrencyLimit   = 256             // Upper bound for --max-filter-concurrency
+	FilterQueueWait             = 2 * time.Second // How long a filter waits for a free slot before failing
+	filterQueuePerSlot          = 4               // Waiting filters allowed per slot before new ones fail at once
+)
+
+// filterLimiter is a semaphore gating filterOutput, so concurrent filtered reads can't flood
+// the host with filter processes
+type filterLimiter struct {
+	slots      chan struct{}
+	waiting    atomic.Int32
+	maxWaiting int32
+}
+
+// filterSlots gates every filter pipeline (--max-filter-concurrency)
+var filterSlots = newFilterLimiter(DefaultMaxFilterConcurrency)
+
+func newFilterLimiter(concurrency int) *filterLimiter {
+	return &filterLimiter{
+		slots:      make(chan struct{}, concurrency),
+		maxWaiting: int32(concurrency * filterQueuePerSlot),
+	}
+}
+
+// acquire takes a slot, waiting up to wait for one to free up. Fails straight away when
+// the queue is already full.
+func (l *filterLimiter) acquire(wait time.Duration) error {
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	default:
+	}
+
+	if l.waiting.Add(1) > l.maxWaiting {
+		l.waiting.Add(-1)
+		return fmt.Errorf("filter busy: %d filter pipelines running and the queue is full, try again shortly", cap(l.slots))
+	}
+	defer l.waiting.Add(-1)
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("filter busy: no free slot after %s (%d filter pipelines running), try again shortly", wait, cap(l.slots))
+	}
+}
+
+// release frees a slot taken by acquire
+func (l *filterLimiter) release() {
+	<-l.slots
+}
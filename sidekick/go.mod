@@ -9,6 +9,7 @@ require (
 	github.com/mark3labs/mcp-go v0.43.2
 	github.com/rivo/tview v0.0.0-20241227133733-17b7edb88c57
 	github.com/tidwall/jsonc v0.3.2
+	golang.org/x/sys v0.17.0
 )
 
 require (
@@ -24,7 +25,6 @@ require (
 	github.com/stretchr/testify v1.10.0 // indirect
 	github.com/wk8/go-ordered-map/v2 v2.1.8 // indirect
 	github.com/yosida95/uritemplate/v3 v3.0.2 // indirect
-	golang.org/x/sys v0.17.0 // indirect
 	golang.org/x/term v0.17.0 // indirect
 	golang.org/x/text v0.14.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// DefaultDrainTimeout is how long drain mode waits for running processes before shutting down anyway
+const DefaultDrainTimeout = 30 * time.Minute
+
+// drainCheckInterval is how often drain mode checks whether the running processes have finished
+const drainCheckInterval = time.Second
+
+// drainTimeout is the drain deadline (--drain-timeout)
+var drainTimeout = DefaultDrainTimeout
+
+// drainState tracks drain mode: new sessions are refused while existing sessions and
+// processes are served until the processes finish or the deadline passes
+type drainState struct {
+	mu       sync.RWMutex
+	draining bool
+	trigger  string
+	started  time.Time
+	deadline time.Time
+}
+
+var httpDrain = &drainState{}
+
+// DrainStatus describes drain mode for /healthz and /drain
+type DrainStatus struct {
+	Draining bool       `json:"draining"`
+	Trigger  string     `json:"trigger,omitempty"`
+	Started  *time.Time `json:"started,omitempty"`
+	Deadline *time.Time `json:"deadline,omitempty"`
+}
+
+// start enters drain mode. Returns false if already draining.
+func (d *drainState) start(trigger string, timeout time.Duration) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.draining {
+		return false
+	}
+	d.draining = true
+	d.trigger = trigger
+	d.started = time.Now()
+	d.deadline = d.started.Add(timeout)
+	return true
+}
+
+func (d *drainState) isDraining() bool {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.draining
+}
+
+func (d *drainState) status() DrainStatus {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if !d.draining {
+		return DrainStatus{}
+	}
+	started, deadline := d.started, d.deadline
+	return DrainStatus{Draining: true, Trigger: d.trigger, Started: &started, Deadline: &deadline}
+}
+
+// startDrain puts the HTTP server into drain mode and shuts sidekick down once no processes
+// are running, or at the drain deadline
+func startDrain(trigger string) {
+	if !httpDrain.start(trigger, drainTimeout) {
+		LogInfo("HTTPServer", "Already draining", fmt.Sprintf("Trigger: %s", trigger))
+		return
+	}
+
+	running := len(getRunningProcesses())
+	LogWarn("HTTPServer", "Drain mode started: refusing new sessions",
+		fmt.Sprintf("Trigger: %s, running processes: %d, deadline: %s", trigger, running, drainTimeout))
+
+	go waitForDrain(drainTimeout)
+}
+
+// waitForDrain triggers shutdown once the running processes have finished or timeout passes
+func waitForDrain(timeout time.Duration) {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(drainCheckInterval)
+	defer ticker.Stop()
+
+	for running := len(getRunningProcesses()); running > 0; running = len(getRunningProcesses()) {
+		select {
+		case <-ticker.C:
+		case <-deadline.C:
+			LogWarn("HTTPServer", "Drain deadline reached, shutting down", fmt.Sprintf("Processes still running: %d", running))
+			shutdownOnce.Do(func() {
+				close(shutdownChan)
+			})
+			return
+		case <-shutdownChan:
+			return // Shut down some other way in the meantime
+		}
+	}
+
+	LogInfo("HTTPServer", "Drain complete, shutting down", "No processes running")
+	shutdownOnce.Do(func() {
+		close(shutdownChan)
+	})
+}
+
+// isNewSessionRequest reports whether r would open a new MCP session: an SSE stream,
+// or a Streamable HTTP request that carries no session ID yet (initialize)
+func isNewSessionRequest(r *http.Request) bool {
+	path, _ := routePath(r)
+	if strings.HasPrefix(path, "/mcp/sse") {
+		return true
+	}
+	return path == "/mcp" && r.Method == http.MethodPost && r.Header.Get(server.HeaderKeySessionID) == ""
+}
+
+// handleHealthz reports liveness and drain status. Answers 503 while draining so load
+// balancers stop routing new clients here.
+func handleHealthz(w http.ResponseWriter, r *http.Request) {
+	drain := httpDrain.status()
+
+	connected := 0
+	sessionManager.mu.RLock()
+	for _, session := range sessionManager.sessions {
+		if session.Status == SessionConnected {
+			connected++
+		}
+	}
+	sessionManager.mu.RUnlock()
+
+	status := "ok"
+	code := http.StatusOK
+	if drain.Draining {
+		status = "draining"
+		code = http.StatusServiceUnavailable
+	} else if maintenance.isEnabled() {
+		status = "maintenance" // Still healthy: existing work is served, only new work is refused
+	}
+
+	writeJSON(w, code, map[string]any{
+		"status":             status,
+		"version":            version,
+		"connected_sessions": connected,
+		"running_processes":  len(getRunningProcesses()),
+		"sse_connections":    sseConnections.Stats(),
+		"drain":              drain,
+		"maintenance":        maintenance.status(),
+	})
+}
+
+// handleDrainRequest starts drain mode (POST /drain), for platforms without SIGUSR1
+func handleDrainRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Use POST to start draining", http.StatusMethodNotAllowed)
+		return
+	}
+
+	startDrain("POST /drain")
+	writeJSON(w, http.StatusAccepted, httpDrain.status())
+}
+
+// writeJSON writes v as a JSON response with the given status code
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
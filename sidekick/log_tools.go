@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// handleSetLogCapacity changes the in-memory log capacity, trimming the oldest entries if reduced
+func handleSetLogCapacity(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	maxEntries := getIntArg(request, "max_entries", 0)
+	if maxEntries < 1 || maxEntries > MaxLogMaxEntries {
+		return mcp.NewToolResultError(fmt.Sprintf("max_entries must be between 1 and %d", MaxLogMaxEntries)), nil
+	}
+
+	previous := logger.Stats().Capacity
+	dropped := logger.SetMaxEntries(maxEntries)
+	LogInfo("Logger", "Log capacity changed", fmt.Sprintf("From %d to %d entries, dropped: %d", previous, maxEntries, dropped))
+
+	result := map[string]any{
+		"previous_capacity": previous,
+		"capacity":          maxEntries,
+		"dropped":           dropped,
+	}
+
+	resultBytes, _ := json.Marshal(result)
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// handleLogStats reports the in-memory log's size and capacity
+func handleLogStats(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	resultBytes, _ := json.Marshal(logger.Stats())
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
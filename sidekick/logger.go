@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"sort"
 	"sync"
 	"time"
 )
@@ -39,6 +40,11 @@ type LogEntry struct {
 	Details   string    `json:"details,omitempty"` // Optional additional details
 }
 
+const (
+	DefaultLogMaxEntries = 1000    // In-memory log entries kept by default
+	MaxLogMaxEntries     = 1000000 // Upper bound for --log-max-entries / set_log_capacity
+)
+
 // Logger manages application logs
 type Logger struct {
 	mu            sync.RWMutex
@@ -50,7 +56,7 @@ type Logger struct {
 // Global logger instance
 var logger = &Logger{
 	entries:       make([]LogEntry, 0),
-	maxEntries:    1000,
+	maxEntries:    DefaultLogMaxEntries,
 	consoleOutput: true, // Default to console output
 }
 
@@ -141,6 +147,86 @@ func (l *Logger) GetEntriesByLevel(level LogLevel) []LogEntry {
 	return filtered
 }
 
+// GetEntriesBySource returns entries logged by the given source (e.g. "AgentQA")
+func (l *Logger) GetEntriesBySource(source string) []LogEntry {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	var filtered []LogEntry
+	for _, entry := range l.entries {
+		if entry.Source == source {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+// Sources returns the distinct sources of the current entries, sorted
+func (l *Logger) Sources() []string {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	var sources []string
+	for _, entry := range l.entries {
+		if !seen[entry.Source] {
+			seen[entry.Source] = true
+			sources = append(sources, entry.Source)
+		}
+	}
+	sort.Strings(sources)
+	return sources
+}
+
+// SetMaxEntries changes how many entries are kept, trimming the oldest immediately if reduced.
+// Returns the number of entries dropped.
+func (l *Logger) SetMaxEntries(maxEntries int) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.maxEntries = maxEntries
+	dropped := max(len(l.entries)-maxEntries, 0)
+	if dropped > 0 {
+		// Copy so the dropped entries can be garbage collected
+		l.entries = append([]LogEntry(nil), l.entries[dropped:]...)
+	}
+	return dropped
+}
+
+// LogStats summarizes the in-memory log
+type LogStats struct {
+	Entries  int            `json:"entries"`
+	Capacity int            `json:"capacity"`
+	ByLevel  map[string]int `json:"by_level"`
+	Oldest   *time.Time     `json:"oldest,omitempty"`
+	Newest   *time.Time     `json:"newest,omitempty"`
+}
+
+// Stats returns the current entry count, capacity, and per-level counts
+func (l *Logger) Stats() LogStats {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	stats := LogStats{
+		Entries:  len(l.entries),
+		Capacity: l.maxEntries,
+		ByLevel: map[string]int{
+			LogLevelInfo.String():  0,
+			LogLevelWarn.String():  0,
+			LogLevelError.String(): 0,
+		},
+	}
+	for _, entry := range l.entries {
+		stats.ByLevel[entry.Level.String()]++
+	}
+	if len(l.entries) > 0 {
+		oldest := l.entries[0].Timestamp
+		newest := l.entries[len(l.entries)-1].Timestamp
+		stats.Oldest, stats.Newest = &oldest, &newest
+	}
+	return stats
+}
+
 // Clear removes all log entries
 func (l *Logger) Clear() {
 	l.mu.Lock()
@@ -15,12 +15,14 @@ type LogsPageView struct {
 	table         *tview.Table
 	controlPanel  *tview.Flex
 	filterButton  *tview.Button
+	sourceButton  *tview.Button
 	clearButton   *tview.Button
 	statusBar     *tview.TextView
 	selectedRow   int
-	focusedItem   int // 0: table, 1: filter button, 2: clear button
+	focusedItem   int // 0: table, 1: filter button, 2: source button, 3: clear button
 	showAllLevels bool
 	filterLevel   LogLevel
+	filterSource  string // Only show entries from this source ("" = all sources)
 }
 
 // NewLogsPageView creates a new logs page view
@@ -29,6 +31,7 @@ func NewLogsPageView(tuiApp *TUIApp) *LogsPageView {
 		tuiApp:        tuiApp,
 		table:         tview.NewTable(),
 		filterButton:  tview.NewButton("Filter: All"),
+		sourceButton:  tview.NewButton("Source: All"),
 		clearButton:   tview.NewButton("Clear Logs"),
 		statusBar:     tview.NewTextView(),
 		selectedRow:   0,
@@ -82,6 +85,9 @@ func (p *LogsPageView) setupTable() {
 			case 'f', 'F':
 				p.toggleFilter()
 				return nil
+			case 's', 'S':
+				p.toggleSourceFilter()
+				return nil
 			case 'c', 'C':
 				p.clearLogs()
 				return nil
@@ -100,6 +106,11 @@ func (p *LogsPageView) setupControls() {
 		p.toggleFilter()
 	})
 
+	// Source button setup
+	p.sourceButton.SetSelectedFunc(func() {
+		p.toggleSourceFilter()
+	})
+
 	// Clear button setup
 	p.clearButton.SetSelectedFunc(func() {
 		p.clearLogs()
@@ -107,13 +118,14 @@ func (p *LogsPageView) setupControls() {
 
 	// Style the buttons
 	p.filterButton.SetBackgroundColor(tcell.ColorDarkBlue)
+	p.sourceButton.SetBackgroundColor(tcell.ColorDarkBlue)
 	p.clearButton.SetBackgroundColor(tcell.ColorDarkRed)
 }
 
 // setupStatusBar configures the status bar
 func (p *LogsPageView) setupStatusBar() {
 	p.statusBar.SetDynamicColors(true)
-	p.statusBar.SetText("[yellow]Enter[white]: View Details | [yellow]Tab[white]: Switch panels | [yellow]f[white]: Filter | [yellow]c[white]: Clear | [yellow]↑↓[white]: Navigate\n[grey]Pages: [yellow]1[white]: Processes | [yellow]2[white]: Notifications | [yellow]3[white]: Agents Q&A | [yellow]4[white]: Logs | [yellow]5[white]: Features[grey]")
+	p.statusBar.SetText(statusBarText(LogsPage))
 	p.statusBar.SetBorder(true).SetBorderPadding(0, 0, 1, 1)
 	p.statusBar.SetBackgroundColor(tcell.ColorBlack)
 }
@@ -125,6 +137,8 @@ func (p *LogsPageView) setupLayout() {
 		SetDirection(tview.FlexColumn).
 		AddItem(p.filterButton, 0, 1, false).
 		AddItem(tview.NewBox(), 1, 0, false). // Spacer
+		AddItem(p.sourceButton, 0, 1, false).
+		AddItem(tview.NewBox(), 1, 0, false). // Spacer
 		AddItem(p.clearButton, 0, 1, false)
 	p.controlPanel.SetBackgroundColor(tcell.ColorBlack)
 
@@ -170,13 +184,7 @@ func (p *LogsPageView) Refresh() {
 		p.table.SetCell(0, i, cell)
 	}
 
-	// Get logs based on filter
-	var logs []LogEntry
-	if p.showAllLevels {
-		logs = GetLogEntries()
-	} else {
-		logs = logger.GetEntriesByLevel(p.filterLevel)
-	}
+	logs := p.filteredEntries()
 
 	// Update table title with count
 	title := fmt.Sprintf(" System Logs (%d entries) ", len(logs))
@@ -236,10 +244,36 @@ func (p *LogsPageView) Refresh() {
 	} else {
 		p.filterButton.SetLabel(fmt.Sprintf("Filter: %s", p.filterLevel.String()))
 	}
+	if p.filterSource == "" {
+		p.sourceButton.SetLabel("Source: All")
+	} else {
+		p.sourceButton.SetLabel(fmt.Sprintf("Source: %s", p.filterSource))
+	}
 
 	p.updateStatusBar()
 }
 
+// filteredEntries returns the log entries matching both the level and source filters
+func (p *LogsPageView) filteredEntries() []LogEntry {
+	var logs []LogEntry
+	if p.filterSource != "" {
+		logs = logger.GetEntriesBySource(p.filterSource)
+	} else {
+		logs = GetLogEntries()
+	}
+	if p.showAllLevels {
+		return logs
+	}
+
+	var filtered []LogEntry
+	for _, entry := range logs {
+		if entry.Level == p.filterLevel {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
 // toggleFilter cycles through filter options
 func (p *LogsPageView) toggleFilter() {
 	if p.showAllLevels {
@@ -255,6 +289,28 @@ func (p *LogsPageView) toggleFilter() {
 	p.Refresh()
 }
 
+// toggleSourceFilter cycles through All and each source currently in the log
+func (p *LogsPageView) toggleSourceFilter() {
+	p.filterSource = nextLogSource(logger.Sources(), p.filterSource)
+	p.Refresh()
+}
+
+// nextLogSource returns the source after current in sources, or "" (all) after the last one
+func nextLogSource(sources []string, current string) string {
+	if current == "" {
+		if len(sources) == 0 {
+			return ""
+		}
+		return sources[0]
+	}
+	for _, source := range sources {
+		if source > current {
+			return source // Works even if current has since been cleared from the log
+		}
+	}
+	return ""
+}
+
 // clearLogs clears all log entries
 func (p *LogsPageView) clearLogs() {
 	ClearLogs()
@@ -263,13 +319,7 @@ func (p *LogsPageView) clearLogs() {
 
 // showLogDetail shows the full details of the selected log entry
 func (p *LogsPageView) showLogDetail() {
-	// Get current logs based on filter
-	var logs []LogEntry
-	if p.showAllLevels {
-		logs = GetLogEntries()
-	} else {
-		logs = logger.GetEntriesByLevel(p.filterLevel)
-	}
+	logs := p.filteredEntries()
 
 	// Check if selection is valid (accounting for header row)
 	if p.selectedRow > 0 && p.selectedRow <= len(logs) {
@@ -280,7 +330,7 @@ func (p *LogsPageView) showLogDetail() {
 
 // updateStatusBar updates the status bar text
 func (p *LogsPageView) updateStatusBar() {
-	logs := GetLogEntries()
+	logs := p.filteredEntries()
 	if p.selectedRow > 0 && p.selectedRow <= len(logs) {
 		log := logs[p.selectedRow-1]
 		if log.Details != "" {
@@ -288,18 +338,18 @@ func (p *LogsPageView) updateStatusBar() {
 			return
 		}
 	}
-	p.statusBar.SetText("[yellow]Tab[white]: Switch panels | [yellow]f[white]: Filter | [yellow]c[white]: Clear | [yellow]↑↓[white]: Navigate\n[grey]Pages: [yellow]1[white]: Processes | [yellow]2[white]: Notifications | [yellow]3[white]: Agents Q&A | [yellow]4[white]: Logs | [yellow]5[white]: Features[grey]")
+	p.statusBar.SetText(statusBarText(LogsPage))
 }
 
 // focusNext moves focus to the next control
 func (p *LogsPageView) focusNext() {
-	p.focusedItem = (p.focusedItem + 1) % 3
+	p.focusedItem = (p.focusedItem + 1) % 4
 	p.updateFocus()
 }
 
 // focusPrev moves focus to the previous control
 func (p *LogsPageView) focusPrev() {
-	p.focusedItem = (p.focusedItem + 2) % 3
+	p.focusedItem = (p.focusedItem + 3) % 4
 	p.updateFocus()
 }
 
@@ -311,6 +361,8 @@ func (p *LogsPageView) updateFocus() {
 	case 1:
 		p.tuiApp.app.SetFocus(p.filterButton)
 	case 2:
+		p.tuiApp.app.SetFocus(p.sourceButton)
+	case 3:
 		p.tuiApp.app.SetFocus(p.clearButton)
 	}
 }
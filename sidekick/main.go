@@ -21,6 +21,9 @@ var version = "dev"
 // Global SSE server reference for session tracking
 var globalSSEServer *server.SSEServer
 
+// Global MCP server reference for dynamic resource registration
+var globalMCPServer *server.MCPServer
+
 // Global Streamable HTTP server reference for session tracking
 var globalStreamableHTTPServer *server.StreamableHTTPServer
 
@@ -141,6 +144,29 @@ func main() {
 	processesMode := flag.Bool("processes", false, "Enable process management tools (default: false)")
 	port := flag.String("port", "5050", "Port for SSE server (default: 5050)")
 	host := flag.String("host", "localhost", "Host for SSE server (default: localhost)")
+	maxQuestionBytes := flag.Int("max-question-bytes", 0, "Maximum question size in bytes for ask_specialist (default: 0 = unlimited)")
+	maxAnswerBytes := flag.Int("max-answer-bytes", 0, "Maximum answer size in bytes for answer_question (default: 0 = unlimited)")
+	truncateQA := flag.Bool("truncate-oversized-qa", false, "Truncate oversized questions/answers instead of rejecting them (default: false)")
+	var allowedWorkdirs, allowedSpawnCommands, envMasks stringListFlag
+	flag.Var(&allowedWorkdirs, "allowed-workdir", "Only spawn processes whose working directory is under this path (repeatable, default: unrestricted)")
+	flag.Var(&allowedSpawnCommands, "allowed-command", "Only spawn this command, matched exactly as given to spawn_process (repeatable, default: unrestricted)")
+	flag.Var(&envMasks, "env-mask", "Regex of environment variable names whose values get_process_status include_env masks, on top of the built-in secret names (repeatable)")
+	sseKeepAlive := flag.Duration("sse-keepalive", DefaultSSEKeepAlive, "Interval between SSE keepalive comments, 0 disables (default: 15s)")
+	maxTotalBufferBytes := flag.Int64("max-total-buffer-bytes", 0, "Maximum process output held in memory across all processes; over it, buffers of finished then least recently accessed processes are shrunk, never below 64KB per stream, and spawns whose buffers do not fit next to the output already held are refused (default: 0 = unlimited)")
+	maxSSEConnections := flag.Int("max-sse-connections", 0, "Maximum SSE streams open at once; further connects get 503 (default: 0 = unlimited)")
+	tuiRefresh := flag.Duration("tui-refresh", tuiRefreshInterval, "TUI refresh interval, e.g. 500ms or 2s (default: 1s)")
+	flag.StringVar(&stateDumpFile, "state-dump-file", "", "Write a JSON snapshot of tracked processes and sessions to this file on SIGTERM/SIGINT (default: disabled)")
+	maxSpawnDelay := flag.Duration("max-spawn-delay", msDuration(MaxSpawnDelay), "Maximum delay accepted by spawn_process and spawn_multiple_processes (default: 5m)")
+	logMaxEntries := flag.Int("log-max-entries", DefaultLogMaxEntries, "Number of log entries kept in memory for the Logs page (default: 1000)")
+	flag.DurationVar(&drainTimeout, "drain-timeout", DefaultDrainTimeout, "How long drain mode (SIGUSR1 or POST /drain) waits for running processes before shutting down (default: 30m)")
+	flag.BoolVar(&defaultCombineOutput, "default-combine-output", false, "Combine stderr into stdout for spawns that don't set combine_output, for tools that log progress to stderr (default: false)")
+	flag.StringVar(&defaultOutputStreams, "default-streams", "both", "streams value for get_partial_process_output and get_full_process_output calls that omit it: both, stdout or stderr (default: both)")
+	flag.BoolVar(&defaultCombineReads, "default-combine-reads", false, "Merge stdout and stderr chronologically on output reads of both streams that omit combine, and record line timestamps for spawns that omit timestamp_lines (default: false)")
+	maxOutputDelay := flag.Duration("max-output-delay", msDuration(MaxOutputDelay), "Maximum delay/timeout accepted by the output tools and run_with_stdin (default: 2m)")
+	maxFilterConcurrency := flag.Int("max-filter-concurrency", DefaultMaxFilterConcurrency, "Maximum output filter pipelines running at once; others wait briefly, then fail with 'filter busy' (default: 8)")
+	basePath := flag.String("base-path", "", "Mount all HTTP endpoints under this path prefix, e.g. /sidekick serves /sidekick/mcp/sse (default: none)")
+	qaProcessingTimeout := flag.Duration("qa-processing-timeout", 0, "Re-queue a question its specialist has held this long without answering, failing it once its retries are used up (default: 0, never)")
+	qaAuditLog := flag.String("qa-audit-log", "", "Append an NDJSON audit record for every answered or failed question to this file (default: disabled)")
 	flag.Parse()
 
 	if *versionFlag {
@@ -153,21 +179,119 @@ func main() {
 		fmt.Println("Error: TUI mode (--tui) is only available with SSE mode (--sse)")
 		os.Exit(1)
 	}
+	if *tuiRefresh < 100*time.Millisecond {
+		fmt.Println("Error: --tui-refresh must be at least 100ms")
+		os.Exit(1)
+	}
+	tuiRefreshInterval = *tuiRefresh
+	if *sseKeepAlive < 0 {
+		fmt.Println("Error: --sse-keepalive cannot be negative")
+		os.Exit(1)
+	}
+	if defaultOutputStreams != "both" && defaultOutputStreams != "stdout" && defaultOutputStreams != "stderr" {
+		fmt.Println("Error: --default-streams must be both, stdout or stderr")
+		os.Exit(1)
+	}
+	if *maxSSEConnections < 0 {
+		fmt.Println("Error: --max-sse-connections cannot be negative")
+		os.Exit(1)
+	}
+	if *maxTotalBufferBytes < 0 {
+		fmt.Println("Error: --max-total-buffer-bytes cannot be negative")
+		os.Exit(1)
+	}
+	if *maxQuestionBytes < 0 || *maxAnswerBytes < 0 {
+		fmt.Println("Error: --max-question-bytes and --max-answer-bytes cannot be negative")
+		os.Exit(1)
+	}
+	if *qaProcessingTimeout < 0 {
+		fmt.Println("Error: --qa-processing-timeout cannot be negative")
+		os.Exit(1)
+	}
+	if *maxSpawnDelay < time.Millisecond || *maxOutputDelay < time.Millisecond {
+		fmt.Println("Error: --max-spawn-delay and --max-output-delay must be at least 1ms")
+		os.Exit(1)
+	}
+	normalizedBasePath, err := normalizeBasePath(*basePath)
+	if err != nil {
+		fmt.Printf("Error: --base-path: %v\n", err)
+		os.Exit(1)
+	}
+	httpBasePath = normalizedBasePath
+	if drainTimeout <= 0 {
+		fmt.Println("Error: --drain-timeout must be positive")
+		os.Exit(1)
+	}
+	if *logMaxEntries < 1 || *logMaxEntries > MaxLogMaxEntries {
+		fmt.Printf("Error: --log-max-entries must be between 1 and %d\n", MaxLogMaxEntries)
+		os.Exit(1)
+	}
+	if *maxFilterConcurrency < 1 || *maxFilterConcurrency > MaxFilterConcurrencyLimit {
+		fmt.Printf("Error: --max-filter-concurrency must be between 1 and %d\n", MaxFilterConcurrencyLimit)
+		os.Exit(1)
+	}
+	logger.SetMaxEntries(*logMaxEntries)
+	filterSlots = newFilterLimiter(*maxFilterConcurrency)
+	MaxSpawnDelay = maxSpawnDelay.Milliseconds()
+	MaxOutputDelay = maxOutputDelay.Milliseconds()
+	agentQARegistry.SetSizeLimits(*maxQuestionBytes, *maxAnswerBytes, *truncateQA)
+	agentQARegistry.SetProcessingTimeout(*qaProcessingTimeout)
+	if *qaAuditLog != "" {
+		auditFile, err := openQAAuditLog(*qaAuditLog)
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		defer auditFile.Close()
+		agentQARegistry.SetAuditLog(auditFile)
+	}
+	spawnPolicyFlags = SpawnPolicyConfig{AllowedWorkdirs: allowedWorkdirs, AllowedCommands: allowedSpawnCommands}
+	if err := spawnPolicy.Configure(allowedWorkdirs, allowedSpawnCommands); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := configureEnvMask(envMasks); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	if *maxTotalBufferBytes > 0 {
+		bufferBudget.SetLimit(*maxTotalBufferBytes)
+		go bufferBudget.run(cleanupCtx)
+	}
+	serverRuntimeInfo = ServerRuntimeInfo{
+		SSEMode:       *sseMode,
+		TUIMode:       *tuiMode,
+		ProcessesMode: *processesMode,
+		Address:       fmt.Sprintf("%s:%s", *host, *port),
+	}
 
 	// 🛠️ Create hooks for session lifecycle management
 	hooks := &server.Hooks{}
+	hooks.AddOnRegisterSession(func(ctx context.Context, session server.ClientSession) {
+		sseConnections.connected(ctx, session.SessionID())
+		handleSessionRegistered(ctx, session.SessionID())
+	})
 	hooks.AddOnUnregisterSession(func(ctx context.Context, session server.ClientSession) {
 		sessionID := session.SessionID()
+		sseConnections.disconnected(sessionID)
 		handleSessionClosed(sessionID)
 	})
 
 	// 🛠️ Create a new MCP server
+	serverOptions := []server.ServerOption{
+		server.WithToolCapabilities(false),
+		server.WithHooks(hooks),
+	}
+	if *processesMode {
+		// Process output is exposed as process://{id}/stdout and process://{id}/stderr resources
+		serverOptions = append(serverOptions, server.WithResourceCapabilities(false, true))
+	}
 	s := server.NewMCPServer(
 		"Sidekick Notifications",
 		"1.0.0",
-		server.WithToolCapabilities(false),
-		server.WithHooks(hooks),
+		serverOptions...,
 	)
+	globalMCPServer = s
 
 	// 🗣️ Define and register the notifications_speak tool (macOS only)
 	if runtime.GOOS == "darwin" {
@@ -182,6 +306,13 @@ func main() {
 		s.AddTool(speakTool, handleSpeak)
 	}
 
+	// 🔔 Registered everywhere, so hosts without the speech backends find out why
+	testNotificationsTool := mcp.NewTool(
+		"test_notifications",
+		mcp.WithDescription("Fire a sample notification through every backend (system sound and speech on macOS, the Discord webhook if configured) and report which succeeded, failed, or are unavailable on this host"),
+	)
+	s.AddTool(testNotificationsTool, handleTestNotifications)
+
 	// 🔧 Define and register process management tools (only if enabled)
 	if *processesMode {
 		spawnProcessTool := mcp.NewTool(
@@ -200,14 +331,17 @@ func main() {
 			mcp.WithObject("env",
 				mcp.Description("Environment variables (optional)"),
 			),
+			mcp.WithString("env_file",
+				mcp.Description("Server-side dotenv file of KEY=VALUE lines to load, relative to working_dir. Supports # comments, 'export' and quoted values; entries in env take precedence (optional)"),
+			),
 			mcp.WithNumber("buffer_size",
 				mcp.Description("Ring buffer size in bytes (default: 10MB)"),
 			),
 			mcp.WithBoolean("combine_output",
-				mcp.Description("Whether to combine stdout and stderr into single stream (default: false)"),
+				mcp.Description(fmt.Sprintf("Whether to combine stdout and stderr into single stream (default: %t, set by --default-combine-output)", defaultCombineOutput)),
 			),
 			mcp.WithNumber("delay",
-				mcp.Description("Delay in milliseconds before starting process (max: 300000 = 5 minutes). With sync_delay=false, returns immediately with 'pending' status and executes after delay. With sync_delay=true, waits for delay then starts process before returning with 'running' status"),
+				mcp.Description(fmt.Sprintf("Delay in milliseconds before starting process (max: %d = %s). With sync_delay=false, returns immediately with 'pending' status and executes after delay. With sync_delay=true, waits for delay then starts process before returning with 'running' status", MaxSpawnDelay, msDuration(MaxSpawnDelay))),
 			),
 			mcp.WithBoolean("sync_delay",
 				mcp.Description("Controls delay behavior: false (default) = return immediately with 'pending' status, execute later; true = wait for delay, start process, then return with 'running' status"),
@@ -215,6 +349,65 @@ func main() {
 			mcp.WithString("name",
 				mcp.Description("Optional human-readable name for the process (non-unique)"),
 			),
+			mcp.WithBoolean("capture_git",
+				mcp.Description("Record the git branch and short commit of working_dir at spawn, returned as git_branch/git_commit in status and list (default: false)"),
+			),
+			mcp.WithObject("labels",
+				mcp.Description("Arbitrary key/value string tags (e.g. repo, task, ci_job), returned in list/status and usable as filters"),
+			),
+			mcp.WithString("idempotency_key",
+				mcp.Description("Client-supplied key that makes retries safe: if a process spawned with this key is still running or finished within the last 10 minutes, its process_id is returned (idempotent_replay: true) instead of spawning again"),
+			),
+			mcp.WithBoolean("timestamp_lines",
+				mcp.Description(fmt.Sprintf("Record when each output line is written, enabling since_ms_ago and combine on the output tools (default: %t, true with --default-combine-reads)", defaultCombineReads)),
+			),
+			mcp.WithBoolean("dedup_consecutive",
+				mcp.Description("Collapse runs of identical consecutive output lines: the first is kept and the rest become one '<line> (repeated xN)' line when the run ends, saving buffer space for chatty processes (default: false)"),
+			),
+			mcp.WithArray("on_exit_command",
+				mcp.Description("Command (argv array) to run after the process finishes, e.g. to notify or deploy. It gets SIDEKICK_PROCESS_ID, SIDEKICK_PROCESS_NAME, SIDEKICK_STATUS, SIDEKICK_EXIT_CODE, SIDEKICK_EXIT_REASON and SIDEKICK_SIGNAL, runs in working_dir under the same spawn policy and user, and is killed after 60s. Its outcome and first 8KB of output appear as on_exit_result in get_process_status and in the logs. Not run if the process fails to start"),
+				mcp.WithStringItems(),
+			),
+			mcp.WithBoolean("wait_on_main_only",
+				mcp.Description("Mark the process finished as soon as the main process exits, even if children it started (e.g. daemons) keep stdout/stderr open. Output is read for 500ms more, then the pipes are closed, so late child output is lost (default: false)"),
+			),
+			mcp.WithString("progress_regex",
+				mcp.Description("Regex matched against each output line; the latest match sets progress_current/progress_total/progress_percent in get_process_status and the TUI. Use groups (?P<current>...) and (?P<total>...), two plain groups (current, total), or one group holding a percentage, e.g. \"(\\d+)/(\\d+) files\" (optional)"),
+			),
+			mcp.WithArray("redact_patterns",
+				mcp.Description("Regexes (RE2) whose matches are replaced with *** in each output line before it is stored, so secrets never reach the buffer, the TUI or tool results. Applied per line, so a secret split across lines or by max_line_bytes truncation is not matched (optional)"),
+				mcp.WithStringItems(),
+			),
+			mcp.WithBoolean("redact_builtin",
+				mcp.Description("Also redact common credentials: AWS access keys and secret key assignments, bearer tokens, GitHub tokens, JWTs, and user:password in URLs (default: false)"),
+			),
+			mcp.WithBoolean("tee_stderr_to_logs",
+				mcp.Description(fmt.Sprintf("Also mirror each stderr line into sidekick's logs under the source 'proc:<name>' (first 8 characters of the ID if unnamed), to read it next to sidekick's own logs on the Logs page. At most %d lines per second are mirrored; redaction applies (default: false)", MaxTeeLinesPerSecond)),
+			),
+			mcp.WithString("ready_pattern",
+				mcp.Description("Regex matched against each output line; the first match marks the process ready, and get_process_status then reports ready_time and startup_duration (time from spawn to ready) next to exit_time and run_duration (optional)"),
+			),
+			mcp.WithBoolean("flush_partial",
+				mcp.Description(fmt.Sprintf("Commit a line the process has not finished (no trailing newline yet, e.g. a 'Password: ' prompt) to the output after %dms, so prompts are visible; the rest of the line is appended when its newline arrives, and get_partial_process_output reports partial_line: true meanwhile. Not combinable with dedup_consecutive (default: false)", PartialFlushDelay.Milliseconds())),
+			),
+			mcp.WithString("line_prefix",
+				mcp.Description("Prefix stored at the start of every output line, to tell processes apart in merged logs. Placeholders: {name} (command base name if unnamed), {pid}, {id} (first 8 characters), {stream}. Example: \"[{name}:{pid}] \" (default: none, output is stored raw)"),
+			),
+			mcp.WithNumber("max_line_bytes",
+				mcp.Description(fmt.Sprintf("Cap for a single output line in bytes; longer lines are cut and marked '[sidekick: line truncated]', protecting memory from minified bundles or binary blobs (default: %d, max: %d)", DefaultMaxLineBytes, MaxLineBytesLimit)),
+			),
+			mcp.WithNumber("cols",
+				mcp.Description("Terminal width for pty-backed processes, only recorded for now as no process is pty-backed yet (optional, requires rows)"),
+			),
+			mcp.WithNumber("rows",
+				mcp.Description("Terminal height for pty-backed processes, only recorded for now as no process is pty-backed yet (optional, requires cols)"),
+			),
+			mcp.WithString("run_as_user",
+				mcp.Description("Run the process as this user (Unix only). Sidekick must be running as root or as that user"),
+			),
+			mcp.WithNumber("run_as_uid",
+				mcp.Description("Run the process as this uid (Unix only). If run_as_user is also given, they must refer to the same user"),
+			),
 		)
 
 		getPartialProcessOutputTool := mcp.NewTool(
@@ -225,39 +418,123 @@ func main() {
 				mcp.Description("Process identifier"),
 			),
 			mcp.WithString("streams",
-				mcp.Description("Which streams to read from"),
+				mcp.Description(fmt.Sprintf("Which streams to read from (default: %s, set by --default-streams)", defaultOutputStreams)),
 				mcp.Enum("stdout", "stderr", "both"),
 			),
 			mcp.WithNumber("max_lines",
 				mcp.Description("Maximum lines to return (optional)"),
 			),
+			mcp.WithNumber("tail_bytes",
+				mcp.Description("Return only about the last N bytes, starting at a line boundary, applied before max_lines and filters. tail_omitted reports the bytes/lines left out per stream (optional)"),
+			),
 			mcp.WithArray("filters",
 				mcp.Description("Optional command pipeline - each element is [command, ...args]"),
 			),
+			mcp.WithString("preset",
+				mcp.Description("Optional named filter preset applied before filters: errors-only, json-pretty, last-50, or a custom preset from filter_presets in ~/.sidekick/config.json"),
+			),
 			mcp.WithNumber("delay",
-				mcp.Description("Delay before returning output in milliseconds (max: 120000 = 2 minutes). Smart delay with early termination - if process completes during delay, returns immediately with output"),
+				mcp.Description(fmt.Sprintf("Delay before returning output in milliseconds (max: %d = %s). Smart delay with early termination - if process completes during delay, returns immediately with output", MaxOutputDelay, msDuration(MaxOutputDelay))),
+			),
+			mcp.WithNumber("since_ms_ago",
+				mcp.Description("Return lines written within the last N milliseconds instead of reading from the cursor (cursor is left unchanged). Requires timestamp_lines=true at spawn"),
+			),
+			mcp.WithBoolean("combine",
+				mcp.Description(fmt.Sprintf("Merge stdout and stderr chronologically into stdout for this read (streams is ignored, combined: true in the result). Requires timestamp_lines=true at spawn; no effect on processes spawned with combine_output (default: %t, set by --default-combine-reads, which applies only when streams is both and the process has timestamps)", defaultCombineReads)),
+			),
+			mcp.WithNumber("gap_marker_ms",
+				mcp.Description("With combine, insert a '[sidekick] --- Ns gap ---' line wherever no output was written for longer than this many milliseconds, to show where the process paused (default: 0, no markers)"),
+			),
+			mcp.WithString("format",
+				mcp.Description("'text' (default) returns stdout/stderr as strings; 'lines' returns stdout_lines/stderr_lines arrays instead, split after max_lines and filters are applied, without line terminators"),
+				mcp.Enum("text", "lines"),
+			),
+			mcp.WithBoolean("skip_if_no_new",
+				mcp.Description("If the process is still running and nothing new is past the cursor after the delay, return only {no_new_output: true} with status and cursors instead of an empty payload (default: false)"),
 			),
 		)
 
 		getFullProcessOutputTool := mcp.NewTool(
 			"get_full_process_output",
-			mcp.WithDescription("Get the complete output from a process (all data in memory)"),
+			mcp.WithDescription("Get the complete output from a process (all data in memory). A terminated process stays readable for a minute after it is removed (reap_processes, TUI delete), flagged removed: true"),
 			mcp.WithString("process_id",
 				mcp.Required(),
 				mcp.Description("Process identifier"),
 			),
 			mcp.WithString("streams",
-				mcp.Description("Which streams to read from"),
+				mcp.Description(fmt.Sprintf("Which streams to read from (default: %s, set by --default-streams)", defaultOutputStreams)),
 				mcp.Enum("stdout", "stderr", "both"),
 			),
 			mcp.WithNumber("max_lines",
 				mcp.Description("Maximum lines to return (optional)"),
 			),
+			mcp.WithNumber("tail_bytes",
+				mcp.Description("Return only about the last N bytes, starting at a line boundary, applied before max_lines and filters. tail_omitted reports the bytes/lines left out per stream (optional)"),
+			),
 			mcp.WithArray("filters",
 				mcp.Description("Optional command pipeline - each element is [command, ...args]"),
 			),
+			mcp.WithString("preset",
+				mcp.Description("Optional named filter preset applied before filters: errors-only, json-pretty, last-50, or a custom preset from filter_presets in ~/.sidekick/config.json"),
+			),
+			mcp.WithBoolean("combine",
+				mcp.Description(fmt.Sprintf("Merge stdout and stderr chronologically into stdout for this read (streams is ignored, combined: true in the result). Requires timestamp_lines=true at spawn; no effect on processes spawned with combine_output (default: %t, set by --default-combine-reads, which applies only when streams is both and the process has timestamps)", defaultCombineReads)),
+			),
+			mcp.WithNumber("gap_marker_ms",
+				mcp.Description("With combine, insert a '[sidekick] --- Ns gap ---' line wherever no output was written for longer than this many milliseconds, to show where the process paused (default: 0, no markers)"),
+			),
+			mcp.WithString("compress",
+				mcp.Description("Output encoding: 'none' (default) or 'gzip' to return stdout/stderr as base64(gzip(content)) with compressed: true and original/compressed byte counts"),
+			),
+			mcp.WithString("format",
+				mcp.Description("'text' (default) returns stdout/stderr as strings; 'lines' returns stdout_lines/stderr_lines arrays instead, split after max_lines and filters are applied, without line terminators. Not compatible with compress"),
+				mcp.Enum("text", "lines"),
+			),
 			mcp.WithNumber("delay",
-				mcp.Description("Delay before returning output in milliseconds (max: 120000 = 2 minutes). Smart delay with early termination - if process completes during delay, returns immediately with output"),
+				mcp.Description(fmt.Sprintf("Delay before returning output in milliseconds (max: %d = %s). Smart delay with early termination - if process completes during delay, returns immediately with output", MaxOutputDelay, msDuration(MaxOutputDelay))),
+			),
+		)
+
+		diffProcessOutputTool := mcp.NewTool(
+			"diff_process_output",
+			mcp.WithDescription("Compare the buffered output of two processes (e.g. a run before and after a change) and return a unified diff per stream with lines_added/lines_removed, computed in sidekick. Recently removed processes can still be compared"),
+			mcp.WithString("process_id_a",
+				mcp.Required(),
+				mcp.Description("Process whose output is the old side (---)"),
+			),
+			mcp.WithString("process_id_b",
+				mcp.Required(),
+				mcp.Description("Process whose output is the new side (+++)"),
+			),
+			mcp.WithString("streams",
+				mcp.Description("Which streams to compare (default: stdout)"),
+				mcp.Enum("stdout", "stderr", "both"),
+			),
+			mcp.WithNumber("context",
+				mcp.Description(fmt.Sprintf("Unchanged lines shown around each change (default: %d, max: %d)", DefaultDiffContext, MaxDiffContext)),
+			),
+			mcp.WithNumber("max_lines",
+				mcp.Description(fmt.Sprintf("Maximum diff lines returned per stream, flagged truncated: true when cut (default: %d, -1 for no limit)", DefaultDiffMaxLines)),
+			),
+		)
+
+		pipeProcessesTool := mcp.NewTool(
+			"pipe_processes",
+			mcp.WithDescription("Stream a process's stdout into another running process's stdin as it arrives, like a shell pipe, until the source exits. Returns a pipe_id at once; the pipe's state, bytes_piped and any error show under pipes in get_process_status of either process. The pipe fails if the destination exits, its stdin is closed, or it stops reading for 30s. Source output evicted from its buffer before it is piped is counted in lost_bytes"),
+			mcp.WithString("source_process_id",
+				mcp.Required(),
+				mcp.Description("Process whose stdout is read (with combine_output this includes stderr)"),
+			),
+			mcp.WithString("dest_process_id",
+				mcp.Required(),
+				mcp.Description("Running process whose stdin receives the output"),
+			),
+			mcp.WithString("from",
+				mcp.Description("'new' (default) pipes only output produced from now on; 'start' also pipes the source output still buffered"),
+				mcp.Enum("new", "start"),
+			),
+			mcp.WithBoolean("close_stdin",
+				mcp.Description("Close the destination's stdin once the source has exited and its output is delivered, so the destination sees EOF (default: true)"),
 			),
 		)
 
@@ -277,18 +554,279 @@ func main() {
 			),
 		)
 
+		broadcastInputTool := mcp.NewTool(
+			"broadcast_input",
+			mcp.WithDescription("Send the same input to several running processes' stdin at once (e.g. a cluster of REPLs or workers), chosen by process_ids and/or a labels selector. Returns success or the error per process"),
+			mcp.WithArray("process_ids",
+				mcp.Description("Processes to send the input to"),
+				mcp.WithStringItems(),
+			),
+			mcp.WithObject("labels",
+				mcp.Description("Also send to every running process whose labels include all of these key/value pairs"),
+			),
+			mcp.WithString("input",
+				mcp.Required(),
+				mcp.Description("Input data to send to each process's stdin"),
+			),
+			mcp.WithBoolean("auto_newline",
+				mcp.Description("Automatically append newline character to input (default: true)"),
+			),
+		)
+
+		runProcessTool := mcp.NewTool(
+			"run_process",
+			mcp.WithDescription("Spawn a command, wait for it to exit, and return its full output and exit code in one call - spawn_process, waiting and get_full_process_output in a single round-trip for one-shot commands. Accepts the spawn_process parameters (command, args, working_dir, env, combine_output, buffer_size, labels, ...). A command still running at timeout_ms is killed unless keep is true"),
+			mcp.WithString("command",
+				mcp.Required(),
+				mcp.Description("Command to execute"),
+			),
+			mcp.WithArray("args",
+				mcp.Description("Command arguments"),
+			),
+			mcp.WithString("working_dir",
+				mcp.Description("Working directory (optional)"),
+			),
+			mcp.WithObject("env",
+				mcp.Description("Environment variables (optional)"),
+			),
+			mcp.WithBoolean("combine_output",
+				mcp.Description(fmt.Sprintf("Whether to combine stdout and stderr into single stream (default: %t, set by --default-combine-output)", defaultCombineOutput)),
+			),
+			mcp.WithNumber("timeout_ms",
+				mcp.Description(fmt.Sprintf("Maximum milliseconds to wait for the command to exit (default: %d, max: %d). On timeout timed_out is true and the process is killed, unless keep is set", DefaultRunProcessTimeout, MaxOutputDelay)),
+			),
+			mcp.WithBoolean("keep",
+				mcp.Description("Keep the process tracked afterwards and return its process_id; on a timeout it is left running (default: false, the process is removed once done)"),
+			),
+		)
+
+		runWithStdinTool := mcp.NewTool(
+			"run_with_stdin",
+			mcp.WithDescription("Write input to a running process, close its stdin (EOF), wait for it to exit, and return the complete output and exit code. Ideal for filter commands like sort or wc"),
+			mcp.WithString("process_id",
+				mcp.Required(),
+				mcp.Description("Process identifier"),
+			),
+			mcp.WithString("input",
+				mcp.Description("Input data to write before closing stdin (sent as-is, no newline is appended)"),
+			),
+			mcp.WithNumber("timeout",
+				mcp.Description(fmt.Sprintf("Maximum milliseconds to wait for the process to exit (default: 30000, max: %d). On timeout the process keeps running and timed_out is true", MaxOutputDelay)),
+			),
+		)
+
+		pipeFileToProcessTool := mcp.NewTool(
+			"pipe_file_to_process",
+			mcp.WithDescription("Stream a server-side file into a running process's stdin in chunks, without loading it into memory. Use for large inputs (datasets, tarballs)"),
+			mcp.WithString("process_id",
+				mcp.Required(),
+				mcp.Description("Process identifier"),
+			),
+			mcp.WithString("path",
+				mcp.Required(),
+				mcp.Description("Path of the file to stream (must be under --allowed-workdir when set)"),
+			),
+			mcp.WithBoolean("close_stdin",
+				mcp.Description("Close stdin (send EOF) after the file is written (default: false)"),
+			),
+			mcp.WithNumber("max_bytes",
+				mcp.Description("Refuse files larger than this many bytes (default and max: 1GB)"),
+			),
+			mcp.WithNumber("timeout",
+				mcp.Description("Maximum milliseconds for the whole transfer, including waiting on a process that reads slowly (default: 60000, max: 600000)"),
+			),
+		)
+
 		listProcessesTool := mcp.NewTool(
 			"list_processes",
 			mcp.WithDescription("List all tracked processes and their status"),
+			mcp.WithObject("labels",
+				mcp.Description("Only list processes carrying all of these key/value labels (optional)"),
+			),
+			mcp.WithBoolean("include_last_line",
+				mcp.Description(fmt.Sprintf("Add each process's most recent non-empty output line as last_line (and last_stderr_line), up to %d characters, for an at-a-glance status (default: false)", MaxLastLinePreview)),
+			),
 		)
 
 		killProcessTool := mcp.NewTool(
 			"kill_process",
-			mcp.WithDescription("Terminate a tracked process"),
+			mcp.WithDescription("Terminate a tracked process and wait for it to exit. 'confirmed' is true once the process is gone; false means the signal was sent but the process was still alive when timeout_ms ran out"),
 			mcp.WithString("process_id",
 				mcp.Required(),
 				mcp.Description("Process identifier"),
 			),
+			mcp.WithNumber("timeout_ms",
+				mcp.Description("How long to wait for the process to exit, in milliseconds (default: 5000, max: 60000, 0 = don't wait)"),
+			),
+		)
+
+		killAllProcessesTool := mcp.NewTool(
+			"kill_all_processes",
+			mcp.WithDescription("Terminate all running and pending processes, optionally only those matching a label selector"),
+			mcp.WithObject("labels",
+				mcp.Description("Only kill processes carrying all of these key/value labels (optional, default: all processes)"),
+			),
+		)
+
+		setProcessWinsizeTool := mcp.NewTool(
+			"set_process_winsize",
+			mcp.WithDescription("Set the terminal window size of a pty-backed process so full-screen programs redraw correctly. No process is pty-backed yet: spawned processes use pipes, so the size is only recorded (applied: false)"),
+			mcp.WithString("process_id",
+				mcp.Required(),
+				mcp.Description("Process identifier"),
+			),
+			mcp.WithNumber("cols",
+				mcp.Required(),
+				mcp.Description("Terminal width in columns"),
+			),
+			mcp.WithNumber("rows",
+				mcp.Required(),
+				mcp.Description("Terminal height in rows"),
+			),
+		)
+
+		resizeProcessBufferTool := mcp.NewTool(
+			"resize_process_buffer",
+			mcp.WithDescription("Change the output buffer size of a tracked process without restarting it. Shrinking discards the oldest buffered output immediately"),
+			mcp.WithString("process_id",
+				mcp.Required(),
+				mcp.Description("Process identifier"),
+			),
+			mcp.WithNumber("buffer_size",
+				mcp.Required(),
+				mcp.Description("New buffer size in bytes (max: 100MB)"),
+			),
+		)
+
+		reapProcessesTool := mcp.NewTool(
+			"reap_processes",
+			mcp.WithDescription("Remove finished (completed, failed, or killed) processes from the registry in bulk. Running and pending processes are never touched. Returns how many were reaped"),
+			mcp.WithString("status",
+				mcp.Description("Only reap processes with this status: completed, failed, or killed (optional, default: all terminated)"),
+			),
+			mcp.WithNumber("older_than_ms",
+				mcp.Description("Only reap processes that finished at least this many milliseconds ago (optional, default: 0)"),
+			),
+		)
+
+		listFilterCommandsTool := mcp.NewTool(
+			"list_filter_commands",
+			mcp.WithDescription("List the commands allowed in output filters and whether each one is installed on this host's PATH. Use it to pick filters that will actually run"),
+		)
+
+		subscribeProcessOutputTool := mcp.NewTool(
+			"subscribe_process_output",
+			mcp.WithDescription("Subscribe to a running process's output resource (process://{id}/stdout or stderr). The server then sends notifications/resources/updated for that URI as output arrives (at most every 250ms) so you can read the resource instead of polling. Subscriptions end when the process exits"),
+			mcp.WithString("process_id",
+				mcp.Required(),
+				mcp.Description("Process identifier"),
+			),
+			mcp.WithString("stream",
+				mcp.Description("Stream to follow (default: stdout). Combined-output processes only have stdout"),
+				mcp.Enum("stdout", "stderr"),
+			),
+		)
+
+		unsubscribeProcessOutputTool := mcp.NewTool(
+			"unsubscribe_process_output",
+			mcp.WithDescription("Stop output update notifications for a process stream"),
+			mcp.WithString("process_id",
+				mcp.Required(),
+				mcp.Description("Process identifier"),
+			),
+			mcp.WithString("stream",
+				mcp.Description("Stream to stop following (default: stdout)"),
+				mcp.Enum("stdout", "stderr"),
+			),
+		)
+
+		waitForOutputPatternTool := mcp.NewTool(
+			"wait_for_output_pattern",
+			mcp.WithDescription("Block until a line of process output matches a regex, the process exits, or the timeout expires - the primitive for readiness checks (e.g. 'Listening on') instead of polling output. Returns the matching line, the match, and the line's byte offset in its stream. Does not move the get_partial_process_output cursors"),
+			mcp.WithString("process_id",
+				mcp.Required(),
+				mcp.Description("Process identifier"),
+			),
+			mcp.WithString("pattern",
+				mcp.Required(),
+				mcp.Description("Regular expression (Go RE2 syntax) matched against each output line"),
+			),
+			mcp.WithNumber("timeout_ms",
+				mcp.Description(fmt.Sprintf("Maximum time to wait in milliseconds (default: %d, max: %d)", DefaultPatternWaitTimeout, MaxOutputDelay)),
+			),
+			mcp.WithString("streams",
+				mcp.Description("Streams to search (default: both). Combined-output processes only have stdout"),
+				mcp.Enum("stdout", "stderr", "both"),
+			),
+			mcp.WithString("search",
+				mcp.Description("'new' searches output not yet read with get_partial_process_output, plus anything that arrives while waiting; 'full' also searches everything still in the buffer (default: new)"),
+				mcp.Enum("new", "full"),
+			),
+		)
+
+		getOutputContextTool := mcp.NewTool(
+			"get_output_context",
+			mcp.WithDescription("Get the lines around a position in a process's output - a line number, or a byte offset such as the one wait_for_output_pattern returns - without re-reading the whole buffer. Lines are numbered from the start of the stream, so numbers stay valid as old output is trimmed; a position that was trimmed returns whatever of its window is left, with target_trimmed: true. Does not move the read cursors"),
+			mcp.WithString("process_id",
+				mcp.Required(),
+				mcp.Description("Process identifier"),
+			),
+			mcp.WithNumber("line_number",
+				mcp.Description("1-based line number in the stream (give this or byte_offset)"),
+			),
+			mcp.WithNumber("byte_offset",
+				mcp.Description("Absolute byte offset in the stream; the window centers on the line containing it (give this or line_number)"),
+			),
+			mcp.WithNumber("before",
+				mcp.Description(fmt.Sprintf("Lines to include before the target (default: %d, max: %d)", DefaultContextLines, MaxContextLines)),
+			),
+			mcp.WithNumber("after",
+				mcp.Description(fmt.Sprintf("Lines to include after the target (default: %d, max: %d)", DefaultContextLines, MaxContextLines)),
+			),
+			mcp.WithString("stream",
+				mcp.Description("Stream to read (default: stdout). Combined-output processes only have stdout"),
+				mcp.Enum("stdout", "stderr"),
+			),
+		)
+
+		attachProcessTool := mcp.NewTool(
+			"attach_process",
+			mcp.WithDescription("Attach to a background process and stream its new output live until a detach condition: the process exits, a line matches detach_pattern, output goes quiet for idle_ms, or window_ms ends (then call again to stay attached). Chunks arrive as progress notifications when the request carries a progress token, and the result always holds everything streamed. Moves the get_partial_process_output cursors"),
+			mcp.WithString("process_id",
+				mcp.Required(),
+				mcp.Description("Process identifier"),
+			),
+			mcp.WithNumber("window_ms",
+				mcp.Description(fmt.Sprintf("How long this call stays attached in milliseconds (default: %d, max: %d)", DefaultAttachWindow, MaxOutputDelay)),
+			),
+			mcp.WithString("detach_pattern",
+				mcp.Description("Regular expression (Go RE2 syntax); detach once a new output line matches it (optional)"),
+			),
+			mcp.WithNumber("idle_ms",
+				mcp.Description("Detach after this many milliseconds without new output (default: 0, never)"),
+			),
+			mcp.WithNumber("max_bytes",
+				mcp.Description(fmt.Sprintf("Return once this much output was collected, still attached (default: %d)", DefaultAttachMaxBytes)),
+			),
+		)
+
+		exportProcessesTool := mcp.NewTool(
+			"export_processes",
+			mcp.WithDescription("Export a snapshot of all tracked processes (id, name, session, pid, command, args, working_dir, status, start/end time, duration, exit code and reason, labels - no output) as JSON or CSV, written to a file or returned inline. The TUI Processes page exports its table the same way with E"),
+			mcp.WithString("format",
+				mcp.Description("Export format (default: json)"),
+				mcp.Enum("json", "csv"),
+			),
+			mcp.WithString("path",
+				mcp.Description("Absolute path of the file to write (mode 0600), under an --allowed-workdir when set; omit to return the export inline as content"),
+			),
+			mcp.WithString("status",
+				mcp.Description("Only export processes with this status: pending, running, completed, failed, or killed (optional)"),
+				mcp.Enum("pending", "running", "completed", "failed", "killed"),
+			),
+			mcp.WithObject("labels",
+				mcp.Description("Only export processes whose labels include all of these key/value pairs (optional)"),
+			),
 		)
 
 		getProcessStatusTool := mcp.NewTool(
@@ -298,14 +836,90 @@ func main() {
 				mcp.Required(),
 				mcp.Description("Process identifier"),
 			),
+			mcp.WithBoolean("include_env",
+				mcp.Description("Include the environment variables the spawn set (env and env_file, on top of sidekick's own environment). Values of secret-looking names (token, password, key, ... and --env-mask patterns) are replaced by '***' and listed in env_masked; credentials inside other values are redacted (default: false)"),
+			),
+		)
+
+		resolveCommandTool := mcp.NewTool(
+			"resolve_command",
+			mcp.WithDescription("Dry run of spawn_process: show the absolute binary path found on PATH, the final argv, the environment variable names (values are not returned), the effective working_dir, and whether the spawn policy allows it - without running anything"),
+			mcp.WithString("command",
+				mcp.Required(),
+				mcp.Description("Command to resolve"),
+			),
+			mcp.WithArray("args",
+				mcp.Description("Command arguments"),
+			),
+			mcp.WithString("working_dir",
+				mcp.Description("Working directory (optional)"),
+			),
+			mcp.WithObject("env",
+				mcp.Description("Environment variables (optional)"),
+			),
+			mcp.WithString("env_file",
+				mcp.Description("Server-side dotenv file to load, relative to working_dir; env takes precedence (optional)"),
+			),
+		)
+
+		processStatsTool := mcp.NewTool(
+			"process_stats",
+			mcp.WithDescription("Summarize all tracked processes in one call: counts by status, total buffered and written output bytes, session counts, the oldest running process, and the processes holding the most output"),
+			mcp.WithNumber("top",
+				mcp.Description(fmt.Sprintf("How many processes to list in top_by_buffer (default: %d, max: %d)", DefaultStatsTop, MaxStatsTop)),
+			),
+		)
+
+		benchmarkSpawnTool := mcp.NewTool(
+			"benchmark_spawn",
+			mcp.WithDescription("Measure sidekick's spawn overhead on this host: spawn a trivial command runs times (sequentially or concurrently), then report min/avg/p95/max milliseconds from the spawn call until the process is running (spawn_to_running) and until it exits (spawn_to_exit). The benchmark processes are removed afterwards"),
+			mcp.WithString("command",
+				mcp.Description("Command to spawn (default: 'true'; 'cmd /c exit 0' on Windows). Spawn policies apply as for spawn_process"),
+			),
+			mcp.WithArray("args",
+				mcp.Description("Arguments for command"),
+				mcp.WithStringItems(),
+			),
+			mcp.WithString("working_dir",
+				mcp.Description("Working directory for the spawned command (optional)"),
+			),
+			mcp.WithNumber("runs",
+				mcp.Description(fmt.Sprintf("How many times to spawn the command (default: %d, max: %d)", DefaultBenchmarkRuns, MaxBenchmarkRuns)),
+			),
+			mcp.WithNumber("concurrency",
+				mcp.Description(fmt.Sprintf("How many spawns run at once (default: 1 = sequential, max: %d)", MaxBenchmarkConcurrency)),
+			),
+		)
+
+		reapZombiesTool := mcp.NewTool(
+			"reap_zombies",
+			mcp.WithDescription("Best-effort cleanup of defunct (zombie) processes in the process groups of tracked processes, which get_process_status reports as zombies/has_zombies. Zombies whose parent is sidekick are collected; for the others the parent is sent SIGCHLD as a hint, since only a zombie's own parent can reap it. Linux only"),
+			mcp.WithString("process_id",
+				mcp.Description("Only this process's group (default: all running processes)"),
+			),
+		)
+
+		adoptProcessTool := mcp.NewTool(
+			"adopt_process",
+			mcp.WithDescription("Start tracking a process that was launched outside sidekick, by PID. The process shows up in list_processes/get_process_status, its exit is detected by polling, and kill_process can terminate it. Past and future output can't be captured and input can't be sent, since it is not a child of sidekick; its exit code is unknown. Adopted processes are left running when sidekick shuts down"),
+			mcp.WithNumber("pid",
+				mcp.Required(),
+				mcp.Description("PID of the process to adopt"),
+			),
+			mcp.WithString("name",
+				mcp.Description("Optional human-readable name for the process"),
+			),
+			mcp.WithObject("labels",
+				mcp.Description("Arbitrary key/value tags for filtering (optional)"),
+			),
 		)
 
 		spawnMultipleProcessesTool := mcp.NewTool(
 			"spawn_multiple_processes",
-			mcp.WithDescription("Spawn multiple processes sequentially with individual delays. Delays are cumulative (each delay occurs after previous process scheduled). In async mode (sync_delay=false for any process with delay>0), returns immediately - initial no-delay processes show 'running', first delayed process and all subsequent show 'pending'. In sync mode (all sync_delay=true), waits for all processes to start before returning with 'running' status"),
+			mcp.WithDescription("Spawn multiple processes sequentially with individual delays. Delays are cumulative: process N starts delay_0 + ... + delay_N ms after the call (start_offset_ms in each result), however long earlier processes took to start. In async mode (sync_delay=false for any process with delay>0), returns immediately - initial no-delay processes show 'running', first delayed process and all subsequent show 'pending'. In sync mode (all sync_delay=true), waits for all processes to start before returning with 'running' status"),
 			mcp.WithArray("processes",
 				mcp.Required(),
-				mcp.Description("Array of process configurations. Each supports: command (required), args, name, working_dir, env, buffer_size, delay (ms), sync_delay (bool). Delays are sequential - process N waits for its delay after process N-1 is scheduled"),
+				mcp.Description("Array of process configurations. Each supports: command (required), args, name, working_dir, env, buffer_size, delay (ms), sync_delay (bool), capture_git (bool), timestamp_lines (bool), line_prefix, labels (object). Delays are cumulative - process N starts its delay after process N-1's scheduled start. All entries are validated before anything is spawned: unknown fields and wrong types are rejected with the index (0-based) of the offending entry"),
 			),
 		)
 
@@ -314,10 +928,32 @@ func main() {
 		s.AddTool(spawnMultipleProcessesTool, handleSpawnMultipleProcesses)
 		s.AddTool(getPartialProcessOutputTool, handleGetPartialProcessOutput)
 		s.AddTool(getFullProcessOutputTool, handleGetFullProcessOutput)
+		s.AddTool(diffProcessOutputTool, handleDiffProcessOutput)
+		s.AddTool(waitForOutputPatternTool, handleWaitForOutputPattern)
+		s.AddTool(getOutputContextTool, handleGetOutputContext)
+		s.AddTool(attachProcessTool, handleAttachProcess)
+		s.AddTool(exportProcessesTool, handleExportProcesses)
 		s.AddTool(sendProcessInputTool, handleSendProcessInput)
+		s.AddTool(broadcastInputTool, handleBroadcastInput)
+		s.AddTool(runWithStdinTool, handleRunWithStdin)
+		s.AddTool(runProcessTool, handleRunProcess)
+		s.AddTool(pipeFileToProcessTool, handlePipeFileToProcess)
+		s.AddTool(pipeProcessesTool, handlePipeProcesses)
 		s.AddTool(listProcessesTool, handleListProcesses)
 		s.AddTool(killProcessTool, handleKillProcess)
+		s.AddTool(killAllProcessesTool, handleKillAllProcesses)
+		s.AddTool(resizeProcessBufferTool, handleResizeProcessBuffer)
+		s.AddTool(setProcessWinsizeTool, handleSetProcessWinsize)
+		s.AddTool(reapProcessesTool, handleReapProcesses)
 		s.AddTool(getProcessStatusTool, handleGetProcessStatus)
+		s.AddTool(processStatsTool, handleProcessStats)
+		s.AddTool(benchmarkSpawnTool, handleBenchmarkSpawn)
+		s.AddTool(resolveCommandTool, handleResolveCommand)
+		s.AddTool(reapZombiesTool, handleReapZombies)
+		s.AddTool(adoptProcessTool, handleAdoptProcess)
+		s.AddTool(listFilterCommandsTool, handleListFilterCommands)
+		s.AddTool(subscribeProcessOutputTool, handleSubscribeProcessOutput)
+		s.AddTool(unsubscribeProcessOutputTool, handleUnsubscribeProcessOutput)
 	}
 
 	// 🤝 Define agent communication tools
@@ -337,7 +973,7 @@ func main() {
 
 	getNextQuestionTool := mcp.NewTool(
 		"get_next_question",
-		mcp.WithDescription("Wait for and retrieve the next question for this specialist. Creates or joins a directory for the specified specialty. Blocks if no questions are available."),
+		mcp.WithDescription("Wait for and retrieve the next question for this specialist. Creates or joins a directory for the specified specialty. Blocks if no questions are available. Questions from an asker with a timeout include deadline and remaining_ms; answers after the deadline are rejected."),
 		mcp.WithString("name",
 			mcp.Required(),
 			mcp.Description("Agent name"),
@@ -347,8 +983,10 @@ func main() {
 			mcp.Description("Specialty area (e.g., 'codebase', 'testing', 'security', 'flutter', 'convex', 'firebase-backend')"),
 		),
 		mcp.WithString("root_dir",
-			mcp.Required(),
-			mcp.Description("Root directory of the project"),
+			mcp.Description("Root directory of the project (required unless global is true)"),
+		),
+		mcp.WithBoolean("global",
+			mcp.Description("Use the org-wide directory for this specialty instead of a project's, ignoring root_dir. ask_specialist falls back to it for projects without a directory of their own (default: false)"),
 		),
 		mcp.WithString("instructions",
 			mcp.Description("Usage instructions for potential questioners (optional)"),
@@ -361,6 +999,72 @@ func main() {
 		),
 	)
 
+	registerSpecialistTool := mcp.NewTool(
+		"register_specialist",
+		mcp.WithDescription("Register (or update) a specialist directory without waiting for questions, so askers can discover it via list_specialists right away. Use get_next_question to actually wait for questions."),
+		mcp.WithString("specialty",
+			mcp.Required(),
+			mcp.Description("Specialty area (e.g., 'codebase', 'testing', 'security', 'flutter', 'convex', 'firebase-backend')"),
+		),
+		mcp.WithString("root_dir",
+			mcp.Description("Root directory of the project (required unless global is true)"),
+		),
+		mcp.WithBoolean("global",
+			mcp.Description("Use the org-wide directory for this specialty instead of a project's, ignoring root_dir. ask_specialist falls back to it for projects without a directory of their own (default: false)"),
+		),
+		mcp.WithString("instructions",
+			mcp.Description("Usage instructions for potential questioners (optional)"),
+		),
+	)
+
+	updateSpecialistInstructionsTool := mcp.NewTool(
+		"update_specialist_instructions",
+		mcp.WithDescription("Replace the usage instructions of an existing specialist directory, whether or not a specialist is currently waiting. Each change bumps instruction_version, visible to askers via list_specialists"),
+		mcp.WithString("specialty",
+			mcp.Required(),
+			mcp.Description("Specialty of the directory"),
+		),
+		mcp.WithString("root_dir",
+			mcp.Required(),
+			mcp.Description("Root directory of the project"),
+		),
+		mcp.WithString("instructions",
+			mcp.Required(),
+			mcp.Description("New usage instructions for questioners (empty string clears them)"),
+		),
+	)
+
+	deleteDirectoryTool := mcp.NewTool(
+		"delete_directory",
+		mcp.WithDescription("Delete a specialist directory together with its question queue and Q&A history, e.g. for an abandoned project. Unresolved questions are failed and their waiting askers released. Returns counts of what was removed"),
+		mcp.WithString("key",
+			mcp.Required(),
+			mcp.Description("Directory key as returned by list_specialists (\"<root_dir>-<specialty>\")"),
+		),
+		mcp.WithBoolean("force",
+			mcp.Description("Delete even if a specialist is currently waiting for questions, cancelling its wait (default: false)"),
+		),
+	)
+
+	replayQuestionsTool := mcp.NewTool(
+		"replay_questions",
+		mcp.WithDescription("Re-ask a directory's most recent completed questions as new questions (new IDs), e.g. to compare answers after updating a specialist. Returns the new question IDs for get_answers"),
+		mcp.WithString("key",
+			mcp.Required(),
+			mcp.Description("Directory key as returned by list_specialists (\"<root_dir>-<specialty>\")"),
+		),
+		mcp.WithNumber("count",
+			mcp.Required(),
+			mcp.Description(fmt.Sprintf("How many of the most recent completed questions to replay (max: %d)", MaxReplayQuestions)),
+		),
+		mcp.WithString("from",
+			mcp.Description("Asker name for the replays (default: each original asker)"),
+		),
+		mcp.WithBoolean("tag_as_replay",
+			mcp.Description("Mark each replay with replay_of, the original question ID, visible to the specialist (default: true)"),
+		),
+	)
+
 	askSpecialistTool := mcp.NewTool(
 		"ask_specialist",
 		mcp.WithDescription("Ask a question to a specialist agent. IMPORTANT: Always call list_specialists first to verify a specialist exists for the specialty and root_dir, otherwise this call will fail. If wait=true (default), blocks until answer is available."),
@@ -380,7 +1084,19 @@ func main() {
 			mcp.Description("Whether to wait for the answer (default: true). Recommended: use wait=true in most cases to get the answer immediately. Only set wait=false if you are doing other work in parallel and will call get_answer later to retrieve the response."),
 		),
 		mcp.WithNumber("timeout",
-			mcp.Description("Timeout in milliseconds if wait=true (optional, default 0 = no timeout)"),
+			mcp.Description("Timeout in milliseconds if wait=true (optional, default 0 = no timeout). The specialist sees it as the question's deadline, and the question fails if it is not answered in time"),
+		),
+		mcp.WithNumber("retries",
+			mcp.Description("How many times to re-queue the question if the specialist handling it goes away before answering, so a restarted specialist can pick it up (default: 3, max: 10). The result includes retry_count."),
+		),
+		mcp.WithString("specialist_name",
+			mcp.Description("Direct the question at the specialist with this name (see list_specialists). Only that specialist will pick it up (optional)"),
+		),
+		mcp.WithBoolean("strict",
+			mcp.Description("With specialist_name: fail if that specialist is not currently waiting in the directory instead of sending the question to any specialist (default: false)"),
+		),
+		mcp.WithBoolean("enqueue_if_no_waiter",
+			mcp.Description(fmt.Sprintf("Queue the question for the next specialist to call get_next_question when none is live in the directory (default: true, up to %d pending questions per directory). Set to false to fail immediately instead of waiting on a directory nobody serves.", MaxPendingQuestions)),
 		),
 	)
 
@@ -389,6 +1105,19 @@ func main() {
 		mcp.WithDescription("List all active specialist agents. MUST be called before ask_specialist to verify a specialist is available for your specialty and root_dir."),
 	)
 
+	isSpecialistAvailableTool := mcp.NewTool(
+		"is_specialist_available",
+		mcp.WithDescription("Check whether ask_specialist would reach a live specialist for this specialty and root_dir, without asking anything. Call it before ask_specialist with wait=true to avoid blocking on a directory no specialist is serving."),
+		mcp.WithString("specialty",
+			mcp.Required(),
+			mcp.Description("Specialty to check"),
+		),
+		mcp.WithString("root_dir",
+			mcp.Required(),
+			mcp.Description("Root directory of the project (falls back to a global specialist, as ask_specialist does)"),
+		),
+	)
+
 	getAnswerTool := mcp.NewTool(
 		"get_answer",
 		mcp.WithDescription("Get the answer for a previously asked question. If answer is not yet available, waits until it is (respecting timeout if provided)."),
@@ -401,18 +1130,95 @@ func main() {
 		),
 	)
 
+	getAnswersTool := mcp.NewTool(
+		"get_answers",
+		mcp.WithDescription("Get the answers for several previously asked questions in one call. By default waits until all are answered or failed, or until the shared timeout; each entry reports its current status either way."),
+		mcp.WithArray("question_ids",
+			mcp.Required(),
+			mcp.Description("IDs of previously asked questions (max 100)"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithNumber("timeout",
+			mcp.Description("Shared deadline for all answers in milliseconds (optional, default 0 = no timeout)"),
+		),
+		mcp.WithBoolean("wait",
+			mcp.Description("Wait for unresolved questions (default: true). false returns the current state immediately"),
+		),
+	)
+
 	getSystemHealthTool := mcp.NewTool(
 		"get_system_health",
 		mcp.WithDescription("Get diagnostic information about the Q&A system health, including active waiters and channel status."),
 	)
 
+	dumpStateTool := mcp.NewTool(
+		"dump_state",
+		mcp.WithDescription("Get a JSON snapshot of everything sidekick is tracking: all processes (metadata only, no output) and client sessions. Useful for debugging and crash forensics"),
+	)
+
+	reloadConfigTool := mcp.NewTool(
+		"reload_config",
+		mcp.WithDescription("Re-read ~/.sidekick/config.json and apply the settings that are safe to change at runtime (filter_presets, limits, spawn_policy) without dropping connections or processes. The config is validated first; an invalid file changes nothing. Returns the list of settings that changed. Sending SIGHUP does the same"),
+	)
+
+	setLogCapacityTool := mcp.NewTool(
+		"set_log_capacity",
+		mcp.WithDescription("Change how many log entries sidekick keeps in memory (the Logs page history). Reducing it drops the oldest entries immediately"),
+		mcp.WithNumber("max_entries",
+			mcp.Required(),
+			mcp.Description(fmt.Sprintf("New capacity (1 to %d)", MaxLogMaxEntries)),
+		),
+	)
+
+	logStatsTool := mcp.NewTool(
+		"log_stats",
+		mcp.WithDescription("Get the in-memory log's entry count, capacity, per-level counts, and oldest/newest entry times"),
+	)
+
+	setMaintenanceModeTool := mcp.NewTool(
+		"set_maintenance_mode",
+		mcp.WithDescription("Turn maintenance mode on or off: while on, spawn_process, ask_specialist and get_next_question fail with a maintenance mode error, while running processes and questions already asked carry on. /healthz and the TUI header show it. Omit enabled to only query the current state"),
+		mcp.WithBoolean("enabled",
+			mcp.Description("true to refuse new spawns and questions, false to accept them again (omit to query)"),
+		),
+		mcp.WithString("reason",
+			mcp.Description("Why maintenance mode is on, included in the errors clients get (optional)"),
+		),
+	)
+
+	connectionStatsTool := mcp.NewTool(
+		"connection_stats",
+		mcp.WithDescription("Get the current, pending and peak number of open SSE connections, the --max-sse-connections cap, and how many connects it rejected. Pass max_connections to change the cap at runtime; open connections are never closed"),
+		mcp.WithNumber("max_connections",
+			mcp.Description("New cap on open SSE connections, 0 for unlimited (optional)"),
+		),
+	)
+
+	serverInfoTool := mcp.NewTool(
+		"server_info",
+		mcp.WithDescription("Get the sidekick version, platform, enabled transports, configured limits, and which optional features are active"),
+	)
+
 	// 🔗 Register agent communication tools
 	s.AddTool(answerQuestionTool, handleAnswerQuestion)
 	s.AddTool(getNextQuestionTool, handleGetNextQuestion)
+	s.AddTool(registerSpecialistTool, handleRegisterSpecialist)
+	s.AddTool(updateSpecialistInstructionsTool, handleUpdateSpecialistInstructions)
+	s.AddTool(deleteDirectoryTool, handleDeleteDirectory)
+	s.AddTool(replayQuestionsTool, handleReplayQuestions)
 	s.AddTool(askSpecialistTool, handleAskSpecialist)
 	s.AddTool(listSpecialistsTool, handleListSpecialists)
+	s.AddTool(isSpecialistAvailableTool, handleIsSpecialistAvailable)
 	s.AddTool(getAnswerTool, handleGetAnswer)
+	s.AddTool(getAnswersTool, handleGetAnswers)
 	s.AddTool(getSystemHealthTool, handleGetSystemHealth)
+	s.AddTool(serverInfoTool, handleServerInfo)
+	s.AddTool(connectionStatsTool, handleConnectionStats)
+	s.AddTool(setMaintenanceModeTool, handleSetMaintenanceMode)
+	s.AddTool(dumpStateTool, handleDumpState)
+	s.AddTool(reloadConfigTool, handleReloadConfig)
+	s.AddTool(setLogCapacityTool, handleSetLogCapacity)
+	s.AddTool(logStatsTool, handleLogStats)
 
 	// 🎯 Auto-start keybindings watcher if previously enabled
 	cfg, cfgErr := LoadConfig()
@@ -426,6 +1232,40 @@ func main() {
 		}
 	}
 
+	// 🧰 Apply reloadable settings from the config (filter presets, limits, spawn policy)
+	if cfgErr == nil {
+		if _, err := applyReloadableConfig(cfg); err != nil {
+			LogWarn("Main", "Ignoring invalid settings in config", err.Error())
+		} else if len(cfg.FilterPresets) > 0 {
+			LogInfo("Main", fmt.Sprintf("Loaded %d custom filter presets from config", len(cfg.FilterPresets)))
+		}
+	}
+
+	// 🔄 Reload the config on SIGHUP without dropping connections or processes
+	hupChan := make(chan os.Signal, 1)
+	signal.Notify(hupChan, syscall.SIGHUP)
+	go func() {
+		for range hupChan {
+			reloadConfig("SIGHUP")
+		}
+	}()
+
+	// 🚰 Enter drain mode on SIGUSR1 (HTTP mode): refuse new sessions, shut down once processes finish
+	if sig := drainSignal(); sig != nil && *sseMode {
+		drainChan := make(chan os.Signal, 1)
+		signal.Notify(drainChan, sig)
+		go func() {
+			for range drainChan {
+				startDrain("SIGUSR1")
+			}
+		}()
+	}
+
+	if *processesMode {
+		LogInfo("Main", fmt.Sprintf("Delay caps: spawn %s, output %s", msDuration(MaxSpawnDelay), msDuration(MaxOutputDelay)))
+		go watchZombies()
+	}
+
 	// 🚦 Setup signal handling for graceful shutdown
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
@@ -436,8 +1276,10 @@ func main() {
 	if *sseMode {
 		// SSE mode
 		config := SSEServerConfig{
-			Host: *host,
-			Port: *port,
+			Host:              *host,
+			Port:              *port,
+			KeepAlive:         *sseKeepAlive,
+			MaxSSEConnections: *maxSSEConnections,
 		}
 
 		// Start TUI if requested
@@ -496,6 +1338,7 @@ func main() {
 			select {
 			case <-sigChan:
 				// Handle OS signal (Ctrl+C, SIGTERM, etc.)
+				dumpStateOnSignal()
 				shutdownOnce.Do(func() {
 					close(shutdownChan)
 				})
@@ -531,6 +1374,7 @@ func main() {
 		// Handle signals for stdio mode
 		go func() {
 			<-sigChan
+			dumpStateOnSignal()
 			handleGracefulShutdown()
 			os.Exit(0)
 		}()
@@ -542,16 +1386,18 @@ func main() {
 }
 
 // getRunningProcesses returns all currently running or pending processes
-// This includes pending delayed spawns that haven't started yet
+// This includes pending delayed spawns that haven't started yet, but not adopted
+// processes: sidekick didn't start them, so shutdown leaves them running
 func getRunningProcesses() []*ProcessTracker {
 	processes := registry.getAllProcesses()
 	var running []*ProcessTracker
 	for _, tracker := range processes {
 		tracker.Mutex.RLock()
 		status := tracker.Status
+		adopted := tracker.Adopted
 		tracker.Mutex.RUnlock()
 
-		if status == StatusRunning || status == StatusPending {
+		if (status == StatusRunning || status == StatusPending) && !adopted {
 			running = append(running, tracker)
 		}
 	}
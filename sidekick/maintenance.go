@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maintenanceState is the quiesce switch (set_maintenance_mode): while enabled, new spawns and
+// new questions are refused, and everything already running keeps going
+type maintenanceState struct {
+	mu      sync.RWMutex
+	enabled bool
+	reason  string
+	since   time.Time
+}
+
+var maintenance = &maintenanceState{}
+
+// MaintenanceStatus describes maintenance mode for set_maintenance_mode and /healthz
+type MaintenanceStatus struct {
+	Enabled bool       `json:"enabled"`
+	Reason  string     `json:"reason,omitempty"`
+	Since   *time.Time `json:"since,omitempty"`
+}
+
+// set turns maintenance mode on or off, reporting whether it changed
+func (m *maintenanceState) set(enabled bool, reason string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	changed := m.enabled != enabled
+	m.enabled = enabled
+	if !enabled {
+		m.reason, m.since = "", time.Time{}
+	} else {
+		m.reason = reason
+		if changed {
+			m.since = time.Now()
+		}
+	}
+	return changed
+}
+
+func (m *maintenanceState) isEnabled() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.enabled
+}
+
+func (m *maintenanceState) status() MaintenanceStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if !m.enabled {
+		return MaintenanceStatus{}
+	}
+	since := m.since
+	return MaintenanceStatus{Enabled: true, Reason: m.reason, Since: &since}
+}
+
+// refuse returns the error for new work of the given kind while in maintenance mode, or nil
+func (m *maintenanceState) refuse(what string) *mcp.CallToolResult {
+	status := m.status()
+	if !status.Enabled {
+		return nil
+	}
+	message := fmt.Sprintf("Sidekick is in maintenance mode and not accepting new %s; work already running is unaffected", what)
+	if status.Reason != "" {
+		message += fmt.Sprintf(" (reason: %s)", status.Reason)
+	}
+	return mcp.NewToolResultError(message)
+}
+
+// handleSetMaintenanceMode turns maintenance mode on or off, or reports it when enabled is omitted
+func handleSetMaintenanceMode(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	changed := false
+	if arguments, ok := request.Params.Arguments.(map[string]any); ok {
+		if _, exists := arguments["enabled"]; exists {
+			enabled := getBoolArg(request, "enabled", false)
+			reason := getStringArg(request, "reason", "")
+			changed = maintenance.set(enabled, reason)
+			if changed && enabled {
+				LogWarn("Server", "Maintenance mode on: refusing new spawns and questions", fmt.Sprintf("Reason: %s", reason))
+			} else if changed {
+				LogInfo("Server", "Maintenance mode off")
+			}
+		}
+	}
+
+	result := map[string]any{
+		"maintenance": maintenance.status(),
+		"changed":     changed,
+	}
+	resultBytes, _ := json.Marshal(result)
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
@@ -7,11 +7,12 @@ import (
 	"fmt"
 	"net/http"
 	"os/exec"
+	"runtime"
 	"strings"
 	"sync"
 	"time"
 
-	"github.com/mark3labs/mcp-go/mcp" // Used by handleSpeak
+	"github.com/mark3labs/mcp-go/mcp" // Used by handleSpeak and handleTestNotifications
 )
 
 // Shared HTTP client with timeout for Discord webhook calls
@@ -224,3 +225,77 @@ func TestDiscordWebhook() error {
 	}
 	return sendDiscordMessage(cfg.Discord.WebhookURL, "Test notification from Sidekick")
 }
+
+// NotificationTestTimeout bounds each backend tried by test_notifications
+const NotificationTestTimeout = 15 * time.Second
+
+// NotificationBackendResult is the outcome of one notification backend in test_notifications
+type NotificationBackendResult struct {
+	Backend string `json:"backend"`
+	Status  string `json:"status"` // ok, failed, unavailable (not on this host) or skipped (not configured)
+	Detail  string `json:"detail,omitempty"`
+}
+
+// testNotificationCommand runs one command-line notification backend the way handleSpeak
+// would, but waits for it so failures can be reported
+func testNotificationCommand(ctx context.Context, backend, name string, args ...string) NotificationBackendResult {
+	result := NotificationBackendResult{Backend: backend}
+	if runtime.GOOS != "darwin" {
+		result.Status, result.Detail = "unavailable", fmt.Sprintf("%s is only used on macOS (this host is %s)", name, runtime.GOOS)
+		return result
+	}
+	path, err := exec.LookPath(name)
+	if err != nil {
+		result.Status, result.Detail = "unavailable", fmt.Sprintf("%s not found in PATH", name)
+		return result
+	}
+
+	cmdCtx, cancel := context.WithTimeout(ctx, NotificationTestTimeout)
+	defer cancel()
+	if output, err := exec.CommandContext(cmdCtx, path, args...).CombinedOutput(); err != nil {
+		result.Status, result.Detail = "failed", err.Error()
+		if text := strings.TrimSpace(string(output)); text != "" {
+			result.Detail += ": " + text
+		}
+		return result
+	}
+	result.Status = "ok"
+	return result
+}
+
+// handleTestNotifications fires a sample notification through every backend (system sound,
+// speech and the Discord webhook) and reports which ones worked on this host. It ignores the
+// sound setting and does not add to the notification history.
+func handleTestNotifications(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	backends := []NotificationBackendResult{
+		testNotificationCommand(ctx, "sound", "afplay", "/System/Library/Sounds/Glass.aiff", "-v", "5"),
+		testNotificationCommand(ctx, "speech", "say", "-v", "Zoe (Premium)", "Sidekick notification test"),
+	}
+
+	discord := NotificationBackendResult{Backend: "discord"}
+	if !IsDiscordWebhookConfigured() {
+		discord.Status, discord.Detail = "skipped", "no webhook configured"
+	} else if err := TestDiscordWebhook(); err != nil {
+		discord.Status, discord.Detail = "failed", err.Error()
+	} else {
+		discord.Status = "ok"
+	}
+	backends = append(backends, discord)
+
+	working := 0
+	for _, backend := range backends {
+		if backend.Status == "ok" {
+			working++
+		}
+	}
+	LogInfo("Notifications", "Notification test finished", fmt.Sprintf("Working backends: %d of %d", working, len(backends)))
+
+	result := map[string]any{
+		"platform":      runtime.GOOS + "/" + runtime.GOARCH,
+		"sound_enabled": notificationManager.IsSoundEnabled(),
+		"working":       working,
+		"backends":      backends,
+	}
+	resultBytes, _ := json.Marshal(result)
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
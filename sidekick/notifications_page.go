@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
@@ -17,8 +18,9 @@ type NotificationsPageView struct {
 	clearButton     *tview.Button
 	statusBar       *tview.TextView
 	selectedRow     int
-	focusedItem     int // 0: table, 1: sound toggle, 2: clear button
-	lastHistorySize int // Cache for incremental updates
+	focusedItem     int       // 0: table, 1: sound toggle, 2: clear button
+	lastHistorySize int       // Cache for incremental updates
+	lastViewed      time.Time // Notifications after this are marked NEW
 }
 
 // NewNotificationsPageView creates a new notifications page view
@@ -32,6 +34,7 @@ func NewNotificationsPageView(tuiApp *TUIApp) *NotificationsPageView {
 		selectedRow:     0,
 		focusedItem:     0,
 		lastHistorySize: 0,
+		lastViewed:      time.Now(),
 	}
 	
 	p.setupTable()
@@ -90,7 +93,7 @@ func (p *NotificationsPageView) setupControls() {
 // setupStatusBar configures the status bar
 func (p *NotificationsPageView) setupStatusBar() {
 	p.statusBar.SetBorder(true).SetTitle(" Controls ").SetTitleAlign(tview.AlignLeft)
-	p.statusBar.SetText("[yellow]↑↓[white]: Navigate | [yellow]Tab[white]: Switch Focus | [yellow]Enter[white]: Activate | [yellow]Esc[white]: Back | [yellow]Q[white]: Quit\n[grey]Pages: [yellow]1[white]: Processes | [yellow]2[white]: Notifications | [yellow]3[white]: Agents Q&A | [yellow]4[white]: Logs | [yellow]5[white]: Features[grey]")
+	p.statusBar.SetText(statusBarText(NotificationsPage))
 	p.statusBar.SetTextAlign(tview.AlignCenter)
 	p.statusBar.SetDynamicColors(true)
 }
@@ -134,6 +137,13 @@ func (p *NotificationsPageView) handleTableKeys(event *tcell.EventKey) *tcell.Ev
 	case tcell.KeyTab:
 		p.switchFocus()
 		return nil
+	case tcell.KeyRune:
+		switch event.Rune() {
+		case 'a', 'A':
+			p.MarkViewed()
+			p.Refresh()
+			return nil
+		}
 	}
 	return event
 }
@@ -236,6 +246,38 @@ func (p *NotificationsPageView) clearHistory() {
 	p.Refresh()
 }
 
+// MarkViewed moves the last-viewed marker to now, clearing the NEW markers on the next rebuild
+func (p *NotificationsPageView) MarkViewed() {
+	p.lastViewed = time.Now()
+}
+
+// formatMessage prefixes notifications received since the page was last viewed with a NEW marker
+func (p *NotificationsPageView) formatMessage(entry NotificationEntry, message string) string {
+	if entry.Timestamp.After(p.lastViewed) {
+		return "[yellow::b]NEW[-:-:-] " + message
+	}
+	return message
+}
+
+// historyTitle builds the table title with the total and new notification counts
+func (p *NotificationsPageView) historyTitle(history []NotificationEntry) string {
+	newCount := 0
+	for _, entry := range history {
+		if entry.Timestamp.After(p.lastViewed) {
+			newCount++
+		}
+	}
+
+	title := fmt.Sprintf(" Notification History (%d) ", len(history))
+	if newCount > 0 {
+		title = fmt.Sprintf(" Notification History (%d, %d new) ", len(history), newCount)
+	}
+	if p.focusedItem == 0 {
+		title += "[FOCUSED]"
+	}
+	return title
+}
+
 // Refresh refreshes the notifications list
 func (p *NotificationsPageView) Refresh() {
 	p.populateTable()
@@ -273,17 +315,13 @@ func (p *NotificationsPageView) populateTable() {
 		p.table.SetCell(row, 0, tview.NewTableCell(timeStr).
 			SetTextColor(tcell.ColorLightBlue).
 			SetAlign(tview.AlignCenter))
-		p.table.SetCell(row, 1, tview.NewTableCell(message).
+		p.table.SetCell(row, 1, tview.NewTableCell(p.formatMessage(entry, message)).
 			SetTextColor(tcell.ColorWhite).
 			SetExpansion(1))
 	}
 	
 	// Update title with count
-	title := fmt.Sprintf(" Notification History (%d) ", len(history))
-	if p.focusedItem == 0 {
-		title += "[FOCUSED]"
-	}
-	p.table.SetTitle(title)
+	p.table.SetTitle(p.historyTitle(history))
 	
 	// Restore selection if possible
 	if p.selectedRow > 0 && p.selectedRow < p.table.GetRowCount() {
@@ -326,15 +364,11 @@ func (p *NotificationsPageView) populateTableIncremental() {
 			
 			// IDIOMATIC: Insert row instead of rebuilding
 			p.table.SetCell(row, 0, tview.NewTableCell(timeStr).SetTextColor(tcell.ColorLightBlue))
-			p.table.SetCell(row, 1, tview.NewTableCell(message).SetTextColor(tcell.ColorWhite))
+			p.table.SetCell(row, 1, tview.NewTableCell(p.formatMessage(entry, message)).SetTextColor(tcell.ColorWhite))
 		}
 		
 		// Update the title with new count
-		title := fmt.Sprintf(" Notification History (%d) ", len(history))
-		if p.focusedItem == 0 {
-			title += "[FOCUSED]"
-		}
-		p.table.SetTitle(title)
+		p.table.SetTitle(p.historyTitle(history))
 		
 		p.lastHistorySize = len(history)
 		
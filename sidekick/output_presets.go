@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// builtinFilterPresets are named filter pipelines available to the output tools
+var builtinFilterPresets = map[string][][]string{
+	"errors-only": {{"grep", "-iE", "error|warn"}},
+	"json-pretty": {{"jq", "."}},
+	"last-50":     {{"tail", "-n", "50"}},
+}
+
+var (
+	customFilterPresets   map[string][][]string
+	customFilterPresetsMu sync.RWMutex
+)
+
+// SetCustomFilterPresets installs presets from the config file; they override built-ins of the same name
+func SetCustomFilterPresets(presets map[string][][]string) {
+	customFilterPresetsMu.Lock()
+	defer customFilterPresetsMu.Unlock()
+	customFilterPresets = presets
+}
+
+// CustomFilterPresets returns the presets currently installed from the config file
+func CustomFilterPresets() map[string][][]string {
+	customFilterPresetsMu.RLock()
+	defer customFilterPresetsMu.RUnlock()
+	return customFilterPresets
+}
+
+// resolveFilterPreset expands a preset name into its filter pipeline
+func resolveFilterPreset(name string) ([][]string, error) {
+	customFilterPresetsMu.RLock()
+	filters, exists := customFilterPresets[name]
+	customFilterPresetsMu.RUnlock()
+
+	if !exists {
+		filters, exists = builtinFilterPresets[name]
+	}
+	if !exists {
+		return nil, fmt.Errorf("unknown preset '%s' (available: %s)", name, strings.Join(filterPresetNames(), ", "))
+	}
+
+	// Return a copy so callers can append their own filters safely
+	return slices.Clone(filters), nil
+}
+
+// filterPresetNames returns all preset names, sorted
+func filterPresetNames() []string {
+	customFilterPresetsMu.RLock()
+	defer customFilterPresetsMu.RUnlock()
+
+	names := make([]string, 0, len(builtinFilterPresets)+len(customFilterPresets))
+	for name := range builtinFilterPresets {
+		names = append(names, name)
+	}
+	for name := range customFilterPresets {
+		if _, builtin := builtinFilterPresets[name]; !builtin {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
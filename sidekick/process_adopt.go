@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// AdoptPollInterval is how often an adopted process is checked for exit
+const AdoptPollInterval = 250 * time.Millisecond
+
+// adoptLimitations is returned by adopt_process so callers know what an adopted tracker can't do
+var adoptLimitations = []string{
+	"Output is not captured: the process is not a child of sidekick, so its stdout/stderr can't be reattached",
+	"Input can't be sent: sidekick has no handle on its stdin",
+	"The exit code is unknown: the process ends as 'completed' with exit_reason 'adopted_exited'",
+	"Liveness is polled by PID; if the process exits and the PID is reused, the new process is tracked instead",
+}
+
+// handleAdoptProcess starts tracking a process that was launched outside sidekick
+func handleAdoptProcess(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	pid := getIntArg(request, "pid", 0)
+	if pid <= 0 {
+		return mcp.NewToolResultError("Missing or invalid 'pid' argument"), nil
+	}
+	if pid == os.Getpid() {
+		return mcp.NewToolResultError("Cannot adopt sidekick itself"), nil
+	}
+	if !processAlive(pid) {
+		return mcp.NewToolResultError(fmt.Sprintf("No running process with PID %d", pid)), nil
+	}
+
+	for _, existing := range registry.getAllProcesses() {
+		existing.Mutex.RLock()
+		tracked := existing.PID == pid && existing.Status == StatusRunning
+		existingID := existing.ID
+		existing.Mutex.RUnlock()
+		if tracked {
+			return mcp.NewToolResultError(fmt.Sprintf("PID %d is already tracked as process %s", pid, existingID)), nil
+		}
+	}
+
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to open PID %d: %v", pid, err)), nil
+	}
+
+	command, args := fmt.Sprintf("pid %d", pid), []string{}
+	if argv, err := processCommandLine(pid); err == nil {
+		command, args = argv[0], argv[1:]
+	}
+
+	now := time.Now()
+	tracker := &ProcessTracker{
+		ID:           uuid.New().String(),
+		Name:         getStringArg(request, "name", ""),
+		PID:          pid,
+		Command:      command,
+		Args:         args,
+		StartTime:    now, // When tracking began; the real start time is unknown
+		LastAccessed: now,
+		Status:       StatusRunning,
+		StdoutBuffer: NewRingBuffer(DefaultBufferSize), // Stays empty, so the output tools still work
+		StderrBuffer: NewRingBuffer(DefaultBufferSize),
+		Process:      &exec.Cmd{Process: process}, // Never started; only carries the handle used to signal it
+		Labels:       getStringMapArg(request, "labels"),
+		Adopted:      true,
+	}
+	if workingDir, err := os.Readlink(fmt.Sprintf("/proc/%d/cwd", pid)); err == nil {
+		tracker.WorkingDir = workingDir
+	}
+
+	registry.addProcess(tracker)
+	go monitorAdoptedProcess(tracker)
+
+	LogInfo("Process", "Process adopted: "+command, fmt.Sprintf("PID: %d, ID: %s, command: %s", pid, tracker.ID, strings.Join(append([]string{command}, args...), " ")))
+
+	result := map[string]any{
+		"process_id":  tracker.ID,
+		"pid":         pid,
+		"command":     command,
+		"args":        args,
+		"status":      string(StatusRunning),
+		"limitations": adoptLimitations,
+	}
+	if tracker.WorkingDir != "" {
+		result["working_dir"] = tracker.WorkingDir
+	}
+
+	resultBytes, _ := json.Marshal(result)
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// monitorAdoptedProcess stands in for the exit goroutine of spawned processes: it polls the
+// PID and records the end once the process is gone
+func monitorAdoptedProcess(tracker *ProcessTracker) {
+	ticker := time.NewTicker(AdoptPollInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if processAlive(tracker.PID) {
+			continue
+		}
+
+		tracker.Mutex.Lock()
+		if tracker.Status == StatusKilled {
+			tracker.ExitReason = ExitReasonKilled
+		} else {
+			tracker.Status = StatusCompleted
+			tracker.ExitReason = ExitReasonAdoptedExited
+		}
+		captureProcessEndTime(tracker)
+		tracker.Mutex.Unlock()
+
+		LogInfo("Process", "Adopted process exited: "+tracker.Command, fmt.Sprintf("PID: %d, ID: %s", tracker.PID, tracker.ID))
+		return
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const (
+	DefaultAttachWindow   = 30000      // How long one attach_process call streams when no window_ms is given
+	DefaultAttachMaxBytes = 256 * 1024 // Output one attach_process call collects before returning
+)
+
+// attachSession is one attach_process call following a process's output from its read cursors
+type attachSession struct {
+	ctx      context.Context
+	tracker  *ProcessTracker
+	token    mcp.ProgressToken // Set when the client asked for progress notifications
+	stdout   strings.Builder
+	stderr   strings.Builder
+	chunks   int
+	lastData time.Time
+}
+
+// read moves the read cursors past the output written since the last read, collecting it and
+// streaming it to the client as progress notifications when a progress token was given
+func (a *attachSession) read() {
+	tracker := a.tracker
+	tracker.Mutex.Lock()
+	stdout := tracker.StdoutBuffer.GetContentFromCursor(tracker.StdoutCursor)
+	tracker.StdoutCursor = tracker.StdoutBuffer.TotalBytes()
+	var stderr string
+	if tracker.StderrBuffer != nil {
+		stderr = tracker.StderrBuffer.GetContentFromCursor(tracker.StderrCursor)
+		tracker.StderrCursor = tracker.StderrBuffer.TotalBytes()
+	}
+	tracker.Mutex.Unlock()
+
+	a.collect("stdout", stdout, &a.stdout)
+	a.collect("stderr", stderr, &a.stderr)
+}
+
+// collect records one stream's new output as a chunk
+func (a *attachSession) collect(stream, chunk string, into *strings.Builder) {
+	if chunk == "" {
+		return
+	}
+	into.WriteString(chunk)
+	a.chunks++
+	a.lastData = time.Now()
+
+	if a.token == nil {
+		return
+	}
+	mcpServer := server.ServerFromContext(a.ctx)
+	if mcpServer == nil {
+		return
+	}
+	err := mcpServer.SendNotificationToClient(a.ctx, "notifications/progress", map[string]any{
+		"progressToken": a.token,
+		"progress":      a.chunks,
+		"message":       chunk,
+		"stream":        stream,
+	})
+	if err != nil {
+		a.token = nil // The client is not listening; the output still comes back in the result
+	}
+}
+
+// size returns how much output the call has collected
+func (a *attachSession) size() int {
+	return a.stdout.Len() + a.stderr.Len()
+}
+
+// handleAttachProcess streams a background process's new output until a detach condition:
+// the process exits, a line matches detach_pattern, output goes idle, or the window ends.
+// Output arrives as progress notifications when the client sends a progress token, and is
+// always returned in the result; the read cursors move as with get_partial_process_output.
+func handleAttachProcess(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	processID, err := request.RequireString("process_id")
+	if err != nil {
+		return mcp.NewToolResultError("Missing or invalid 'process_id' argument"), nil
+	}
+
+	windowMs := getInt64Arg(request, "window_ms", DefaultAttachWindow)
+	if windowMs <= 0 || windowMs > MaxOutputDelay {
+		return mcp.NewToolResultError(fmt.Sprintf("window_ms must be between 1 and %d milliseconds (%s)", MaxOutputDelay, msDuration(MaxOutputDelay))), nil
+	}
+	idleMs := getInt64Arg(request, "idle_ms", 0)
+	if idleMs < 0 {
+		return mcp.NewToolResultError("idle_ms cannot be negative"), nil
+	}
+	maxBytes := getIntArg(request, "max_bytes", DefaultAttachMaxBytes)
+	if maxBytes <= 0 {
+		return mcp.NewToolResultError("max_bytes must be positive"), nil
+	}
+
+	var detachPattern *regexp.Regexp
+	if pattern := getStringArg(request, "detach_pattern", ""); pattern != "" {
+		if detachPattern, err = regexp.Compile(pattern); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid detach_pattern: %v", err)), nil
+		}
+	}
+
+	tracker, exists := registry.getProcess(processID)
+	if !exists {
+		return mcp.NewToolResultError(fmt.Sprintf("Process %s not found", processID)), nil
+	}
+
+	// detach_pattern looks at the lines from the cursors on, the same output the call returns
+	tracker.Mutex.RLock()
+	scanners := []*outputPatternScanner{{stream: "stdout", buffer: tracker.StdoutBuffer, pos: tracker.StdoutCursor}}
+	if tracker.StderrBuffer != nil {
+		scanners = append(scanners, &outputPatternScanner{stream: "stderr", buffer: tracker.StderrBuffer, pos: tracker.StderrCursor})
+	}
+	tracker.Mutex.RUnlock()
+
+	start := time.Now()
+	attach := &attachSession{ctx: ctx, tracker: tracker, lastData: start}
+	if meta := request.Params.Meta; meta != nil {
+		attach.token = meta.ProgressToken
+	}
+
+	window := time.NewTimer(time.Duration(windowMs) * time.Millisecond)
+	defer window.Stop()
+	ticker := time.NewTicker(time.Duration(DelayCheckInterval) * time.Millisecond)
+	defer ticker.Stop()
+
+	result := func(reason string, match *patternMatch) *mcp.CallToolResult {
+		tracker.Mutex.RLock()
+		response := map[string]any{
+			"process_id":    processID,
+			"stdout":        attach.stdout.String(),
+			"stderr":        attach.stderr.String(),
+			"stdout_cursor": tracker.StdoutCursor,
+			"stderr_cursor": tracker.StderrCursor,
+			"status":        tracker.Status,
+			"reason":        reason,
+			"detached":      reason != "window" && reason != "max_bytes", // Otherwise call again to keep streaming
+			"chunks":        attach.chunks,
+			"elapsed_ms":    time.Since(start).Milliseconds(),
+		}
+		if tracker.ExitCode != nil {
+			response["exit_code"] = *tracker.ExitCode
+		}
+		tracker.Mutex.RUnlock()
+		if match != nil {
+			response["match"] = match
+		}
+
+		resultBytes, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(resultBytes))
+	}
+
+	for {
+		// Read the status first: a finished process has flushed all of its output
+		tracker.Mutex.RLock()
+		finished := isTerminalStatus(tracker.Status)
+		tracker.Mutex.RUnlock()
+
+		attach.read()
+		if detachPattern != nil {
+			for _, scanner := range scanners {
+				if match := scanner.scan(detachPattern, finished); match != nil {
+					return result("pattern", match), nil
+				}
+			}
+		}
+		switch {
+		case finished:
+			return result("exited", nil), nil
+		case attach.size() >= maxBytes:
+			return result("max_bytes", nil), nil
+		case idleMs > 0 && time.Since(attach.lastData) >= time.Duration(idleMs)*time.Millisecond:
+			return result("idle", nil), nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-window.C:
+			attach.read()
+			return result("window", nil), nil
+		case <-ctx.Done():
+			return mcp.NewToolResultError("request canceled"), nil
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	DefaultBenchmarkRuns    = 10
+	MaxBenchmarkRuns        = 200
+	MaxBenchmarkConcurrency = 32
+	BenchmarkExitTimeout    = 30 * time.Second // A run that has not exited by then is killed and counted as failed
+	maxBenchmarkErrors      = 5                // Failures listed in the result
+)
+
+// LatencyStats summarizes benchmark latencies in milliseconds
+type LatencyStats struct {
+	MinMs float64 `json:"min_ms"`
+	AvgMs float64 `json:"avg_ms"`
+	P95Ms float64 `json:"p95_ms"`
+	MaxMs float64 `json:"max_ms"`
+}
+
+// benchmarkRun is the outcome of one benchmark spawn
+type benchmarkRun struct {
+	spawn time.Duration // Until spawn_process returned with the process running
+	exit  time.Duration // Until the process exited
+	err   string
+}
+
+// summarizeLatencies returns min/avg/p95/max of the samples (nearest-rank p95), or nil without samples
+func summarizeLatencies(samples []time.Duration) *LatencyStats {
+	if len(samples) == 0 {
+		return nil
+	}
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for _, sample := range sorted {
+		total += sample
+	}
+	ms := func(d time.Duration) float64 {
+		return math.Round(float64(d)/float64(time.Microsecond)) / 1000
+	}
+	p95 := int(math.Ceil(0.95*float64(len(sorted)))) - 1
+	return &LatencyStats{
+		MinMs: ms(sorted[0]),
+		AvgMs: ms(total / time.Duration(len(sorted))),
+		P95Ms: ms(sorted[p95]),
+		MaxMs: ms(sorted[len(sorted)-1]),
+	}
+}
+
+// runBenchmarkSpawn spawns command once through spawn_process, waits for it to exit and removes it
+func runBenchmarkSpawn(ctx context.Context, command string, args []string, workingDir string) benchmarkRun {
+	request := mcp.CallToolRequest{}
+	arguments := map[string]any{"command": command, "name": "benchmark_spawn"}
+	if len(args) > 0 {
+		argList := make([]any, len(args))
+		for i, arg := range args {
+			argList[i] = arg
+		}
+		arguments["args"] = argList
+	}
+	if workingDir != "" {
+		arguments["working_dir"] = workingDir
+	}
+	request.Params.Arguments = arguments
+
+	start := time.Now()
+	result, _ := handleSpawnProcess(ctx, request)
+	run := benchmarkRun{spawn: time.Since(start)}
+	text := result.Content[0].(mcp.TextContent).Text
+	if result.IsError {
+		run.err = text
+		return run
+	}
+
+	var spawned struct {
+		ProcessID string `json:"process_id"`
+	}
+	json.Unmarshal([]byte(text), &spawned)
+	tracker, exists := registry.getProcess(spawned.ProcessID)
+	if !exists {
+		run.err = "process disappeared before it could be timed"
+		return run
+	}
+	defer registry.removeProcess(tracker.ID)
+
+	if !waitForProcessExit(ctx, tracker, BenchmarkExitTimeout) {
+		tracker.Mutex.RLock()
+		pid := tracker.PID
+		tracker.Mutex.RUnlock()
+		if pid > 0 {
+			forceKillProcessGroup(pid)
+		}
+		waitForProcessExit(context.Background(), tracker, time.Second)
+		run.err = fmt.Sprintf("process did not exit within %s", BenchmarkExitTimeout)
+		return run
+	}
+
+	tracker.Mutex.RLock()
+	run.exit = tracker.EndTime.Sub(start)
+	if tracker.ExitCode != nil && *tracker.ExitCode != 0 {
+		run.err = fmt.Sprintf("exit code %d", *tracker.ExitCode)
+	}
+	tracker.Mutex.RUnlock()
+	return run
+}
+
+// handleBenchmarkSpawn spawns a trivial command repeatedly and reports how long sidekick takes
+// to get it running and to see it exit
+func handleBenchmarkSpawn(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	command := getStringArg(request, "command", "")
+	args := getStringArrayArg(request, "args")
+	if command == "" {
+		command, args = "true", nil
+		if runtime.GOOS == "windows" {
+			command, args = "cmd", []string{"/c", "exit", "0"}
+		}
+	}
+	workingDir := getStringArg(request, "working_dir", "")
+
+	runs := getIntArg(request, "runs", DefaultBenchmarkRuns)
+	if runs < 1 || runs > MaxBenchmarkRuns {
+		return mcp.NewToolResultError(fmt.Sprintf("runs must be between 1 and %d", MaxBenchmarkRuns)), nil
+	}
+	concurrency := getIntArg(request, "concurrency", 1)
+	if concurrency < 1 || concurrency > MaxBenchmarkConcurrency {
+		return mcp.NewToolResultError(fmt.Sprintf("concurrency must be between 1 and %d", MaxBenchmarkConcurrency)), nil
+	}
+	concurrency = min(concurrency, runs)
+
+	started := time.Now()
+	results := make([]benchmarkRun, runs)
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				results[i] = runBenchmarkSpawn(ctx, command, args, workingDir)
+			}
+		}()
+	}
+	for i := 0; i < runs; i++ {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	var spawnTimes, exitTimes []time.Duration
+	var errs []string
+	failed := 0
+	for _, run := range results {
+		if run.err != "" {
+			failed++
+			if len(errs) < maxBenchmarkErrors {
+				errs = append(errs, run.err)
+			}
+			continue
+		}
+		spawnTimes = append(spawnTimes, run.spawn)
+		exitTimes = append(exitTimes, run.exit)
+	}
+
+	LogInfo("Process", "Spawn benchmark finished", fmt.Sprintf("Command: %s, runs: %d, concurrency: %d, failed: %d", command, runs, concurrency, failed))
+
+	result := map[string]any{
+		"command":          command,
+		"args":             args,
+		"runs":             runs,
+		"concurrency":      concurrency,
+		"succeeded":        runs - failed,
+		"failed":           failed,
+		"total_ms":         time.Since(started).Milliseconds(),
+		"spawn_to_running": summarizeLatencies(spawnTimes),
+		"spawn_to_exit":    summarizeLatencies(exitTimes),
+	}
+	if len(errs) > 0 {
+		result["errors"] = errs
+	}
+
+	resultBytes, _ := json.Marshal(result)
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// BroadcastResult is the outcome of broadcast_input for one process
+type BroadcastResult struct {
+	ProcessID string `json:"process_id"`
+	Sent      bool   `json:"sent"`
+	BytesSent int    `json:"bytes_sent,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// broadcastTargets returns the processes named in ids plus the running processes matching
+// labelSelector, without duplicates. Unknown ids are reported in missing.
+func broadcastTargets(ids []string, labelSelector map[string]string) (targets []*ProcessTracker, missing []string) {
+	seen := map[string]bool{}
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if tracker, exists := registry.getProcess(id); exists {
+			targets = append(targets, tracker)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+
+	if len(labelSelector) > 0 {
+		matched := runningTrackers()
+		sort.Slice(matched, func(i, j int) bool {
+			return matched[i].StartTime.Before(matched[j].StartTime)
+		})
+		for _, tracker := range matched {
+			tracker.Mutex.RLock()
+			matches := matchesLabels(tracker, labelSelector)
+			tracker.Mutex.RUnlock()
+			if matches && !seen[tracker.ID] {
+				seen[tracker.ID] = true
+				targets = append(targets, tracker)
+			}
+		}
+	}
+	return targets, missing
+}
+
+// handleBroadcastInput writes the same input to several processes' stdin at once, chosen by
+// ID and/or label selector, and reports the outcome per process
+func handleBroadcastInput(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	input, err := request.RequireString("input")
+	if err != nil {
+		return mcp.NewToolResultError("Missing or invalid 'input' argument"), nil
+	}
+	autoNewline := getBoolArg(request, "auto_newline", true)
+	if autoNewline {
+		input += "\n"
+	}
+
+	ids := getStringArrayArg(request, "process_ids")
+	labelSelector := getStringMapArg(request, "labels")
+	if len(ids) == 0 && len(labelSelector) == 0 {
+		return mcp.NewToolResultError("Provide process_ids and/or labels to choose the processes"), nil
+	}
+
+	targets, missing := broadcastTargets(ids, labelSelector)
+	results := make([]BroadcastResult, len(targets))
+
+	// Each process is written to on its own, so one with a full stdin pipe cannot hold up the rest
+	var wg sync.WaitGroup
+	for i, tracker := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = BroadcastResult{ProcessID: tracker.ID}
+			if err := writeProcessInput(tracker, input); err != nil {
+				results[i].Error = err.Error()
+				return
+			}
+			results[i].Sent, results[i].BytesSent = true, len(input)
+		}()
+	}
+	wg.Wait()
+
+	for _, id := range missing {
+		results = append(results, BroadcastResult{ProcessID: id, Error: "Process " + id + " not found"})
+	}
+
+	sent := 0
+	for _, result := range results {
+		if result.Sent {
+			sent++
+		}
+	}
+
+	result := map[string]any{
+		"sent":         sent,
+		"failed":       len(results) - sent,
+		"auto_newline": autoNewline,
+		"results":      results,
+	}
+	resultBytes, _ := json.Marshal(result)
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
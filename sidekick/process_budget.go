@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	BufferBudgetInterval = time.Second // How often the --max-total-buffer-bytes budget is enforced
+	MinBudgetBufferSize  = 64 * 1024   // The budget never shrinks a buffer below this
+)
+
+// BufferBudgetStats is a snapshot of the --max-total-buffer-bytes budget, for process_stats
+type BufferBudgetStats struct {
+	Limit      int64 `json:"limit"`       // --max-total-buffer-bytes, 0 = unlimited
+	Used       int64 `json:"used"`        // Output currently held in memory across all processes
+	Shrunk     int64 `json:"shrunk"`      // Buffers shrunk to stay within the budget since start
+	FreedBytes int64 `json:"freed_bytes"` // Output evicted by those shrinks
+	Refused    int64 `json:"refused"`     // Spawns and resizes refused because their buffers exceed the budget
+}
+
+// BufferBudget caps the output held in memory by all processes' RingBuffers. Over the limit,
+// the buffers of finished processes are shrunk first, then those of the least recently
+// accessed ones; a process whose buffers would not fit next to the output already held is refused.
+type BufferBudget struct {
+	mu      sync.Mutex
+	limit   int64
+	shrunk  int64
+	freed   int64
+	refused int64
+}
+
+// bufferBudget enforces --max-total-buffer-bytes
+var bufferBudget = &BufferBudget{}
+
+// SetLimit sets the budget in bytes (0 = unlimited)
+func (b *BufferBudget) SetLimit(limit int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.limit = limit
+}
+
+// Limit returns the budget in bytes, 0 when unlimited
+func (b *BufferBudget) Limit() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.limit
+}
+
+// bufferCapacity returns the most output a process buffering bufferSize bytes per stream can hold
+func bufferCapacity(bufferSize int64, combineOutput bool) int64 {
+	if combineOutput {
+		return bufferSize
+	}
+	return bufferSize * 2 // stdout and stderr are buffered separately
+}
+
+// admit checks that a process buffering up to bufferSize bytes per stream fits in the budget
+// next to the used bytes already held by other processes
+func (b *BufferBudget) admit(used, bufferSize int64, combineOutput bool) error {
+	capacity := bufferCapacity(bufferSize, combineOutput)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.limit > 0 && used+capacity > b.limit {
+		b.refused++
+		return fmt.Errorf("buffer_size %s (%s across streams) with %s already held exceeds --max-total-buffer-bytes %s; lower buffer_size or set combine_output",
+			formatBytes(bufferSize), formatBytes(capacity), formatBytes(used), formatBytes(b.limit))
+	}
+	return nil
+}
+
+// trackerBufferedBytes returns the output a process holds in memory. The caller holds tracker.Mutex.
+func trackerBufferedBytes(tracker *ProcessTracker) int64 {
+	var buffered int64
+	for _, buffer := range []*RingBuffer{tracker.StdoutBuffer, tracker.StderrBuffer} {
+		if buffer != nil {
+			buffered += int64(buffer.Len())
+		}
+	}
+	return buffered
+}
+
+// totalBufferedBytes sums the output held in memory across the given processes
+func totalBufferedBytes(trackers []*ProcessTracker) int64 {
+	var total int64
+	for _, tracker := range trackers {
+		tracker.Mutex.RLock()
+		total += trackerBufferedBytes(tracker)
+		tracker.Mutex.RUnlock()
+	}
+	return total
+}
+
+// enforce shrinks buffers until the output held by trackers fits the budget: finished processes
+// first, then by least recent access. It returns the bytes still held.
+func (b *BufferBudget) enforce(trackers []*ProcessTracker) int64 {
+	limit := b.Limit()
+	total := totalBufferedBytes(trackers)
+	if limit <= 0 || total <= limit {
+		return total
+	}
+
+	type candidate struct {
+		tracker      *ProcessTracker
+		finished     bool
+		lastAccessed time.Time
+	}
+	candidates := make([]candidate, 0, len(trackers))
+	for _, tracker := range trackers {
+		tracker.Mutex.RLock()
+		candidates = append(candidates, candidate{tracker, isTerminalStatus(tracker.Status), tracker.LastAccessed})
+		tracker.Mutex.RUnlock()
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].finished != candidates[j].finished {
+			return candidates[i].finished
+		}
+		return candidates[i].lastAccessed.Before(candidates[j].lastAccessed)
+	})
+
+	for _, c := range candidates {
+		if total <= limit {
+			break
+		}
+		tracker := c.tracker
+		tracker.Mutex.Lock()
+		before := trackerBufferedBytes(tracker)
+		streams := int64(1)
+		if tracker.StderrBuffer != nil {
+			streams = 2
+		}
+		// Keep what fits once the excess is gone, spread over the streams
+		newSize := max((before-(total-limit))/streams, MinBudgetBufferSize)
+		if before == 0 || newSize >= tracker.BufferSize {
+			tracker.Mutex.Unlock()
+			continue
+		}
+		previousSize := tracker.BufferSize
+		tracker.BufferSize = newSize
+		tracker.StdoutBuffer.Resize(newSize)
+		if tracker.StderrBuffer != nil {
+			tracker.StderrBuffer.Resize(newSize)
+		}
+		freed := before - trackerBufferedBytes(tracker)
+		tracker.Mutex.Unlock()
+
+		total -= freed
+		b.mu.Lock()
+		b.shrunk++
+		b.freed += freed
+		b.mu.Unlock()
+		LogWarn("Process", fmt.Sprintf("Buffer shrunk for --max-total-buffer-bytes: %s → %s", formatBytes(previousSize), formatBytes(newSize)),
+			fmt.Sprintf("ID: %s, freed: %s", tracker.ID, formatBytes(freed)))
+	}
+	return total
+}
+
+// run enforces the budget every BufferBudgetInterval until ctx is done
+func (b *BufferBudget) run(ctx context.Context) {
+	ticker := time.NewTicker(BufferBudgetInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			b.enforce(registry.getAllProcesses())
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Stats returns the budget counters with used as the current usage
+func (b *BufferBudget) Stats(used int64) BufferBudgetStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return BufferBudgetStats{
+		Limit:      b.limit,
+		Used:       used,
+		Shrunk:     b.shrunk,
+		FreedBytes: b.freed,
+		Refused:    b.refused,
+	}
+}
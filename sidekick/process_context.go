@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	DefaultContextLines = 5   // Lines get_output_context returns on each side when before/after are not given
+	MaxContextLines     = 500 // Most lines get_output_context returns on each side
+)
+
+// OutputContextLine is one line of a get_output_context window
+type OutputContextLine struct {
+	Number int64  `json:"number"` // 1-based line number in the stream, counting lines trimmed from the buffer
+	Offset int64  `json:"offset"` // Absolute byte offset where the line starts in the stream
+	Text   string `json:"text"`   // Without the line terminator
+	Target bool   `json:"target,omitempty"`
+}
+
+// bufferedLines splits the buffer into lines numbered from the start of the stream. When
+// partial is true, the first line lost its beginning to trimming.
+func (rb *RingBuffer) bufferedLines() (lines []OutputContextLine, partial bool) {
+	rb.mutex.RLock()
+	defer rb.mutex.RUnlock()
+
+	number := rb.discardedLines + 1
+	offset := rb.totalBytes - int64(len(rb.data))
+	data := rb.data
+	for len(data) > 0 {
+		end := bytes.IndexByte(data, '\n') + 1
+		if end == 0 {
+			end = len(data) // Unfinished last line
+		}
+		lines = append(lines, OutputContextLine{
+			Number: number,
+			Offset: offset,
+			Text:   strings.TrimRight(string(data[:end]), "\r\n"),
+		})
+		number++
+		offset += int64(end)
+		data = data[end:]
+	}
+	return lines, rb.trimmedMidLine && len(rb.data) > 0
+}
+
+// handleGetOutputContext returns the lines around a position in a process's output, given
+// as a line number or an absolute byte offset (such as wait_for_output_pattern's offset).
+// A position whose line was trimmed from the buffer yields whatever of its window is left.
+func handleGetOutputContext(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	processID, err := request.RequireString("process_id")
+	if err != nil {
+		return mcp.NewToolResultError("Missing or invalid 'process_id' argument"), nil
+	}
+
+	stream := getStringArg(request, "stream", "stdout")
+	if stream != "stdout" && stream != "stderr" {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid stream '%s' (use 'stdout' or 'stderr')", stream)), nil
+	}
+	lineNumber := getInt64Arg(request, "line_number", 0)
+	byteOffset := getInt64Arg(request, "byte_offset", -1)
+	if lineNumber < 0 {
+		return mcp.NewToolResultError("line_number must be 1 or more"), nil
+	}
+	if (lineNumber > 0) == (byteOffset >= 0) {
+		return mcp.NewToolResultError("Provide exactly one of line_number or byte_offset"), nil
+	}
+	before := getIntArg(request, "before", DefaultContextLines)
+	after := getIntArg(request, "after", DefaultContextLines)
+	if before < 0 || after < 0 || before > MaxContextLines || after > MaxContextLines {
+		return mcp.NewToolResultError(fmt.Sprintf("before and after must be between 0 and %d", MaxContextLines)), nil
+	}
+
+	tracker, exists := registry.getProcess(processID)
+	removed := false
+	if !exists {
+		if tracker, exists = removedProcesses.get(processID); !exists {
+			return mcp.NewToolResultError(fmt.Sprintf("Process %s not found", processID)), nil
+		}
+		removed = true
+	}
+
+	tracker.Mutex.RLock()
+	buffer := tracker.StdoutBuffer
+	if stream == "stderr" {
+		buffer = tracker.StderrBuffer
+	}
+	tracker.Mutex.RUnlock()
+	if buffer == nil {
+		return mcp.NewToolResultError("Process has combined output - stderr not available separately. Use stream 'stdout'."), nil
+	}
+
+	lines, partial := buffer.bufferedLines()
+	totalBytes := buffer.TotalBytes()
+	discardedBytes := buffer.DiscardedBytes()
+
+	// The first buffered line number, and the number of the last line written so far
+	firstLine, lastLine := int64(1), int64(0)
+	if len(lines) > 0 {
+		firstLine, lastLine = lines[0].Number, lines[len(lines)-1].Number
+	}
+
+	// Resolve the position to a line number; a byte offset in the trimmed region has none
+	targetTrimmed := false
+	var target int64
+	switch {
+	case lineNumber > 0:
+		if lineNumber > lastLine {
+			return mcp.NewToolResultError(fmt.Sprintf("line_number %d is past the end of %s (%d lines written)", lineNumber, stream, lastLine)), nil
+		}
+		target = lineNumber
+		targetTrimmed = lineNumber < firstLine || (lineNumber == firstLine && partial)
+	case byteOffset >= totalBytes:
+		return mcp.NewToolResultError(fmt.Sprintf("byte_offset %d is past the end of %s (%d bytes written)", byteOffset, stream, totalBytes)), nil
+	case byteOffset < discardedBytes:
+		// Show the first lines still buffered, as if the target were the line before them
+		target, targetTrimmed = firstLine-1, true
+	default:
+		for _, line := range lines {
+			if line.Offset > byteOffset {
+				break
+			}
+			target = line.Number
+		}
+		targetTrimmed = target == firstLine && partial
+	}
+
+	window := []OutputContextLine{}
+	for _, line := range lines {
+		if line.Number >= target-int64(before) && line.Number <= target+int64(after) {
+			line.Target = line.Number == target
+			window = append(window, line)
+		}
+	}
+
+	result := map[string]any{
+		"process_id":     processID,
+		"stream":         stream,
+		"lines":          window,
+		"target_trimmed": targetTrimmed,
+		"first_line":     firstLine,
+		"last_line":      lastLine,
+	}
+	if target >= firstLine {
+		result["target_line"] = target
+	}
+	if discardedBytes > 0 {
+		result["discarded_bytes"] = discardedBytes
+	}
+	if removed {
+		result["removed"] = true
+	}
+	resultBytes, _ := json.Marshal(result)
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"os/user"
+	"strconv"
+)
+
+// ProcessCredential is the user a spawned process runs as (run_as_user / run_as_uid)
+type ProcessCredential struct {
+	Username string
+	UID      uint32
+	GID      uint32
+	Groups   []uint32
+}
+
+// resolveProcessCredential looks up the target user by name and/or uid.
+// runAsUID < 0 means unset. Returns nil when neither is given.
+func resolveProcessCredential(runAsUser string, runAsUID int) (*ProcessCredential, error) {
+	if runAsUser == "" && runAsUID < 0 {
+		return nil, nil
+	}
+
+	var u *user.User
+	var err error
+	if runAsUser != "" {
+		u, err = user.Lookup(runAsUser)
+		if err != nil {
+			return nil, fmt.Errorf("run_as_user '%s' not found: %v", runAsUser, err)
+		}
+	} else {
+		u, err = user.LookupId(strconv.Itoa(runAsUID))
+		if err != nil {
+			return nil, fmt.Errorf("run_as_uid %d not found: %v", runAsUID, err)
+		}
+	}
+
+	uid, err := strconv.ParseUint(u.Uid, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("user '%s' has a non-numeric uid '%s'", u.Username, u.Uid)
+	}
+	if runAsUID >= 0 && uint64(runAsUID) != uid {
+		return nil, fmt.Errorf("run_as_user '%s' has uid %d, which does not match run_as_uid %d", runAsUser, uid, runAsUID)
+	}
+
+	gid, err := strconv.ParseUint(u.Gid, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("user '%s' has a non-numeric gid '%s'", u.Username, u.Gid)
+	}
+
+	credential := &ProcessCredential{
+		Username: u.Username,
+		UID:      uint32(uid),
+		GID:      uint32(gid),
+	}
+
+	// Supplementary groups are best effort - a lookup failure just leaves them empty
+	if groupIDs, err := u.GroupIds(); err == nil {
+		for _, groupID := range groupIDs {
+			if g, err := strconv.ParseUint(groupID, 10, 32); err == nil {
+				credential.Groups = append(credential.Groups, uint32(g))
+			}
+		}
+	}
+
+	if err := checkCredentialPrivilege(credential); err != nil {
+		return nil, err
+	}
+
+	return credential, nil
+}
+
+// checkCredentialPrivilegeAs verifies a process running as euid may switch to the given user:
+// only root can run processes as another user
+func checkCredentialPrivilegeAs(credential *ProcessCredential, euid int) error {
+	if euid != 0 && uint32(euid) != credential.UID {
+		return fmt.Errorf("cannot run as user '%s' (uid %d): sidekick is running as uid %d and lacks privilege to switch users", credential.Username, credential.UID, euid)
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -84,7 +85,7 @@ func (p *ProcessDetailPageView) setupInputField() {
 // setupStatusBar configures the status bar
 func (p *ProcessDetailPageView) setupStatusBar() {
 	p.statusBar.SetBorder(true).SetTitle(" Controls ").SetTitleAlign(tview.AlignLeft)
-	p.statusBar.SetText("[yellow]Tab[white]: Switch Focus | [yellow]Enter[white]: Send Input | [yellow]S[white]: Toggle Auto-scroll | [yellow]Esc[white]: Back | [yellow]Q[white]: Quit\n[grey]Pages: [yellow]1[white]: Processes | [yellow]2[white]: Notifications | [yellow]3[white]: Agents Q&A | [yellow]4[white]: Logs | [yellow]5[white]: Features[grey]")
+	p.statusBar.SetText(statusBarText(ProcessDetailPage))
 	p.statusBar.SetTextAlign(tview.AlignCenter)
 	p.statusBar.SetDynamicColors(true)
 }
@@ -366,6 +367,29 @@ func (p *ProcessDetailPageView) updateInfo() {
 	if tracker.ExitCode != nil {
 		info += fmt.Sprintf("\n[yellow]Exit Code:[white] %d", *tracker.ExitCode)
 	}
+	if tracker.ExitReason != "" {
+		reason := tracker.ExitReason
+		if tracker.Signal != "" {
+			reason += fmt.Sprintf(" (%s)", tracker.Signal)
+		}
+		info += fmt.Sprintf("\n[yellow]Exit Reason:[white] %s", reason)
+	}
+	if len(tracker.Labels) > 0 {
+		info += fmt.Sprintf("\n[yellow]Labels:[white] %s", tview.Escape(formatLabels(tracker.Labels)))
+	}
+	if tracker.Progress != nil {
+		if progress := tracker.Progress.snapshot(); progress != nil {
+			info += fmt.Sprintf("\n[yellow]Progress:[white] %s", tview.Escape(formatProgress(progress)))
+		} else {
+			info += "\n[yellow]Progress:[white] waiting for the first progress line"
+		}
+	}
+	if discarded := tracker.discardedOutputBytes(); discarded > 0 {
+		info += fmt.Sprintf("\n[red]⚠ Output Truncated:[white] oldest %s discarded (buffer full)", formatBytes(discarded))
+	}
+	if tracker.LastError != "" {
+		info += fmt.Sprintf("\n[red]Error:[white] %s", tview.Escape(tracker.LastError))
+	}
 
 	p.infoPanel.SetText(info)
 }
@@ -481,6 +505,16 @@ func (p *ProcessDetailPageView) updateLogsIncremental() {
 	}
 }
 
+// formatLabels renders labels as sorted key=value pairs
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ", ")
+}
+
 // getStringOrDash returns the string or "-" if empty
 func getStringOrDash(s string) string {
 	if s == "" {
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	DefaultDiffContext  = 3    // Unchanged lines shown around each change
+	MaxDiffContext      = 100  // Upper bound for the context parameter
+	DefaultDiffMaxLines = 1000 // Diff lines returned per stream unless max_lines says otherwise
+	MaxDiffEdits        = 1000 // Beyond this many changed lines the differing middle is reported as one replaced block
+)
+
+// diffOp is one line of an edit script: ' ' keeps line a, '-' removes line a, '+' inserts line b
+type diffOp struct {
+	kind byte
+	a, b int
+}
+
+// StreamDiff is the diff of one output stream between two processes
+type StreamDiff struct {
+	Diff         string `json:"diff"`
+	LinesAdded   int    `json:"lines_added"`
+	LinesRemoved int    `json:"lines_removed"`
+	Identical    bool   `json:"identical"`
+	Truncated    bool   `json:"truncated,omitempty"`   // max_lines cut the diff short
+	Approximate  bool   `json:"approximate,omitempty"` // Too many changes for a minimal diff (see MaxDiffEdits)
+}
+
+// diffLines returns an edit script turning a into b. Common leading and trailing lines are
+// matched first; the rest uses Myers' algorithm, and is reported as a removed block followed by
+// an added block (approximate) when it needs more than maxEdits changes.
+func diffLines(a, b []string, maxEdits int) ([]diffOp, bool) {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for i := 0; i < prefix; i++ {
+		ops = append(ops, diffOp{' ', i, i})
+	}
+
+	midA, midB := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	middle := myersDiff(midA, midB, maxEdits)
+	approximate := middle == nil && len(midA)+len(midB) > 0
+	if approximate {
+		for i := range midA {
+			middle = append(middle, diffOp{'-', i, 0})
+		}
+		for j := range midB {
+			middle = append(middle, diffOp{'+', len(midA), j})
+		}
+	}
+	for _, op := range middle {
+		ops = append(ops, diffOp{op.kind, op.a + prefix, op.b + prefix})
+	}
+
+	for i := 0; i < suffix; i++ {
+		ops = append(ops, diffOp{' ', len(a) - suffix + i, len(b) - suffix + i})
+	}
+	return ops, approximate
+}
+
+// myersDiff returns a minimal edit script turning a into b, or nil when that takes more than maxEdits changes
+func myersDiff(a, b []string, maxEdits int) []diffOp {
+	n, m := len(a), len(b)
+	limit := min(n+m, maxEdits)
+	offset := limit + 1
+	v := make([]int, 2*limit+3)
+	var trace [][]int // trace[d] holds v[-d..d] as it was before round d
+
+	for d := 0; d <= limit; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1] // Move down: insert from b
+			} else {
+				x = v[offset+k-1] + 1 // Move right: remove from a
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrackMyers(trace, n, m)
+			}
+		}
+	}
+	return nil
+}
+
+// backtrackMyers walks the saved rounds back from (n, m) to build the edit script
+func backtrackMyers(trace [][]int, n, m int) []diffOp {
+	var ops []diffOp
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		at := func(k int) int { return trace[d][k+d] }
+		k := x - y
+		prevK := k - 1
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			ops = append(ops, diffOp{' ', x, y})
+		}
+		if x == prevX {
+			y--
+			ops = append(ops, diffOp{'+', x, y})
+		} else {
+			x--
+			ops = append(ops, diffOp{'-', x, y})
+		}
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		ops = append(ops, diffOp{' ', x, y})
+	}
+
+	for i, j := 0, len(ops)-1; i < j; i, j = i+1, j-1 {
+		ops[i], ops[j] = ops[j], ops[i]
+	}
+	return ops
+}
+
+// unifiedDiff renders the diff between a and b in unified format with context lines around each
+// change, stopping after maxLines lines (0 = no limit)
+func unifiedDiff(labelA, labelB string, a, b []string, context, maxLines int) StreamDiff {
+	ops, approximate := diffLines(a, b, MaxDiffEdits)
+	result := StreamDiff{Approximate: approximate}
+
+	var changes []int
+	for i, op := range ops {
+		switch op.kind {
+		case '-':
+			result.LinesRemoved++
+			changes = append(changes, i)
+		case '+':
+			result.LinesAdded++
+			changes = append(changes, i)
+		}
+	}
+	if len(changes) == 0 {
+		result.Identical = true
+		return result
+	}
+
+	var out strings.Builder
+	lines := 0
+	emit := func(line string) bool {
+		if maxLines > 0 && lines >= maxLines {
+			result.Truncated = true
+			return false
+		}
+		out.WriteString(line)
+		out.WriteByte('\n')
+		lines++
+		return true
+	}
+
+	emit("--- " + labelA)
+	emit("+++ " + labelB)
+	for first := 0; first < len(changes) && !result.Truncated; {
+		// Changes closer than two contexts apart share a hunk
+		last := first
+		for last+1 < len(changes) && changes[last+1]-changes[last] <= 2*context+1 {
+			last++
+		}
+		start := max(changes[first]-context, 0)
+		end := min(changes[last]+context+1, len(ops))
+
+		countA, countB := 0, 0
+		for _, op := range ops[start:end] {
+			if op.kind != '+' {
+				countA++
+			}
+			if op.kind != '-' {
+				countB++
+			}
+		}
+		if !emit(fmt.Sprintf("@@ -%s +%s @@", hunkRange(ops[start].a, countA), hunkRange(ops[start].b, countB))) {
+			break
+		}
+		for _, op := range ops[start:end] {
+			text := ""
+			if op.kind == '+' {
+				text = b[op.b]
+			} else {
+				text = a[op.a]
+			}
+			if !emit(string(op.kind) + text) {
+				break
+			}
+		}
+		first = last + 1
+	}
+
+	result.Diff = out.String()
+	return result
+}
+
+// hunkRange formats a unified diff range from a 0-based start; an empty range names the line before it
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// handleDiffProcessOutput compares the buffered output of two processes and returns a unified diff per stream
+func handleDiffProcessOutput(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	idA, err := request.RequireString("process_id_a")
+	if err != nil {
+		return mcp.NewToolResultError("Missing or invalid 'process_id_a' argument"), nil
+	}
+	idB, err := request.RequireString("process_id_b")
+	if err != nil {
+		return mcp.NewToolResultError("Missing or invalid 'process_id_b' argument"), nil
+	}
+
+	streams := getStringArg(request, "streams", "stdout")
+	if streams != "stdout" && streams != "stderr" && streams != "both" {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid streams value '%s' (use 'stdout', 'stderr' or 'both')", streams)), nil
+	}
+	contextLines := getIntArg(request, "context", DefaultDiffContext)
+	if contextLines < 0 || contextLines > MaxDiffContext {
+		return mcp.NewToolResultError(fmt.Sprintf("context must be between 0 and %d", MaxDiffContext)), nil
+	}
+	maxLines := getIntArg(request, "max_lines", DefaultDiffMaxLines)
+	if maxLines < 0 {
+		maxLines = 0 // No limit
+	}
+
+	trackers := make([]*ProcessTracker, 2)
+	for i, id := range []string{idA, idB} {
+		tracker, exists := registry.getProcess(id)
+		if !exists {
+			// Recently removed processes are still readable, as with get_full_process_output
+			if tracker, exists = removedProcesses.get(id); !exists {
+				return mcp.NewToolResultError(fmt.Sprintf("Process %s not found", id)), nil
+			}
+		}
+		trackers[i] = tracker
+	}
+
+	result := map[string]any{
+		"process_id_a": idA,
+		"process_id_b": idB,
+	}
+	identical := true
+	combined := false
+	for i, tracker := range trackers {
+		tracker.Mutex.RLock()
+		result[fmt.Sprintf("status_%c", 'a'+i)] = tracker.Status
+		combined = combined || tracker.CombineOutput || tracker.StderrBuffer == nil
+		tracker.Mutex.RUnlock()
+	}
+
+	if streams == "stdout" || streams == "both" {
+		diff := unifiedDiff(idA+" stdout", idB+" stdout",
+			splitOutputLines(trackers[0].StdoutBuffer.GetContent()),
+			splitOutputLines(trackers[1].StdoutBuffer.GetContent()), contextLines, maxLines)
+		identical = identical && diff.Identical
+		result["stdout"] = diff
+	}
+	if streams == "stderr" || streams == "both" {
+		if combined {
+			if streams == "stderr" {
+				return mcp.NewToolResultError("A process has combined output - stderr not available separately. Use 'stdout' or 'both' streams."), nil
+			}
+			result["stderr_skipped"] = "a process has combined output; its stderr is part of stdout"
+		} else {
+			diff := unifiedDiff(idA+" stderr", idB+" stderr",
+				splitOutputLines(trackers[0].StderrBuffer.GetContent()),
+				splitOutputLines(trackers[1].StderrBuffer.GetContent()), contextLines, maxLines)
+			identical = identical && diff.Identical
+			result["stderr"] = diff
+		}
+	}
+	result["identical"] = identical
+
+	resultBytes, _ := json.Marshal(result)
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
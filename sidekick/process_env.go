@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// MaxEnvMaskPatterns caps the --env-mask patterns
+const MaxEnvMaskPatterns = 32
+
+// secretEnvKeyPattern matches the names of environment variables that usually hold secrets
+var secretEnvKeyPattern = regexp.MustCompile(`(?i)(secret|token|passw(or)?d|passwd|pwd|api_?key|private_?key|access_?key|credential|auth|cookie|session|signature|dsn)`)
+
+// envMaskPatterns are the --env-mask patterns: more variable names whose values are masked
+var envMaskPatterns []*regexp.Regexp
+
+// configureEnvMask compiles the --env-mask patterns
+func configureEnvMask(patterns []string) error {
+	if len(patterns) > MaxEnvMaskPatterns {
+		return fmt.Errorf("--env-mask cannot be given more than %d times", MaxEnvMaskPatterns)
+	}
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		if pattern == "" {
+			return fmt.Errorf("--env-mask cannot be empty")
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid --env-mask pattern %q: %v", pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	envMaskPatterns = compiled
+	return nil
+}
+
+// isSecretEnvKey reports whether the value of the variable named key is masked
+func isSecretEnvKey(key string) bool {
+	if secretEnvKeyPattern.MatchString(key) {
+		return true
+	}
+	for _, re := range envMaskPatterns {
+		if re.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// envValueRedactor catches secrets inside values of variables with harmless names, such as
+// credentials in a DATABASE_URL
+var envValueRedactor = &outputRedactor{rules: builtinRedactRules}
+
+// maskedEnv returns env with the values of secret variables replaced by RedactionMarker and
+// known credential formats redacted from the rest, plus the sorted names of the masked variables
+func maskedEnv(env map[string]string) (map[string]string, []string) {
+	masked := make(map[string]string, len(env))
+	maskedKeys := []string{}
+	for key, value := range env {
+		if isSecretEnvKey(key) {
+			masked[key] = RedactionMarker
+			maskedKeys = append(maskedKeys, key)
+			continue
+		}
+		masked[key] = envValueRedactor.redact(value)
+	}
+	sort.Strings(maskedKeys)
+	return masked, maskedKeys
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// MaxEnvFileSize caps how large an env_file may be
+const MaxEnvFileSize = 1024 * 1024
+
+// getSpawnEnvArgs returns the env argument merged over the variables of env_file, if given
+func getSpawnEnvArgs(request mcp.CallToolRequest, workingDir string) (map[string]string, error) {
+	envVars := getStringMapArg(request, "env")
+	envFile := getStringArg(request, "env_file", "")
+	if envFile == "" {
+		return envVars, nil
+	}
+
+	fileVars, err := loadEnvFile(envFile, workingDir)
+	if err != nil {
+		return nil, err
+	}
+	// Explicit env entries win over the file
+	for k, v := range envVars {
+		fileVars[k] = v
+	}
+	return fileVars, nil
+}
+
+// loadEnvFile reads a dotenv file for env_file. Relative paths resolve against workingDir.
+func loadEnvFile(path, workingDir string) (map[string]string, error) {
+	if !filepath.IsAbs(path) && workingDir != "" {
+		path = filepath.Join(workingDir, path)
+	}
+
+	// Files are confined like working directories when --allowed-workdir is set
+	if err := spawnPolicy.CheckPath(path); err != nil {
+		return nil, fmt.Errorf("env_file: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("env_file: %v", err)
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("env_file: %s is a directory", path)
+	}
+	if info.Size() > MaxEnvFileSize {
+		return nil, fmt.Errorf("env_file: %s is larger than %d bytes", path, MaxEnvFileSize)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("env_file: %v", err)
+	}
+
+	vars, err := parseEnvFile(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("env_file %s: %v", path, err)
+	}
+	return vars, nil
+}
+
+// parseEnvFile parses KEY=VALUE lines. Blank lines, # comments and an optional "export "
+// prefix are allowed. Values may be single-quoted (literal) or double-quoted (with \n, \t,
+// \" and \\ escapes, spanning lines); unquoted values end at " #".
+func parseEnvFile(content string) (map[string]string, error) {
+	vars := map[string]string{}
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 64*1024), MaxEnvFileSize)
+
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, found := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !found {
+			return nil, fmt.Errorf("line %d: expected KEY=VALUE", lineNum)
+		}
+		if !validEnvKey(key) {
+			return nil, fmt.Errorf("line %d: invalid variable name %q", lineNum, key)
+		}
+		value = strings.TrimSpace(value)
+
+		startLine := lineNum
+		switch {
+		case strings.HasPrefix(value, "'"):
+			end := strings.Index(value[1:], "'")
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated single-quoted value", startLine)
+			}
+			if rest := strings.TrimSpace(value[end+2:]); rest != "" && !strings.HasPrefix(rest, "#") {
+				return nil, fmt.Errorf("line %d: unexpected text after closing quote", startLine)
+			}
+			value = value[1 : end+1]
+
+		case strings.HasPrefix(value, `"`):
+			raw := value[1:]
+			var unquoted strings.Builder
+			for {
+				end, err := unquoteEnvValue(raw, &unquoted)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %v", lineNum, err)
+				}
+				if end >= 0 {
+					if rest := strings.TrimSpace(raw[end+1:]); rest != "" && !strings.HasPrefix(rest, "#") {
+						return nil, fmt.Errorf("line %d: unexpected text after closing quote", lineNum)
+					}
+					break
+				}
+				// The value continues on the next line
+				if !scanner.Scan() {
+					return nil, fmt.Errorf("line %d: unterminated double-quoted value", startLine)
+				}
+				lineNum++
+				unquoted.WriteByte('\n')
+				raw = scanner.Text()
+			}
+			value = unquoted.String()
+
+		default:
+			if i := strings.Index(value, " #"); i >= 0 {
+				value = strings.TrimSpace(value[:i])
+			}
+		}
+
+		vars[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("line %d: %v", lineNum+1, err)
+	}
+
+	return vars, nil
+}
+
+// unquoteEnvValue appends raw to out up to the closing double quote, resolving escapes.
+// Returns the index of the closing quote, or -1 if raw ends first.
+func unquoteEnvValue(raw string, out *strings.Builder) (int, error) {
+	for i := 0; i < len(raw); i++ {
+		switch c := raw[i]; c {
+		case '"':
+			return i, nil
+		case '\\':
+			if i+1 == len(raw) {
+				return -1, fmt.Errorf("trailing backslash in double-quoted value")
+			}
+			i++
+			switch raw[i] {
+			case 'n':
+				out.WriteByte('\n')
+			case 't':
+				out.WriteByte('\t')
+			case 'r':
+				out.WriteByte('\r')
+			default:
+				out.WriteByte(raw[i]) // \" \\ \$ and anything else: the character itself
+			}
+		default:
+			out.WriteByte(c)
+		}
+	}
+	return -1, nil
+}
+
+// validEnvKey reports whether key is a usable environment variable name
+func validEnvKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, c := range key {
+		if c == '_' || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (i > 0 && c >= '0' && c <= '9') {
+			continue
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ExitHookTimeout     = 60 * time.Second // on_exit_command is killed after this long
+	ExitHookOutputLimit = 8 * 1024         // Bytes of on_exit_command output kept
+)
+
+// ExitHookResult records how a process's on_exit_command went
+type ExitHookResult struct {
+	ExitCode  *int      `json:"exit_code,omitempty"`
+	Output    string    `json:"output,omitempty"` // Combined stdout/stderr, first ExitHookOutputLimit bytes
+	Truncated bool      `json:"truncated,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	StartTime time.Time `json:"start_time"`
+	Duration  string    `json:"duration"`
+}
+
+// cappedBuffer keeps the first limit bytes written to it and discards the rest
+type cappedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (c *cappedBuffer) Write(p []byte) (int, error) {
+	kept := p
+	if room := c.limit - c.buf.Len(); room < len(p) {
+		c.truncated = true
+		kept = p[:max(room, 0)]
+	}
+	c.buf.Write(kept)
+	return len(p), nil // Report the full length so the child never sees a short write
+}
+
+// startExitHook runs the process's on_exit_command in the background, if it has one.
+// Called by the exit goroutine once the final status is recorded.
+func startExitHook(tracker *ProcessTracker) {
+	tracker.Mutex.RLock()
+	hook := tracker.OnExitCommand
+	tracker.Mutex.RUnlock()
+
+	if len(hook) == 0 {
+		return
+	}
+	go runExitHook(tracker, hook)
+}
+
+// runExitHook runs hook with SIDEKICK_* variables describing how the process ended
+// and stores the outcome in tracker.OnExitResult
+func runExitHook(tracker *ProcessTracker, hook []string) {
+	tracker.Mutex.RLock()
+	workingDir := tracker.WorkingDir
+	credential := tracker.Credential
+	env := append(os.Environ(),
+		"SIDEKICK_PROCESS_ID="+tracker.ID,
+		"SIDEKICK_PROCESS_NAME="+tracker.Name,
+		"SIDEKICK_STATUS="+string(tracker.Status),
+		"SIDEKICK_EXIT_REASON="+tracker.ExitReason,
+		"SIDEKICK_SIGNAL="+tracker.Signal,
+	)
+	if tracker.ExitCode != nil {
+		env = append(env, "SIDEKICK_EXIT_CODE="+strconv.Itoa(*tracker.ExitCode))
+	}
+	tracker.Mutex.RUnlock()
+
+	result := &ExitHookResult{StartTime: time.Now()}
+	output := &cappedBuffer{limit: ExitHookOutputLimit}
+
+	err := func() error {
+		// The policy may have been reloaded since the spawn
+		if err := spawnPolicy.Check(hook[0], workingDir); err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), ExitHookTimeout)
+		defer cancel()
+
+		cmd := exec.CommandContext(ctx, hook[0], hook[1:]...)
+		cmd.Dir = workingDir
+		cmd.Env = env
+		cmd.Stdout = output
+		cmd.Stderr = output
+		configureProcessGroup(cmd)
+		if credential != nil {
+			if err := applyProcessCredential(cmd, credential); err != nil {
+				return err
+			}
+		}
+		// Kill the hook's whole group on timeout, and don't wait on pipes its children keep open
+		cmd.Cancel = func() error { return forceKillProcessGroup(cmd.Process.Pid) }
+		cmd.WaitDelay = time.Second
+
+		err := cmd.Run()
+		if cmd.ProcessState != nil {
+			exitCode := cmd.ProcessState.ExitCode()
+			result.ExitCode = &exitCode
+		}
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("timed out after %s", ExitHookTimeout)
+		}
+		return err
+	}()
+
+	result.Duration = time.Since(result.StartTime).String()
+	result.Output = output.buf.String()
+	result.Truncated = output.truncated
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	tracker.Mutex.Lock()
+	tracker.OnExitResult = result
+	tracker.Mutex.Unlock()
+
+	details := fmt.Sprintf("ID: %s, command: %v, duration: %s", tracker.ID, hook, result.Duration)
+	if result.ExitCode != nil {
+		details += fmt.Sprintf(", exit code: %d", *result.ExitCode)
+	}
+	if result.Output != "" {
+		details += fmt.Sprintf(", output: %s", strings.TrimSpace(result.Output))
+	}
+	if err != nil {
+		LogWarn("Process", "on_exit_command failed", fmt.Sprintf("%s, error: %v", details, err))
+	} else {
+		LogInfo("Process", "on_exit_command finished", details)
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ProcessExportRecord is one process in an export_processes snapshot: metadata only, no output
+type ProcessExportRecord struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name,omitempty"`
+	SessionID  string            `json:"session_id,omitempty"`
+	PID        int               `json:"pid"`
+	Command    string            `json:"command"`
+	Args       []string          `json:"args"`
+	WorkingDir string            `json:"working_dir,omitempty"`
+	Status     ProcessStatus     `json:"status"`
+	StartTime  time.Time         `json:"start_time"`
+	EndTime    *time.Time        `json:"end_time,omitempty"`
+	DurationMs *int64            `json:"duration_ms,omitempty"`
+	ExitCode   *int              `json:"exit_code,omitempty"`
+	ExitReason string            `json:"exit_reason,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
+}
+
+// processExportColumns is the CSV header, in record field order
+var processExportColumns = []string{
+	"id", "name", "session_id", "pid", "command", "args", "working_dir", "status",
+	"start_time", "end_time", "duration_ms", "exit_code", "exit_reason", "labels",
+}
+
+// exportRecord captures a tracker's metadata
+func exportRecord(tracker *ProcessTracker) ProcessExportRecord {
+	tracker.Mutex.RLock()
+	defer tracker.Mutex.RUnlock()
+
+	record := ProcessExportRecord{
+		ID:         tracker.ID,
+		Name:       tracker.Name,
+		SessionID:  tracker.SessionID,
+		PID:        tracker.PID,
+		Command:    tracker.Command,
+		Args:       append([]string{}, tracker.Args...),
+		WorkingDir: tracker.WorkingDir,
+		Status:     tracker.Status,
+		StartTime:  tracker.StartTime,
+		EndTime:    tracker.EndTime,
+		ExitCode:   tracker.ExitCode,
+		ExitReason: tracker.ExitReason,
+	}
+	if tracker.Duration != nil {
+		ms := tracker.Duration.Milliseconds()
+		record.DurationMs = &ms
+	}
+	if len(tracker.Labels) > 0 {
+		record.Labels = make(map[string]string, len(tracker.Labels))
+		for key, value := range tracker.Labels {
+			record.Labels[key] = value
+		}
+	}
+	return record
+}
+
+// encodeProcessExport renders records as indented JSON or as CSV with a header row. In CSV,
+// args are joined with spaces and labels are written as sorted key=value pairs joined with ';'.
+func encodeProcessExport(records []ProcessExportRecord, format string) ([]byte, error) {
+	if format == "json" {
+		return json.MarshalIndent(records, "", "  ")
+	}
+
+	var out bytes.Buffer
+	writer := csv.NewWriter(&out)
+	writer.Write(processExportColumns)
+	for _, record := range records {
+		var endTime, durationMs, exitCode string
+		if record.EndTime != nil {
+			endTime = record.EndTime.Format(time.RFC3339)
+		}
+		if record.DurationMs != nil {
+			durationMs = strconv.FormatInt(*record.DurationMs, 10)
+		}
+		if record.ExitCode != nil {
+			exitCode = strconv.Itoa(*record.ExitCode)
+		}
+		labels := make([]string, 0, len(record.Labels))
+		for key, value := range record.Labels {
+			labels = append(labels, key+"="+value)
+		}
+		sort.Strings(labels)
+
+		writer.Write([]string{
+			record.ID, record.Name, record.SessionID, strconv.Itoa(record.PID), record.Command,
+			strings.Join(record.Args, " "), record.WorkingDir, string(record.Status),
+			record.StartTime.Format(time.RFC3339), endTime, durationMs, exitCode, record.ExitReason,
+			strings.Join(labels, ";"),
+		})
+	}
+	writer.Flush()
+	return out.Bytes(), writer.Error()
+}
+
+// writeProcessExport writes an export to path, readable by the owner only like state dumps
+func writeProcessExport(path string, data []byte) error {
+	return os.WriteFile(path, data, 0o600)
+}
+
+// handleExportProcesses exports the metadata of all tracked processes (optionally filtered by
+// status and labels) as JSON or CSV, written to path or returned inline
+func handleExportProcesses(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	format := getStringArg(request, "format", "json")
+	if format != "json" && format != "csv" {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid format '%s' (use 'json' or 'csv')", format)), nil
+	}
+	path := getStringArg(request, "path", "")
+	if path != "" {
+		if !filepath.IsAbs(path) {
+			return mcp.NewToolResultError("path must be absolute"), nil
+		}
+		// Files are confined like working directories when --allowed-workdir is set. A new
+		// file can't be resolved yet, so its directory is checked instead.
+		target := path
+		if _, err := os.Lstat(path); err != nil {
+			target = filepath.Dir(path)
+		}
+		if err := spawnPolicy.CheckPath(target); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+	status := getStringArg(request, "status", "")
+	switch ProcessStatus(status) {
+	case "", StatusPending, StatusRunning, StatusCompleted, StatusFailed, StatusKilled:
+	default:
+		return mcp.NewToolResultError(fmt.Sprintf("invalid status '%s': must be pending, running, completed, failed, or killed", status)), nil
+	}
+	labelSelector := getStringMapArg(request, "labels")
+
+	processes := registry.getAllProcesses()
+	sort.Slice(processes, func(i, j int) bool {
+		return processes[i].StartTime.Before(processes[j].StartTime)
+	})
+	records := make([]ProcessExportRecord, 0, len(processes))
+	for _, tracker := range processes {
+		tracker.Mutex.RLock()
+		matches := matchesLabels(tracker, labelSelector) && (status == "" || string(tracker.Status) == status)
+		tracker.Mutex.RUnlock()
+		if matches {
+			records = append(records, exportRecord(tracker))
+		}
+	}
+
+	data, err := encodeProcessExport(records, format)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to encode export: %v", err)), nil
+	}
+
+	result := map[string]any{
+		"format":    format,
+		"processes": len(records),
+	}
+	if path == "" {
+		result["content"] = string(data)
+	} else {
+		if err := writeProcessExport(path, data); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to write export: %v", err)), nil
+		}
+		LogInfo("Process", "Process table exported", fmt.Sprintf("Path: %s, format: %s, processes: %d", path, format, len(records)))
+		result["path"] = path
+		result["bytes"] = len(data)
+	}
+
+	resultBytes, _ := json.Marshal(result)
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
@@ -0,0 +1,35 @@
+package main
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// gitCaptureTimeout bounds how long spawn waits on git when capture_git is enabled
+const gitCaptureTimeout = 2 * time.Second
+
+// captureGitContext returns the current branch and short commit of the git repo containing dir.
+// Empty strings are returned when dir is not inside a repo or git is unavailable.
+func captureGitContext(dir string) (branch, commit string) {
+	ctx, cancel := context.WithTimeout(context.Background(), gitCaptureTimeout)
+	defer cancel()
+
+	runGit := func(args ...string) string {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Dir = dir
+		output, err := cmd.Output()
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(string(output))
+	}
+
+	commit = runGit("rev-parse", "--short", "HEAD")
+	if commit == "" {
+		return "", ""
+	}
+	branch = runGit("rev-parse", "--abbrev-ref", "HEAD")
+	return branch, commit
+}
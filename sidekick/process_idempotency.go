@@ -0,0 +1,96 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// IdempotencyWindow is how long a finished process still answers spawns with its idempotency key
+const IdempotencyWindow = 10 * time.Minute
+
+// idempotencyEntry maps a client-supplied key to the process it spawned
+type idempotencyEntry struct {
+	processID string
+	starting  bool // Reserved but not yet registered (spawn in progress)
+}
+
+// IdempotencyStore deduplicates spawn_process calls carrying the same idempotency_key.
+// Keys are scoped per session so unrelated clients cannot collide.
+type IdempotencyStore struct {
+	mu      sync.Mutex
+	entries map[string]*idempotencyEntry
+	window  time.Duration
+}
+
+// spawnIdempotency tracks idempotency keys for spawn_process
+var spawnIdempotency = NewIdempotencyStore(IdempotencyWindow)
+
+// NewIdempotencyStore creates a store whose keys expire window after their process finishes
+func NewIdempotencyStore(window time.Duration) *IdempotencyStore {
+	return &IdempotencyStore{
+		entries: make(map[string]*idempotencyEntry),
+		window:  window,
+	}
+}
+
+func idempotencyScope(sessionID, key string) string {
+	return sessionID + "\x00" + key
+}
+
+// Reserve claims key for processID. If the key already belongs to a live or recently
+// finished process, that process ID is returned with reserved=false instead.
+func (s *IdempotencyStore) Reserve(r *ProcessRegistry, sessionID, key, processID string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pruneLocked(r)
+
+	scoped := idempotencyScope(sessionID, key)
+	if entry, exists := s.entries[scoped]; exists {
+		return entry.processID, false
+	}
+
+	s.entries[scoped] = &idempotencyEntry{processID: processID, starting: true}
+	return processID, true
+}
+
+// Confirm marks a reserved key as backed by a registered process
+func (s *IdempotencyStore) Confirm(sessionID, key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry, exists := s.entries[idempotencyScope(sessionID, key)]; exists {
+		entry.starting = false
+	}
+}
+
+// Release frees a reserved key after a failed spawn so it can be retried
+func (s *IdempotencyStore) Release(sessionID, key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, idempotencyScope(sessionID, key))
+}
+
+// pruneLocked drops keys whose process was removed or finished longer than the window ago
+func (s *IdempotencyStore) pruneLocked(r *ProcessRegistry) {
+	now := time.Now()
+	for scoped, entry := range s.entries {
+		if entry.starting {
+			continue
+		}
+
+		tracker, exists := r.peekProcess(entry.processID)
+		if !exists {
+			delete(s.entries, scoped)
+			continue
+		}
+
+		tracker.Mutex.RLock()
+		expired := isTerminalStatus(tracker.Status) && tracker.EndTime != nil && now.Sub(*tracker.EndTime) > s.window
+		tracker.Mutex.RUnlock()
+		if expired {
+			delete(s.entries, scoped)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bufio"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PartialFlushDelay is how long an unterminated line waits for its newline before flush_partial
+// commits it to the buffer, so prompts like "Password: " become visible
+const PartialFlushDelay = 250 * time.Millisecond
+
+// partialFlusher commits a stream's unterminated line to its buffer once it has waited
+// PartialFlushDelay (flush_partial). When the newline arrives only the rest of the line is
+// written, so the buffer ends up holding the line exactly once. If a line from the other
+// stream got in between, the whole line is written again on a line of its own.
+type partialFlusher struct {
+	out       *lineWriter
+	prefix    string
+	mu        sync.Mutex
+	pending   string      // The unterminated line read so far
+	committed string      // The part of pending already in the buffer
+	timer     *time.Timer // Armed while uncommitted pending text waits
+}
+
+func newPartialFlusher(out *lineWriter, prefix string) *partialFlusher {
+	return &partialFlusher{out: out, prefix: prefix}
+}
+
+// split wraps a split function to note the unterminated line it is waiting to complete
+func (f *partialFlusher) split(inner bufio.SplitFunc) bufio.SplitFunc {
+	return func(data []byte, atEOF bool) (int, []byte, error) {
+		advance, token, err := inner(data, atEOF)
+		if advance == 0 && token == nil && err == nil && len(data) > 0 {
+			f.wait(string(data))
+		}
+		return advance, token, err
+	}
+}
+
+// wait records the pending line and arms the flush timer if it is not already running
+func (f *partialFlusher) wait(pending string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.pending = strings.TrimSuffix(pending, "\r") // Possibly the first half of a \r\n
+	if len(f.pending) > len(f.committed) && f.timer == nil {
+		f.timer = time.AfterFunc(f.out.partialAfter, f.flush)
+	}
+}
+
+// flush commits the pending text that is not in the buffer yet
+func (f *partialFlusher) flush() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.timer = nil
+	if len(f.pending) <= len(f.committed) || !strings.HasPrefix(f.pending, f.committed) {
+		return
+	}
+	pending, committed := f.pending, f.committed
+	f.out.append(f, true, func(continued bool) string {
+		if continued && committed != "" {
+			return f.redact(pending[len(committed):])
+		}
+		return f.prefix + f.redact(pending)
+	})
+	f.committed = pending
+}
+
+// finish writes a complete line, or the rest of it when part of it was already committed
+func (f *partialFlusher) finish(line string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.stopLocked()
+	committed := f.committed
+	f.pending, f.committed = "", ""
+	if committed == "" {
+		f.out.writePrefixed(f.prefix, line)
+		return
+	}
+
+	if f.out.observe != nil {
+		f.out.observe(line)
+	}
+	f.out.append(f, false, func(continued bool) string {
+		if !continued {
+			return f.prefix + f.redact(line) + "\n"
+		}
+		if strings.HasPrefix(line, committed) {
+			return f.redact(line[len(committed):]) + "\n"
+		}
+		return "\n" + f.prefix + f.redact(line) + "\n" // Truncation changed the line's start
+	})
+}
+
+// stop cancels a pending flush once the stream has ended
+func (f *partialFlusher) stop() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stopLocked()
+}
+
+// stopLocked cancels the flush timer. Must be called with mu held.
+func (f *partialFlusher) stopLocked() {
+	if f.timer != nil {
+		f.timer.Stop()
+		f.timer = nil
+	}
+}
+
+// redact applies the writer's redaction to one piece of a line. Pieces are redacted on their
+// own, so a secret split across a flush boundary may be missed.
+func (f *partialFlusher) redact(text string) string {
+	if f.out.redact == nil {
+		return text
+	}
+	return f.out.redact(text)
+}
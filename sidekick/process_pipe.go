@@ -0,0 +1,259 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	MaxActivePipes   = 64               // Pipes allowed to run at once
+	PipeWriteTimeout = 30 * time.Second // A destination that stops reading stdin this long fails the pipe
+)
+
+// Pipe states
+const (
+	PipeRunning   = "running"
+	PipeCompleted = "completed" // The source finished and all of its output was delivered
+	PipeFailed    = "failed"    // The destination exited, closed stdin or stopped reading
+)
+
+// ProcessPipe streams a source process's stdout into a destination process's stdin
+type ProcessPipe struct {
+	ID          string     `json:"pipe_id"`
+	SourceID    string     `json:"source_process_id"`
+	DestID      string     `json:"dest_process_id"`
+	CloseStdin  bool       `json:"close_stdin"`
+	State       string     `json:"state"`
+	BytesPiped  int64      `json:"bytes_piped"`
+	LostBytes   int64      `json:"lost_bytes,omitempty"` // Source output evicted from its buffer before it could be piped
+	Error       string     `json:"error,omitempty"`
+	StdinClosed bool       `json:"stdin_closed,omitempty"`
+	StartTime   time.Time  `json:"start_time"`
+	EndTime     *time.Time `json:"end_time,omitempty"`
+}
+
+// PipeRegistry tracks the pipes between processes
+type PipeRegistry struct {
+	mutex sync.Mutex
+	pipes map[string]*ProcessPipe
+}
+
+var processPipes = &PipeRegistry{pipes: make(map[string]*ProcessPipe)}
+
+// add registers a new pipe unless MaxActivePipes are already running
+func (r *PipeRegistry) add(pipe *ProcessPipe) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	active := 0
+	for id, existing := range r.pipes {
+		if existing.State == PipeRunning {
+			active++
+		} else if existing.EndTime != nil && time.Since(*existing.EndTime) > RemovedProcessTTL {
+			delete(r.pipes, id) // Finished pipes stay visible for a minute
+		}
+	}
+	if active >= MaxActivePipes {
+		return fmt.Errorf("too many active pipes (max %d)", MaxActivePipes)
+	}
+	r.pipes[pipe.ID] = pipe
+	return nil
+}
+
+// update applies fn to the pipe under the registry lock
+func (r *PipeRegistry) update(pipe *ProcessPipe, fn func(*ProcessPipe)) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	fn(pipe)
+}
+
+// forProcess returns copies of the pipes reading from or writing to processID
+func (r *PipeRegistry) forProcess(processID string) []ProcessPipe {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var pipes []ProcessPipe
+	for _, pipe := range r.pipes {
+		if pipe.SourceID == processID || pipe.DestID == processID {
+			pipes = append(pipes, *pipe)
+		}
+	}
+	return pipes
+}
+
+// runPipe copies source stdout from cursor into the destination's stdin until the source
+// finishes, then optionally closes that stdin
+func runPipe(pipe *ProcessPipe, source, dest *ProcessTracker, cursor int64) {
+	ticker := time.NewTicker(time.Duration(DelayCheckInterval) * time.Millisecond)
+	defer ticker.Stop()
+
+	finish := func(state, errMsg string) {
+		stdinClosed := false
+		if state == PipeCompleted && pipe.CloseStdin {
+			dest.Mutex.Lock()
+			if dest.StdinWriter != nil && dest.StdinWriter.Close() == nil {
+				dest.StdinWriter = nil
+				stdinClosed = true
+			}
+			dest.Mutex.Unlock()
+		}
+
+		now := time.Now()
+		processPipes.update(pipe, func(p *ProcessPipe) {
+			p.State, p.Error, p.StdinClosed, p.EndTime = state, errMsg, stdinClosed, &now
+		})
+		details := fmt.Sprintf("Pipe: %s, %s -> %s, bytes: %d", pipe.ID, pipe.SourceID, pipe.DestID, pipe.BytesPiped)
+		if errMsg != "" {
+			LogWarn("ProcessPipe", "Pipe failed: "+errMsg, details)
+		} else {
+			LogInfo("ProcessPipe", "Pipe completed", details)
+		}
+	}
+
+	for {
+		// Read the status first: a finished process has flushed all of its output
+		source.Mutex.RLock()
+		sourceDone := isTerminalStatus(source.Status)
+		source.Mutex.RUnlock()
+
+		content, start := source.StdoutBuffer.GetContentAndOffsetFromCursor(cursor)
+		if content == "" && sourceDone {
+			finish(PipeCompleted, "")
+			return
+		}
+
+		dest.Mutex.RLock()
+		destStatus := dest.Status
+		stdin := dest.StdinWriter
+		dest.Mutex.RUnlock()
+		if destStatus != StatusRunning {
+			finish(PipeFailed, fmt.Sprintf("destination process is %s", destStatus))
+			return
+		}
+		if stdin == nil {
+			finish(PipeFailed, "destination stdin was closed")
+			return
+		}
+
+		if content != "" {
+			// Bound the write, then clear the deadline so send_process_input is not affected
+			d, hasDeadline := stdin.(interface{ SetWriteDeadline(time.Time) error })
+			if hasDeadline {
+				d.SetWriteDeadline(time.Now().Add(PipeWriteTimeout))
+			}
+			written, err := stdin.Write([]byte(content))
+			if hasDeadline {
+				d.SetWriteDeadline(time.Time{})
+			}
+			processPipes.update(pipe, func(p *ProcessPipe) {
+				p.LostBytes += start - cursor
+				p.BytesPiped += int64(written)
+			})
+			cursor = start + int64(written)
+			if errors.Is(err, os.ErrDeadlineExceeded) {
+				finish(PipeFailed, "destination is not reading stdin")
+				return
+			}
+			if err != nil {
+				finish(PipeFailed, fmt.Sprintf("write to destination stdin failed: %v", err))
+				return
+			}
+		}
+
+		if sourceDone {
+			finish(PipeCompleted, "")
+			return
+		}
+
+		select {
+		case <-ticker.C:
+		case <-shutdownChan:
+			finish(PipeFailed, "sidekick is shutting down")
+			return
+		}
+	}
+}
+
+// handlePipeProcesses connects a source process's stdout to a destination process's stdin,
+// like a shell pipeline, without the client relaying the bytes
+func handlePipeProcesses(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	sourceID, err := request.RequireString("source_process_id")
+	if err != nil {
+		return mcp.NewToolResultError("Missing or invalid 'source_process_id' argument"), nil
+	}
+	destID, err := request.RequireString("dest_process_id")
+	if err != nil {
+		return mcp.NewToolResultError("Missing or invalid 'dest_process_id' argument"), nil
+	}
+	if sourceID == destID {
+		return mcp.NewToolResultError("A process cannot be piped into itself"), nil
+	}
+
+	from := getStringArg(request, "from", "new")
+	if from != "new" && from != "start" {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid from value '%s' (use 'new' or 'start')", from)), nil
+	}
+	closeStdin := getBoolArg(request, "close_stdin", true)
+
+	source, exists := registry.getProcess(sourceID)
+	if !exists {
+		return mcp.NewToolResultError(fmt.Sprintf("Process %s not found", sourceID)), nil
+	}
+	dest, exists := registry.getProcess(destID)
+	if !exists {
+		return mcp.NewToolResultError(fmt.Sprintf("Process %s not found", destID)), nil
+	}
+
+	dest.Mutex.RLock()
+	destStatus := dest.Status
+	hasStdin := dest.StdinWriter != nil
+	dest.Mutex.RUnlock()
+	if destStatus != StatusRunning {
+		return mcp.NewToolResultError(fmt.Sprintf("Process %s is not running (status: %s)", destID, destStatus)), nil
+	}
+	if !hasStdin {
+		return mcp.NewToolResultError("Destination process stdin is not available"), nil
+	}
+
+	// "new" skips what the source printed so far; "start" also pipes what is still buffered
+	cursor := source.StdoutBuffer.TotalBytes()
+	if from == "start" {
+		cursor = 0
+	}
+
+	pipe := &ProcessPipe{
+		ID:         uuid.New().String(),
+		SourceID:   sourceID,
+		DestID:     destID,
+		CloseStdin: closeStdin,
+		State:      PipeRunning,
+		StartTime:  time.Now(),
+	}
+	if err := processPipes.add(pipe); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	// The pipe outlives this request; it stops with the source, the destination, or shutdown
+	go runPipe(pipe, source, dest, cursor)
+
+	LogInfo("ProcessPipe", "Pipe started", fmt.Sprintf("Pipe: %s, %s -> %s, from: %s", pipe.ID, sourceID, destID, from))
+
+	result := map[string]any{
+		"pipe_id":           pipe.ID,
+		"source_process_id": sourceID,
+		"dest_process_id":   destID,
+		"from":              from,
+		"close_stdin":       closeStdin,
+		"state":             PipeRunning,
+	}
+	resultBytes, _ := json.Marshal(result)
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// stringListFlag is a command-line flag that can be repeated to build a list
+type stringListFlag []string
+
+func (f *stringListFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringListFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// SpawnPolicy restricts where processes may run and which commands they may execute.
+// An empty list means no restriction for that dimension.
+type SpawnPolicy struct {
+	mu              sync.RWMutex
+	allowedWorkdirs []string
+	allowedCommands map[string]bool
+}
+
+// spawnPolicy is configured from --allowed-workdir and --allowed-command (or spawn_policy in the config file)
+var spawnPolicy = &SpawnPolicy{}
+
+// spawnPolicyFlags holds --allowed-workdir and --allowed-command. A restriction set there
+// wins over spawn_policy in the config file, so a reload cannot lift it.
+var spawnPolicyFlags SpawnPolicyConfig
+
+// Configure sets the allowed working directory prefixes and commands.
+// On error the previous policy is left unchanged.
+func (p *SpawnPolicy) Configure(workdirs, commands []string) error {
+	var allowedWorkdirs []string
+	for _, dir := range workdirs {
+		resolved, err := resolveWorkdir(dir)
+		if err != nil {
+			return fmt.Errorf("invalid --allowed-workdir '%s': %v", dir, err)
+		}
+		allowedWorkdirs = append(allowedWorkdirs, resolved)
+	}
+
+	var allowedCommands map[string]bool
+	if len(commands) > 0 {
+		allowedCommands = make(map[string]bool, len(commands))
+		for _, command := range commands {
+			allowedCommands[filepath.Clean(command)] = true
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.allowedWorkdirs = allowedWorkdirs
+	p.allowedCommands = allowedCommands
+	return nil
+}
+
+// Rules returns the resolved allowed working directories and the sorted allowed commands
+func (p *SpawnPolicy) Rules() ([]string, []string) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return slices.Clone(p.allowedWorkdirs), p.commandList()
+}
+
+// replace swaps in the rules of another, already configured policy
+func (p *SpawnPolicy) replace(other *SpawnPolicy) {
+	other.mu.RLock()
+	workdirs, commands := other.allowedWorkdirs, other.allowedCommands
+	other.mu.RUnlock()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.allowedWorkdirs = workdirs
+	p.allowedCommands = commands
+}
+
+// widens reports whether other allows a command or working directory that p refuses
+func (p *SpawnPolicy) widens(other *SpawnPolicy) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	other.mu.RLock()
+	defer other.mu.RUnlock()
+
+	if p.allowedCommands != nil {
+		if other.allowedCommands == nil {
+			return true
+		}
+		for command := range other.allowedCommands {
+			if !p.allowedCommands[command] {
+				return true
+			}
+		}
+	}
+
+	if len(p.allowedWorkdirs) > 0 {
+		if len(other.allowedWorkdirs) == 0 {
+			return true
+		}
+		for _, dir := range other.allowedWorkdirs {
+			if !p.withinWorkdirs(dir) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Active reports whether any spawn restriction is configured
+func (p *SpawnPolicy) Active() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return len(p.allowedWorkdirs) > 0 || p.allowedCommands != nil
+}
+
+// Check returns a descriptive error if the command or working directory is not allowed
+func (p *SpawnPolicy) Check(command, workingDir string) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.allowedCommands != nil && !p.allowedCommands[filepath.Clean(command)] {
+		return fmt.Errorf("command not allowed: %s (allowed commands: %s)", command, strings.Join(p.commandList(), ", "))
+	}
+
+	if len(p.allowedWorkdirs) == 0 {
+		return nil
+	}
+
+	resolved, err := resolveWorkdir(workingDir)
+	if err != nil {
+		return fmt.Errorf("working directory not allowed: %s (%v)", workingDir, err)
+	}
+	if p.withinWorkdirs(resolved) {
+		return nil
+	}
+	return fmt.Errorf("working directory not allowed: %s is not under any of: %s", resolved, strings.Join(p.allowedWorkdirs, ", "))
+}
+
+// CheckPath returns a descriptive error if a server-side file lies outside the allowed working directories
+func (p *SpawnPolicy) CheckPath(path string) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if len(p.allowedWorkdirs) == 0 {
+		return nil
+	}
+
+	resolved, err := resolveWorkdir(path)
+	if err != nil {
+		return fmt.Errorf("path not allowed: %s (%v)", path, err)
+	}
+	if p.withinWorkdirs(resolved) {
+		return nil
+	}
+	return fmt.Errorf("path not allowed: %s is not under any of: %s", resolved, strings.Join(p.allowedWorkdirs, ", "))
+}
+
+// withinWorkdirs reports whether a resolved path is one of the allowed directories or below one
+// Must be called with p.mu held
+func (p *SpawnPolicy) withinWorkdirs(resolved string) bool {
+	for _, prefix := range p.allowedWorkdirs {
+		if resolved == prefix || strings.HasPrefix(resolved, prefix+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+// commandList returns the allowed commands, sorted, for error messages
+// Must be called with p.mu held
+func (p *SpawnPolicy) commandList() []string {
+	commands := make([]string, 0, len(p.allowedCommands))
+	for command := range p.allowedCommands {
+		commands = append(commands, command)
+	}
+	slices.Sort(commands)
+	return commands
+}
+
+// resolveWorkdir returns the absolute, symlink-free form of dir (the current directory when empty)
+func resolveWorkdir(dir string) (string, error) {
+	if dir == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			return "", err
+		}
+		dir = cwd
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(abs)
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MaxProgressRegexLength caps the length of a progress_regex pattern
+const MaxProgressRegexLength = 512
+
+// progressBarWidth is the width of the TUI progress bar in cells
+const progressBarWidth = 20
+
+// ProcessProgress is the latest progress a process reported through its progress_regex
+type ProcessProgress struct {
+	Current   float64   `json:"progress_current"`
+	Total     float64   `json:"progress_total"`
+	Percent   float64   `json:"progress_percent"`
+	UpdatedAt time.Time `json:"progress_updated_at"`
+}
+
+// progressMatcher extracts progress from output lines. It has its own lock so output
+// streaming never waits on readers holding the tracker mutex.
+type progressMatcher struct {
+	pattern      *regexp.Regexp
+	currentGroup int
+	totalGroup   int // 0 = the pattern captures a percentage
+	mu           sync.Mutex
+	latest       *ProcessProgress
+}
+
+// newProgressMatcher compiles a progress_regex. The pattern needs named groups "current" and
+// "total", or two groups (current, then total), or a single group holding a percentage.
+func newProgressMatcher(pattern string) (*progressMatcher, error) {
+	if len(pattern) > MaxProgressRegexLength {
+		return nil, fmt.Errorf("progress_regex cannot exceed %d bytes", MaxProgressRegexLength)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid progress_regex: %v", err)
+	}
+
+	matcher := &progressMatcher{pattern: re}
+	current, total := re.SubexpIndex("current"), re.SubexpIndex("total")
+	switch {
+	case current > 0 && total > 0:
+		matcher.currentGroup, matcher.totalGroup = current, total
+	case current > 0 || total > 0:
+		return nil, fmt.Errorf("progress_regex needs both (?P<current>...) and (?P<total>...) groups")
+	case re.NumSubexp() >= 2:
+		matcher.currentGroup, matcher.totalGroup = 1, 2
+	case re.NumSubexp() == 1:
+		matcher.currentGroup = 1
+	default:
+		return nil, fmt.Errorf("progress_regex needs a capture group for the current/total values or a percentage")
+	}
+	return matcher, nil
+}
+
+// observe records the progress in line if it matches
+func (m *progressMatcher) observe(line string) {
+	groups := m.pattern.FindStringSubmatch(line)
+	if groups == nil {
+		return
+	}
+
+	current, err := parseProgressNumber(groups[m.currentGroup])
+	if err != nil {
+		return
+	}
+	total := 100.0
+	if m.totalGroup > 0 {
+		if total, err = parseProgressNumber(groups[m.totalGroup]); err != nil {
+			return
+		}
+	}
+
+	progress := &ProcessProgress{Current: current, Total: total, UpdatedAt: time.Now()}
+	if total > 0 {
+		progress.Percent = math.Round(math.Max(0, math.Min(current/total, 1))*1000) / 10
+	}
+
+	m.mu.Lock()
+	m.latest = progress
+	m.mu.Unlock()
+}
+
+// snapshot returns the latest progress, or nil before the first match
+func (m *progressMatcher) snapshot() *ProcessProgress {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.latest == nil {
+		return nil
+	}
+	progress := *m.latest
+	return &progress
+}
+
+// parseProgressNumber parses a captured value such as "12", "3.5", "1,024" or "45%"
+func parseProgressNumber(text string) (float64, error) {
+	text = strings.TrimSuffix(strings.TrimSpace(text), "%")
+	return strconv.ParseFloat(strings.ReplaceAll(text, ",", ""), 64)
+}
+
+// formatProgress renders progress for the TUI, e.g. "[██████░░░░] 12/40 (30.0%)"
+func formatProgress(progress *ProcessProgress) string {
+	filled := int(progress.Percent / 100 * progressBarWidth)
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", progressBarWidth-filled)
+	return fmt.Sprintf("[%s] %s/%s (%.1f%%)", bar,
+		strconv.FormatFloat(progress.Current, 'f', -1, 64),
+		strconv.FormatFloat(progress.Total, 'f', -1, 64),
+		progress.Percent)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+)
+
+const (
+	RedactionMarker        = "***" // Replaces every redacted match in stored output
+	MaxRedactPatterns      = 32    // redact_patterns entries allowed per process
+	MaxRedactPatternLength = 512   // Length cap for each redact_patterns entry
+)
+
+// redactRule replaces matches of pattern with replacement, which may reference groups ($1)
+// to keep the context around a secret
+type redactRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// builtinRedactRules are enabled by redact_builtin: common credentials that tools tend to echo
+var builtinRedactRules = []redactRule{
+	{regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`), RedactionMarker},                                              // AWS access key ID
+	{regexp.MustCompile(`(?i)(aws_secret_access_key["']?\s*[:=]\s*["']?)[A-Za-z0-9/+=]{40}`), "${1}" + RedactionMarker}, // AWS secret access key
+	{regexp.MustCompile(`(?i)\b(bearer\s+)[A-Za-z0-9\-._~+/]+=*`), "${1}" + RedactionMarker},                            // Bearer tokens
+	{regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`), RedactionMarker},                                             // GitHub tokens
+	{regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`), RedactionMarker},                     // JWTs
+	{regexp.MustCompile(`(://)[^/\s:@]+:[^/\s@]+@`), "${1}" + RedactionMarker + "@"},                                    // user:password in URLs
+}
+
+// outputRedactor replaces secrets in output lines before they are stored. It is immutable
+// once built, so the output streams share it without locking.
+type outputRedactor struct {
+	rules []redactRule
+}
+
+// newOutputRedactor compiles redact_patterns, adding the built-in patterns when builtin is set.
+// Returns nil when there is nothing to redact.
+func newOutputRedactor(patterns []string, builtin bool) (*outputRedactor, error) {
+	if len(patterns) > MaxRedactPatterns {
+		return nil, fmt.Errorf("redact_patterns cannot have more than %d entries", MaxRedactPatterns)
+	}
+
+	redactor := &outputRedactor{}
+	if builtin {
+		redactor.rules = append(redactor.rules, builtinRedactRules...)
+	}
+	for _, pattern := range patterns {
+		if pattern == "" {
+			return nil, fmt.Errorf("redact_patterns cannot contain an empty pattern")
+		}
+		if len(pattern) > MaxRedactPatternLength {
+			return nil, fmt.Errorf("redact_patterns entries cannot exceed %d bytes", MaxRedactPatternLength)
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redact_patterns entry %q: %v", pattern, err)
+		}
+		redactor.rules = append(redactor.rules, redactRule{re, RedactionMarker})
+	}
+
+	if len(redactor.rules) == 0 {
+		return nil, nil
+	}
+	return redactor, nil
+}
+
+// redact returns line with every match replaced by RedactionMarker
+func (r *outputRedactor) redact(line string) string {
+	for _, rule := range r.rules {
+		line = rule.pattern.ReplaceAllString(line, rule.replacement)
+	}
+	return line
+}
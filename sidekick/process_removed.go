@@ -0,0 +1,71 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	RemovedProcessTTL   = time.Minute // How long a removed process's output stays readable
+	MaxRemovedProcesses = 20          // Removed processes kept at once; the oldest go first
+)
+
+// removedProcess is a terminated process that was removed from the registry
+type removedProcess struct {
+	tracker   *ProcessTracker
+	removedAt time.Time
+}
+
+// RemovedProcessCache keeps terminated processes for a short while after they are removed
+// (TUI delete, reap_processes, stale cleanup), so get_full_process_output can still read them
+type RemovedProcessCache struct {
+	entries []removedProcess // Oldest first
+	mutex   sync.Mutex
+}
+
+var removedProcesses = &RemovedProcessCache{}
+
+// add keeps tracker readable for RemovedProcessTTL
+func (c *RemovedProcessCache) add(tracker *ProcessTracker) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.pruneLocked(time.Now())
+	c.entries = append(c.entries, removedProcess{tracker: tracker, removedAt: time.Now()})
+	if len(c.entries) > MaxRemovedProcesses {
+		c.entries = c.entries[len(c.entries)-MaxRemovedProcesses:]
+	}
+}
+
+// get returns a recently removed process that has not expired yet
+func (c *RemovedProcessCache) get(id string) (*ProcessTracker, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.pruneLocked(time.Now())
+	for _, entry := range c.entries {
+		if entry.tracker.ID == id {
+			return entry.tracker, true
+		}
+	}
+	return nil, false
+}
+
+// prune drops expired entries so their buffers can be garbage collected
+func (c *RemovedProcessCache) prune() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.pruneLocked(time.Now())
+}
+
+// pruneLocked drops entries removed more than RemovedProcessTTL ago
+// Must be called with c.mutex held
+func (c *RemovedProcessCache) pruneLocked(now time.Time) {
+	expired := 0
+	for expired < len(c.entries) && now.Sub(c.entries[expired].removedAt) > RemovedProcessTTL {
+		expired++
+	}
+	if expired > 0 {
+		c.entries = append([]removedProcess(nil), c.entries[expired:]...)
+	}
+}
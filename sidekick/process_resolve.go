@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// handleResolveCommand shows what spawn_process would execute for the same inputs, without running it:
+// the binary found on PATH, the final argv, the environment variable names, and the working directory
+func handleResolveCommand(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	command, err := request.RequireString("command")
+	if err != nil {
+		return mcp.NewToolResultError("Missing or invalid 'command' argument"), nil
+	}
+	args := getStringArrayArg(request, "args")
+	workingDir := getStringArg(request, "working_dir", "")
+
+	envVars, err := getSpawnEnvArgs(request, workingDir)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	var warnings []string
+
+	effectiveDir := workingDir
+	if effectiveDir == "" {
+		effectiveDir, _ = os.Getwd() // Processes inherit sidekick's working directory
+	} else if info, err := os.Stat(effectiveDir); err != nil {
+		warnings = append(warnings, fmt.Sprintf("working_dir: %v", err))
+	} else if !info.IsDir() {
+		warnings = append(warnings, fmt.Sprintf("working_dir %s is not a directory", effectiveDir))
+	}
+
+	result := map[string]any{
+		"command":     command,
+		"argv":        append([]string{command}, args...),
+		"working_dir": effectiveDir,
+	}
+
+	// Like exec.Command: bare names are searched on sidekick's PATH, paths are taken relative to the working directory
+	lookup := command
+	if strings.ContainsRune(command, filepath.Separator) && !filepath.IsAbs(command) {
+		lookup = filepath.Join(effectiveDir, command)
+	}
+	if path, err := exec.LookPath(lookup); err == nil {
+		if absPath, err := filepath.Abs(path); err == nil {
+			path = absPath
+		}
+		result["path"] = path
+		result["found"] = true
+	} else {
+		result["found"] = false
+		warnings = append(warnings, fmt.Sprintf("command not found: %v", err))
+	}
+	if _, overridden := envVars["PATH"]; overridden {
+		warnings = append(warnings, "env sets PATH, but the command is looked up on sidekick's own PATH; pass an absolute path to be sure")
+	}
+
+	if err := spawnPolicy.Check(command, workingDir); err != nil {
+		result["allowed"] = false
+		warnings = append(warnings, err.Error())
+	} else {
+		result["allowed"] = true
+	}
+
+	// Names only: values can hold secrets
+	envKeys := []string{}
+	for _, entry := range processEnv(envVars) {
+		key, _, _ := strings.Cut(entry, "=")
+		envKeys = append(envKeys, key)
+	}
+	slices.Sort(envKeys)
+	envKeys = slices.Compact(envKeys)
+	overrides := make([]string, 0, len(envVars))
+	for key := range envVars {
+		overrides = append(overrides, key)
+	}
+	slices.Sort(overrides)
+	result["env_keys"] = envKeys
+	result["env_overrides"] = overrides
+
+	if len(warnings) > 0 {
+		result["warnings"] = warnings
+	}
+
+	resultBytes, _ := json.Marshal(result)
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
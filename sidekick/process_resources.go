@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// processResourceScheme is the URI scheme used for process output resources
+const processResourceScheme = "process://"
+
+// processResourceURI builds the resource URI for a process output stream
+func processResourceURI(processID, stream string) string {
+	return fmt.Sprintf("%s%s/%s", processResourceScheme, processID, stream)
+}
+
+// parseProcessResourceURI splits a process resource URI into process ID and stream
+func parseProcessResourceURI(uri string) (string, string, error) {
+	rest, ok := strings.CutPrefix(uri, processResourceScheme)
+	if !ok {
+		return "", "", fmt.Errorf("invalid process resource URI: %s", uri)
+	}
+
+	processID, stream, ok := strings.Cut(rest, "/")
+	if !ok || processID == "" || (stream != "stdout" && stream != "stderr") {
+		return "", "", fmt.Errorf("invalid process resource URI: %s", uri)
+	}
+
+	return processID, stream, nil
+}
+
+// processResourceStreams returns the streams exposed as resources for a process
+// Combined output lives entirely in stdout, so stderr is not advertised
+func processResourceStreams(tracker *ProcessTracker) []string {
+	if tracker.CombineOutput {
+		return []string{"stdout"}
+	}
+	return []string{"stdout", "stderr"}
+}
+
+// processResourceBuffer returns the ring buffer backing a stream
+func processResourceBuffer(tracker *ProcessTracker, stream string) *RingBuffer {
+	if stream == "stderr" {
+		return tracker.StderrBuffer
+	}
+	return tracker.StdoutBuffer
+}
+
+// newProcessResource describes a process output stream, advertising its current size
+// Must be called with tracker.Mutex held (read or write)
+func newProcessResource(tracker *ProcessTracker, stream string) mcp.Resource {
+	displayName := tracker.Command
+	if tracker.Name != "" {
+		displayName = tracker.Name
+	}
+
+	var size int
+	var total int64
+	if buffer := processResourceBuffer(tracker, stream); buffer != nil {
+		size = buffer.Len()
+		total = buffer.TotalBytes()
+	}
+
+	description := fmt.Sprintf("%s of %s (status: %s, %d bytes)", stream, tracker.Command, tracker.Status, size)
+	if tracker.CombineOutput {
+		description = fmt.Sprintf("Combined stdout/stderr of %s (status: %s, %d bytes)", tracker.Command, tracker.Status, size)
+	}
+
+	resource := mcp.NewResource(
+		processResourceURI(tracker.ID, stream),
+		fmt.Sprintf("%s (%s)", displayName, stream),
+		mcp.WithResourceDescription(description),
+		mcp.WithMIMEType("text/plain"),
+	)
+	resource.Meta = mcp.NewMetaFromMap(map[string]any{
+		"size":        size,
+		"total_bytes": total,
+		"status":      string(tracker.Status),
+		"final":       tracker.Status != StatusRunning && tracker.Status != StatusPending,
+	})
+	return resource
+}
+
+// registerProcessResources exposes a process's output streams as MCP resources
+func registerProcessResources(tracker *ProcessTracker) {
+	if globalMCPServer == nil {
+		return
+	}
+
+	tracker.Mutex.RLock()
+	resources := make([]server.ServerResource, 0, 2)
+	for _, stream := range processResourceStreams(tracker) {
+		resources = append(resources, server.ServerResource{
+			Resource: newProcessResource(tracker, stream),
+			Handler:  handleReadProcessResource,
+		})
+	}
+	tracker.Mutex.RUnlock()
+
+	globalMCPServer.AddResources(resources...)
+}
+
+// finalizeProcessResources refreshes the advertised size and status once a process has exited
+// and notifies clients that the resource content is final
+func finalizeProcessResources(tracker *ProcessTracker) {
+	// Subscriptions end with the process; the final update below reaches every client
+	defer outputSubscriptions.RemoveProcess(tracker.ID)
+
+	if globalMCPServer == nil {
+		return
+	}
+
+	// Skip processes that were already removed from the registry
+	if _, exists := registry.peekProcess(tracker.ID); !exists {
+		return
+	}
+
+	registerProcessResources(tracker)
+
+	for _, stream := range processResourceStreams(tracker) {
+		globalMCPServer.SendNotificationToAllClients(mcp.MethodNotificationResourceUpdated, map[string]any{
+			"uri": processResourceURI(tracker.ID, stream),
+		})
+	}
+}
+
+// unregisterProcessResources removes a process's output resources
+func unregisterProcessResources(processID string) {
+	outputSubscriptions.RemoveProcess(processID)
+
+	if globalMCPServer == nil {
+		return
+	}
+
+	globalMCPServer.DeleteResources(
+		processResourceURI(processID, "stdout"),
+		processResourceURI(processID, "stderr"),
+	)
+}
+
+// handleReadProcessResource serves the full buffered output of a process stream
+func handleReadProcessResource(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	processID, stream, err := parseProcessResourceURI(request.Params.URI)
+	if err != nil {
+		return nil, err
+	}
+
+	tracker, exists := registry.getProcess(processID)
+	if !exists {
+		return nil, fmt.Errorf("process %s not found", processID)
+	}
+
+	tracker.Mutex.RLock()
+	defer tracker.Mutex.RUnlock()
+
+	if stream == "stderr" && tracker.CombineOutput {
+		return nil, fmt.Errorf("process %s has combined output - read %s instead", processID, processResourceURI(processID, "stdout"))
+	}
+
+	buffer := processResourceBuffer(tracker, stream)
+	if buffer == nil {
+		return nil, fmt.Errorf("process %s has no %s buffer", processID, stream)
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "text/plain",
+			Text:     buffer.GetContent(),
+			Meta: map[string]any{
+				"size":        buffer.Len(),
+				"total_bytes": buffer.TotalBytes(),
+				"status":      string(tracker.Status),
+			},
+		},
+	}, nil
+}
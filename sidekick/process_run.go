@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	DefaultRunProcessTimeout = 30000 // How long run_process waits when no timeout_ms is given
+	RunProcessKillTimeout    = 2000  // How long run_process waits for a timed-out process to die
+)
+
+// runProcessOnlyArgs are run_process's own arguments; the rest are passed to spawn_process
+var runProcessOnlyArgs = map[string]bool{"timeout_ms": true, "keep": true}
+
+// handleRunProcess spawns a process through spawn_process, waits for it to exit, and returns
+// its full output and exit code in one call. A process still running at timeout_ms is killed
+// unless keep is set. Without keep the finished process is removed from the registry.
+func handleRunProcess(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	timeoutMs := getInt64Arg(request, "timeout_ms", DefaultRunProcessTimeout)
+	if timeoutMs <= 0 || timeoutMs > MaxOutputDelay {
+		return mcp.NewToolResultError(fmt.Sprintf("timeout_ms must be between 1 and %d milliseconds (%s)", MaxOutputDelay, msDuration(MaxOutputDelay))), nil
+	}
+	keep := getBoolArg(request, "keep", false)
+
+	spawnArguments := map[string]any{}
+	if arguments, ok := request.Params.Arguments.(map[string]any); ok {
+		for key, value := range arguments {
+			if !runProcessOnlyArgs[key] {
+				spawnArguments[key] = value
+			}
+		}
+	}
+	spawnRequest := mcp.CallToolRequest{}
+	spawnRequest.Params.Arguments = spawnArguments
+
+	spawned, err := handleSpawnProcess(ctx, spawnRequest)
+	if err != nil {
+		return nil, err
+	}
+	if spawned.IsError {
+		return spawned, nil
+	}
+	var spawnedProcess struct {
+		ProcessID string `json:"process_id"`
+	}
+	if len(spawned.Content) == 0 {
+		return mcp.NewToolResultError("spawn_process returned no result"), nil
+	}
+	text, ok := spawned.Content[0].(mcp.TextContent)
+	if !ok {
+		return mcp.NewToolResultError("spawn_process returned an unexpected result"), nil
+	}
+	if err := json.Unmarshal([]byte(text.Text), &spawnedProcess); err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to parse spawn_process result: %v", err)), nil
+	}
+	if spawnedProcess.ProcessID == "" {
+		return mcp.NewToolResultError("spawn_process returned no process_id"), nil
+	}
+	tracker, exists := registry.getProcess(spawnedProcess.ProcessID)
+	if !exists {
+		return mcp.NewToolResultError("Process disappeared before it could be waited on"), nil
+	}
+
+	exited := waitForProcessExit(ctx, tracker, time.Duration(timeoutMs)*time.Millisecond)
+	if !exited && !keep {
+		// Run-to-completion: nothing is left running behind the caller's back
+		killRequest := mcp.CallToolRequest{}
+		killRequest.Params.Arguments = map[string]any{"process_id": tracker.ID, "timeout_ms": float64(RunProcessKillTimeout)}
+		handleKillProcess(context.Background(), killRequest)
+	}
+	if ctx.Err() != nil {
+		if !keep {
+			registry.removeProcess(tracker.ID)
+		}
+		return mcp.NewToolResultError("request canceled"), nil
+	}
+
+	tracker.Mutex.RLock()
+	result := map[string]any{
+		"status":    tracker.Status,
+		"timed_out": !exited,
+		"stdout":    tracker.StdoutBuffer.GetContent(),
+	}
+	if tracker.StderrBuffer != nil {
+		result["stderr"] = tracker.StderrBuffer.GetContent()
+	}
+	if tracker.ExitCode != nil {
+		result["exit_code"] = *tracker.ExitCode
+	}
+	if tracker.ExitReason != "" {
+		result["exit_reason"] = tracker.ExitReason
+	}
+	if tracker.Duration != nil {
+		result["duration_ms"] = tracker.Duration.Milliseconds()
+		result["duration"] = tracker.Duration.String()
+	}
+	if discarded := tracker.discardedOutputBytes(); discarded > 0 {
+		result["output_truncated"] = true
+		result["discarded_bytes"] = discarded
+	}
+	tracker.Mutex.RUnlock()
+
+	if keep {
+		result["process_id"] = tracker.ID // Still tracked: readable, and still running on a timeout
+	} else {
+		registry.removeProcess(tracker.ID)
+	}
+
+	resultBytes, _ := json.Marshal(result)
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
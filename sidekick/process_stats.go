@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	DefaultStatsTop = 5  // Processes listed in top_by_buffer by default
+	MaxStatsTop     = 50 // Max for process_stats top
+)
+
+// ProcessStats summarizes every tracked process for dashboards and alerting
+type ProcessStats struct {
+	TotalProcesses     int                   `json:"total_processes"`
+	ByStatus           map[ProcessStatus]int `json:"by_status"`
+	TotalBufferedBytes int64                 `json:"total_buffered_bytes"` // Output currently held in memory
+	TotalOutputBytes   int64                 `json:"total_output_bytes"`   // Output ever written, including what was evicted
+	OldestRunning      *ProcessStatsEntry    `json:"oldest_running,omitempty"`
+	TopByBuffer        []ProcessStatsEntry   `json:"top_by_buffer"`
+	Sessions           int                   `json:"sessions"`
+	ConnectedSessions  int                   `json:"connected_sessions"`
+	BufferBudget       *BufferBudgetStats    `json:"buffer_budget,omitempty"` // With --max-total-buffer-bytes
+}
+
+// ProcessStatsEntry identifies one process in ProcessStats
+type ProcessStatsEntry struct {
+	ID            string        `json:"id"`
+	Name          string        `json:"name,omitempty"`
+	Command       string        `json:"command"`
+	Status        ProcessStatus `json:"status"`
+	BufferedBytes int64         `json:"buffered_bytes"`
+	AgeMs         int64         `json:"age_ms"`
+}
+
+// buildProcessStats aggregates the given processes, listing the top processes by buffered bytes
+func buildProcessStats(trackers []*ProcessTracker, top int, now time.Time) *ProcessStats {
+	stats := &ProcessStats{
+		ByStatus: map[ProcessStatus]int{
+			StatusPending:   0,
+			StatusRunning:   0,
+			StatusCompleted: 0,
+			StatusFailed:    0,
+			StatusKilled:    0,
+		},
+		TopByBuffer: []ProcessStatsEntry{},
+	}
+
+	var oldestStart time.Time
+	entries := make([]ProcessStatsEntry, 0, len(trackers))
+	for _, tracker := range trackers {
+		tracker.Mutex.RLock()
+		entry := ProcessStatsEntry{
+			ID:      tracker.ID,
+			Name:    tracker.Name,
+			Command: tracker.Command,
+			Status:  tracker.Status,
+			AgeMs:   now.Sub(tracker.StartTime).Milliseconds(),
+		}
+		for _, buffer := range []*RingBuffer{tracker.StdoutBuffer, tracker.StderrBuffer} {
+			if buffer != nil {
+				entry.BufferedBytes += int64(buffer.Len())
+				stats.TotalOutputBytes += buffer.TotalBytes()
+			}
+		}
+		start := tracker.StartTime
+		tracker.Mutex.RUnlock()
+
+		stats.TotalProcesses++
+		stats.ByStatus[entry.Status]++
+		stats.TotalBufferedBytes += entry.BufferedBytes
+		if entry.Status == StatusRunning && (stats.OldestRunning == nil || start.Before(oldestStart)) {
+			oldest := entry
+			stats.OldestRunning, oldestStart = &oldest, start
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].BufferedBytes > entries[j].BufferedBytes
+	})
+	stats.TopByBuffer = append(stats.TopByBuffer, entries[:min(top, len(entries))]...)
+
+	return stats
+}
+
+// handleProcessStats summarizes all processes in one call: counts by status, buffered bytes,
+// sessions, the oldest running process, and the largest buffers
+func handleProcessStats(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	top := getIntArg(request, "top", DefaultStatsTop)
+	if top < 0 || top > MaxStatsTop {
+		return mcp.NewToolResultError(fmt.Sprintf("top must be between 0 and %d", MaxStatsTop)), nil
+	}
+
+	stats := buildProcessStats(registry.getAllProcesses(), top, time.Now())
+	if bufferBudget.Limit() > 0 {
+		budget := bufferBudget.Stats(stats.TotalBufferedBytes)
+		stats.BufferBudget = &budget
+	}
+
+	sessionManager.mu.RLock()
+	stats.Sessions = len(sessionManager.sessions)
+	for _, session := range sessionManager.sessions {
+		if session.Status == SessionConnected {
+			stats.ConnectedSessions++
+		}
+	}
+	sessionManager.mu.RUnlock()
+
+	resultBytes, _ := json.Marshal(stats)
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
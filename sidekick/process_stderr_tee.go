@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	MaxTeeLinesPerSecond = 20   // stderr lines a process may mirror into the logs per second
+	MaxTeeLineBytes      = 1024 // Longer mirrored lines are cut (the output buffer keeps them whole)
+	TeeRedactLookahead   = 4096 // Bytes past the cut kept for redaction, so a secret across it still matches
+)
+
+// stderrLogTee mirrors a process's stderr lines into the logs under the source "proc:<name>"
+// (tee_stderr_to_logs). Lines beyond MaxTeeLinesPerSecond are counted, not logged, so a chatty
+// process cannot flood the log ring.
+type stderrLogTee struct {
+	reader     io.ReadCloser
+	source     string
+	redact     func(line string) string
+	mu         sync.Mutex
+	partial    []byte
+	discarding bool      // Skipping the rest of an overlong line
+	window     time.Time // Start of the current one-second rate window
+	logged     int       // Lines logged in the current window
+	suppressed int       // Lines dropped by the rate limit since the last notice
+}
+
+// teeStderrToLogs wraps a process's stderr pipe so its lines also reach the logs, or returns the
+// pipe as is when the process did not ask for it
+func teeStderrToLogs(tracker *ProcessTracker, stderr io.ReadCloser) io.ReadCloser {
+	if !tracker.TeeStderrToLogs {
+		return stderr
+	}
+	name := tracker.Name
+	if name == "" {
+		name = tracker.ID[:min(8, len(tracker.ID))]
+	}
+	tee := &stderrLogTee{reader: stderr, source: "proc:" + name}
+	if tracker.Redactor != nil {
+		tee.redact = tracker.Redactor.redact // Secrets stay out of the logs too
+	}
+	return tee
+}
+
+// Read passes stderr through, logging every complete line; the last partial line is logged at EOF
+func (t *stderrLogTee) Read(p []byte) (int, error) {
+	n, err := t.reader.Read(p)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.partial = append(t.partial, p[:n]...)
+	for {
+		newline := bytes.IndexByte(t.partial, '\n')
+		if newline < 0 {
+			break
+		}
+		if !t.discarding {
+			t.logLine(string(bytes.TrimSuffix(t.partial[:newline], []byte("\r"))))
+		}
+		t.discarding = false
+		t.partial = t.partial[newline+1:]
+	}
+	if len(t.partial) > MaxTeeLineBytes+TeeRedactLookahead {
+		// Log the start of an overlong line now and skip the rest of it
+		if !t.discarding {
+			t.logLine(string(t.partial))
+		}
+		t.discarding = true
+		t.partial = t.partial[:0]
+	}
+	if err != nil && len(t.partial) > 0 && !t.discarding {
+		t.logLine(string(t.partial))
+	}
+	if err != nil {
+		t.partial = nil
+		t.flushSuppressed()
+	}
+	return n, err
+}
+
+// Close closes the underlying pipe
+func (t *stderrLogTee) Close() error {
+	return t.reader.Close()
+}
+
+// logLine logs one stderr line unless the rate limit is exhausted. Must be called with mu held.
+func (t *stderrLogTee) logLine(line string) {
+	now := time.Now()
+	if now.Sub(t.window) >= time.Second {
+		t.flushSuppressed()
+		t.window, t.logged = now, 0
+	}
+	if t.logged >= MaxTeeLinesPerSecond {
+		t.suppressed++
+		return
+	}
+	t.logged++
+
+	// Redact before cutting: a secret cut in two would no longer match its pattern
+	if t.redact != nil {
+		line = t.redact(line)
+	}
+	if len(line) > MaxTeeLineBytes {
+		cut := MaxTeeLineBytes
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		line = line[:cut] + LineTruncatedMarker
+	}
+	LogInfo(t.source, line)
+}
+
+// flushSuppressed logs how many lines the rate limit dropped. Must be called with mu held.
+func (t *stderrLogTee) flushSuppressed() {
+	if t.suppressed == 0 {
+		return
+	}
+	LogWarn(t.source, fmt.Sprintf("%d stderr lines not logged (limit %d/s)", t.suppressed, MaxTeeLinesPerSecond),
+		"The process output buffer still has them")
+	t.suppressed = 0
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// OutputNotifyInterval throttles resource-updated notifications for a single subscription
+const OutputNotifyInterval = 250 * time.Millisecond
+
+// outputSubscription is one client session following one process output resource
+type outputSubscription struct {
+	sessionID string
+	uri       string
+	lastSent  time.Time
+	scheduled bool // A trailing notification is queued for the end of the throttle window
+}
+
+// OutputSubscriptions pushes notifications/resources/updated to sessions subscribed to
+// process://{id}/stdout or process://{id}/stderr whenever the stream grows.
+// mcp-go does not route resources/subscribe, so clients subscribe through the
+// subscribe_process_output tool instead of the native request.
+type OutputSubscriptions struct {
+	mu        sync.Mutex
+	byProcess map[string]map[string]*outputSubscription // processID -> sessionID+uri -> subscription
+	send      func(sessionID, uri string)
+}
+
+// outputSubscriptions tracks the process output resource subscriptions
+var outputSubscriptions = NewOutputSubscriptions(sendResourceUpdated)
+
+// NewOutputSubscriptions creates a subscription set delivering notifications through send
+func NewOutputSubscriptions(send func(sessionID, uri string)) *OutputSubscriptions {
+	return &OutputSubscriptions{
+		byProcess: make(map[string]map[string]*outputSubscription),
+		send:      send,
+	}
+}
+
+// sendResourceUpdated notifies one session that a resource changed
+func sendResourceUpdated(sessionID, uri string) {
+	if globalMCPServer == nil {
+		return
+	}
+	if err := globalMCPServer.SendNotificationToSpecificClient(sessionID, mcp.MethodNotificationResourceUpdated, map[string]any{
+		"uri": uri,
+	}); err != nil {
+		LogWarn("Process", "Failed to send resource update", fmt.Sprintf("URI: %s, session: %s, error: %v", uri, sessionID, err))
+	}
+}
+
+// Subscribe starts notifying sessionID when the process stream grows
+func (s *OutputSubscriptions) Subscribe(sessionID, processID, stream string) string {
+	uri := processResourceURI(processID, stream)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subs := s.byProcess[processID]
+	if subs == nil {
+		subs = make(map[string]*outputSubscription)
+		s.byProcess[processID] = subs
+	}
+	key := sessionID + "\x00" + uri
+	if _, exists := subs[key]; !exists {
+		subs[key] = &outputSubscription{sessionID: sessionID, uri: uri}
+	}
+	return uri
+}
+
+// Unsubscribe stops notifications for one stream, reporting whether a subscription existed
+func (s *OutputSubscriptions) Unsubscribe(sessionID, processID, stream string) bool {
+	key := sessionID + "\x00" + processResourceURI(processID, stream)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subs := s.byProcess[processID]
+	if _, exists := subs[key]; !exists {
+		return false
+	}
+	delete(subs, key)
+	if len(subs) == 0 {
+		delete(s.byProcess, processID)
+	}
+	return true
+}
+
+// RemoveProcess drops every subscription to a process (it exited or was removed)
+func (s *OutputSubscriptions) RemoveProcess(processID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.byProcess, processID)
+}
+
+// RemoveSession drops every subscription held by a disconnected session
+func (s *OutputSubscriptions) RemoveSession(sessionID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for processID, subs := range s.byProcess {
+		for key, sub := range subs {
+			if sub.sessionID == sessionID {
+				delete(subs, key)
+			}
+		}
+		if len(subs) == 0 {
+			delete(s.byProcess, processID)
+		}
+	}
+}
+
+// Count returns the number of active subscriptions to a process
+func (s *OutputSubscriptions) Count(processID string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.byProcess[processID])
+}
+
+// Notify signals that a process stream grew. Each subscription is notified at most
+// once per OutputNotifyInterval; growth inside the window yields one trailing notification.
+func (s *OutputSubscriptions) Notify(processID, stream string) {
+	uri := processResourceURI(processID, stream)
+
+	s.mu.Lock()
+	var due []*outputSubscription
+	now := time.Now()
+	for _, sub := range s.byProcess[processID] {
+		if sub.uri != uri || sub.scheduled {
+			continue
+		}
+		if wait := OutputNotifyInterval - now.Sub(sub.lastSent); wait > 0 {
+			sub.scheduled = true
+			time.AfterFunc(wait, func() { s.flush(processID, sub) })
+			continue
+		}
+		sub.lastSent = now
+		due = append(due, sub)
+	}
+	s.mu.Unlock()
+
+	for _, sub := range due {
+		s.send(sub.sessionID, sub.uri)
+	}
+}
+
+// flush sends a trailing notification unless the subscription went away in the meantime
+func (s *OutputSubscriptions) flush(processID string, sub *outputSubscription) {
+	s.mu.Lock()
+	sub.scheduled = false
+	current, exists := s.byProcess[processID][sub.sessionID+"\x00"+sub.uri]
+	if !exists || current != sub {
+		s.mu.Unlock()
+		return
+	}
+	sub.lastSent = time.Now()
+	s.mu.Unlock()
+
+	s.send(sub.sessionID, sub.uri)
+}
+
+// processOutputNotifier returns the callback used by the output readers of a stream
+func processOutputNotifier(processID, stream string) func() {
+	return func() {
+		outputSubscriptions.Notify(processID, stream)
+	}
+}
+
+// getSubscriptionArgs validates process_id and stream for the subscription tools
+func getSubscriptionArgs(ctx context.Context, request mcp.CallToolRequest) (string, string, string, error) {
+	session := server.ClientSessionFromContext(ctx)
+	if session == nil || session.SessionID() == "" {
+		return "", "", "", fmt.Errorf("subscriptions require a client session")
+	}
+
+	processID, err := request.RequireString("process_id")
+	if err != nil {
+		return "", "", "", fmt.Errorf("Missing or invalid 'process_id' argument")
+	}
+
+	stream := getStringArg(request, "stream", "stdout")
+	if stream != "stdout" && stream != "stderr" {
+		return "", "", "", fmt.Errorf("Invalid stream '%s' (must be stdout or stderr)", stream)
+	}
+
+	return session.SessionID(), processID, stream, nil
+}
+
+// handleSubscribeProcessOutput subscribes the calling session to output updates of a process
+func handleSubscribeProcessOutput(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	sessionID, processID, stream, err := getSubscriptionArgs(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	tracker, exists := registry.getProcess(processID)
+	if !exists {
+		return mcp.NewToolResultError(fmt.Sprintf("Process %s not found", processID)), nil
+	}
+
+	tracker.Mutex.RLock()
+	status := tracker.Status
+	combined := tracker.CombineOutput
+	tracker.Mutex.RUnlock()
+
+	if stream == "stderr" && combined {
+		return mcp.NewToolResultError(fmt.Sprintf("Process %s has combined output - subscribe to stdout instead", processID)), nil
+	}
+	if status != StatusRunning && status != StatusPending {
+		return mcp.NewToolResultError(fmt.Sprintf("Process %s has already finished (status: %s) - read %s directly", processID, status, processResourceURI(processID, stream))), nil
+	}
+
+	uri := outputSubscriptions.Subscribe(sessionID, processID, stream)
+
+	result := map[string]any{
+		"process_id": processID,
+		"uri":        uri,
+		"subscribed": true,
+	}
+
+	resultBytes, _ := json.Marshal(result)
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// handleUnsubscribeProcessOutput stops output updates for the calling session
+func handleUnsubscribeProcessOutput(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	sessionID, processID, stream, err := getSubscriptionArgs(ctx, request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	result := map[string]any{
+		"process_id":   processID,
+		"uri":          processResourceURI(processID, stream),
+		"unsubscribed": outputSubscriptions.Unsubscribe(sessionID, processID, stream),
+	}
+
+	resultBytes, _ := json.Marshal(result)
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// MaxReadyPatternLength caps the length of a ready_pattern
+const MaxReadyPatternLength = 512
+
+// readyProbe records when a process first printed a line matching its ready_pattern. It has
+// its own lock so output streaming never waits on readers holding the tracker mutex.
+type readyProbe struct {
+	pattern *regexp.Regexp
+	mu      sync.Mutex
+	readyAt *time.Time
+}
+
+// newReadyProbe compiles a ready_pattern
+func newReadyProbe(pattern string) (*readyProbe, error) {
+	if len(pattern) > MaxReadyPatternLength {
+		return nil, fmt.Errorf("ready_pattern cannot exceed %d bytes", MaxReadyPatternLength)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ready_pattern: %v", err)
+	}
+	return &readyProbe{pattern: re}, nil
+}
+
+// observe marks the process ready the first time line matches
+func (p *readyProbe) observe(line string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.readyAt == nil && p.pattern.MatchString(line) {
+		now := time.Now()
+		p.readyAt = &now
+	}
+}
+
+// ready returns when the process became ready, or nil if it has not yet
+func (p *readyProbe) ready() *time.Time {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.readyAt
+}
+
+// addProcessTimings adds the lifecycle timestamps and the durations between them to a
+// get_process_status result: ready_time and startup_duration once ready_pattern matched,
+// exit_time once the process finished, and run_duration so far (or in total once finished).
+// Must be called with tracker.Mutex held.
+func addProcessTimings(result map[string]any, tracker *ProcessTracker, now time.Time) {
+	if tracker.Ready != nil {
+		result["ready_pattern"] = tracker.ReadyPattern
+		if readyAt := tracker.Ready.ready(); readyAt != nil {
+			startup := readyAt.Sub(tracker.StartTime)
+			result["ready_time"] = readyAt.Format(time.RFC3339Nano)
+			result["startup_duration_ms"] = startup.Milliseconds()
+			result["startup_duration"] = startup.String()
+		}
+	}
+
+	end := now
+	if tracker.EndTime != nil {
+		end = *tracker.EndTime
+		result["exit_time"] = end.Format(time.RFC3339Nano)
+	}
+	if tracker.Status == StatusPending {
+		return // Not running yet
+	}
+	run := end.Sub(tracker.StartTime)
+	result["run_duration_ms"] = run.Milliseconds()
+	result["run_duration"] = run.String()
+}
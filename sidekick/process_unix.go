@@ -3,8 +3,16 @@
 package main
 
 import (
+	"bytes"
+	"fmt"
+	"os"
 	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
 	"syscall"
+
+	"golang.org/x/sys/unix"
 )
 
 // configureProcessGroup sets up the process to run in its own process group (Unix-specific)
@@ -14,6 +22,27 @@ func configureProcessGroup(cmd *exec.Cmd) {
 	}
 }
 
+// checkCredentialPrivilege verifies sidekick is allowed to switch to the given user (Unix-specific)
+func checkCredentialPrivilege(credential *ProcessCredential) error {
+	return checkCredentialPrivilegeAs(credential, os.Geteuid())
+}
+
+// applyProcessCredential makes the process run as the given user (Unix-specific)
+// Must be called after configureProcessGroup
+func applyProcessCredential(cmd *exec.Cmd, credential *ProcessCredential) error {
+	cmd.SysProcAttr.Credential = &syscall.Credential{
+		Uid:    credential.UID,
+		Gid:    credential.GID,
+		Groups: credential.Groups,
+	}
+	return nil
+}
+
+// setPTYWinsize sets the terminal size of a pty; the kernel delivers SIGWINCH to its foreground process group (Unix-specific)
+func setPTYWinsize(pty *os.File, cols, rows uint16) error {
+	return unix.IoctlSetWinsize(int(pty.Fd()), unix.TIOCSWINSZ, &unix.Winsize{Col: cols, Row: rows})
+}
+
 // killProcessGroup kills the entire process group (Unix-specific)
 func killProcessGroup(pid int, signal syscall.Signal) error {
 	// Kill the entire process group by sending signal to -pid
@@ -29,3 +58,101 @@ func terminateProcessGroup(pid int) error {
 func forceKillProcessGroup(pid int) error {
 	return killProcessGroup(pid, syscall.SIGKILL)
 }
+
+// classifyExit derives the exit reason and terminating signal from a finished process (Unix-specific)
+func classifyExit(state *os.ProcessState) (string, string) {
+	if status, ok := state.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		signal := status.Signal()
+		if signal == syscall.SIGKILL && runtime.GOOS == "linux" {
+			return ExitReasonOOMSuspected, unix.SignalName(signal)
+		}
+		return ExitReasonSignaled, unix.SignalName(signal)
+	}
+	if state.ExitCode() == 0 {
+		return ExitReasonExited, ""
+	}
+	return ExitReasonExitedNonzero, ""
+}
+
+// processAlive reports whether pid exists and has not exited (Unix-specific).
+// Zombies count as exited: they are gone, just not yet reaped by their parent.
+func processAlive(pid int) bool {
+	if err := syscall.Kill(pid, 0); err != nil && err != syscall.EPERM {
+		return false
+	}
+	if stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid)); err == nil {
+		// "pid (comm) state ..." - comm may itself contain spaces and parentheses
+		if i := bytes.LastIndexByte(stat, ')'); i >= 0 && i+2 < len(stat) && stat[i+2] == 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// processCommandLine returns the argv of any process (Unix-specific); only Linux exposes it, via /proc
+func processCommandLine(pid int) ([]string, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
+	if err != nil {
+		return nil, err
+	}
+	args := strings.Split(strings.TrimRight(string(data), "\x00"), "\x00")
+	if args[0] == "" {
+		return nil, fmt.Errorf("process %d has no command line", pid) // Kernel threads
+	}
+	return args, nil
+}
+
+// drainSignal returns the signal that puts the HTTP server into drain mode (Unix-specific)
+func drainSignal() os.Signal {
+	return syscall.SIGUSR1
+}
+
+// listZombies returns the defunct processes on the system keyed by process group (Unix-specific).
+// Only Linux exposes process states, via /proc.
+func listZombies() (map[int][]ZombieProcess, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, fmt.Errorf("zombie detection needs /proc: %v", err)
+	}
+
+	zombies := make(map[int][]ZombieProcess)
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+		if err != nil {
+			continue // Exited meanwhile
+		}
+		// "pid (comm) state ppid pgrp ..." - comm may itself contain spaces and parentheses
+		open, end := bytes.IndexByte(stat, '('), bytes.LastIndexByte(stat, ')')
+		if open < 0 || end < open {
+			continue
+		}
+		fields := strings.Fields(string(stat[end+1:]))
+		if len(fields) < 3 || fields[0] != "Z" {
+			continue
+		}
+		ppid, _ := strconv.Atoi(fields[1])
+		pgrp, _ := strconv.Atoi(fields[2])
+		zombies[pgrp] = append(zombies[pgrp], ZombieProcess{PID: pid, PPID: ppid, Command: string(stat[open+1 : end])})
+	}
+	return zombies, nil
+}
+
+// reapZombie collects a zombie if sidekick is its parent, and otherwise sends SIGCHLD to its
+// parent as a hint to reap it (Unix-specific). Returns what was done.
+func reapZombie(zombie ZombieProcess) string {
+	if zombie.PPID == os.Getpid() {
+		var status unix.WaitStatus
+		if pid, err := unix.Wait4(zombie.PID, &status, unix.WNOHANG, nil); err == nil && pid == zombie.PID {
+			return ZombieReaped
+		}
+		return ""
+	}
+	if zombie.PPID > 1 && syscall.Kill(zombie.PPID, syscall.SIGCHLD) == nil {
+		return ZombieParentSignaled
+	}
+	return ""
+}
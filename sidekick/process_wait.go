@@ -0,0 +1,179 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// DefaultPatternWaitTimeout is how long wait_for_output_pattern waits when no timeout_ms is given
+const DefaultPatternWaitTimeout = 30000
+
+// patternMatch is the first output line matching a wait_for_output_pattern regex
+type patternMatch struct {
+	Stream string `json:"stream"`
+	Line   string `json:"line"`
+	Match  string `json:"match"`
+	Offset int64  `json:"offset"` // Absolute byte offset of the line in its stream, usable as a cursor
+}
+
+// outputPatternScanner searches a growing ring buffer line by line, resuming where it left off
+type outputPatternScanner struct {
+	stream string
+	buffer *RingBuffer
+	pos    int64 // Absolute offset of the next line to scan
+}
+
+// scan checks the complete lines written since the last scan. With final set, a trailing
+// line without a newline is checked too (the stream has ended).
+func (s *outputPatternScanner) scan(re *regexp.Regexp, final bool) *patternMatch {
+	content, start := s.buffer.GetContentAndOffsetFromCursor(s.pos)
+	if content == "" {
+		return nil
+	}
+
+	offset := start
+	for len(content) > 0 {
+		end := strings.IndexByte(content, '\n')
+		if end < 0 {
+			if !final {
+				break // Incomplete line - rescan once it is finished
+			}
+			end = len(content)
+		}
+
+		line := content[:end]
+		if loc := re.FindStringIndex(line); loc != nil {
+			return &patternMatch{Stream: s.stream, Line: line, Match: line[loc[0]:loc[1]], Offset: offset}
+		}
+
+		advance := min(end+1, len(content))
+		offset += int64(advance)
+		content = content[advance:]
+	}
+
+	s.pos = offset
+	return nil
+}
+
+// handleWaitForOutputPattern blocks until a line of process output matches a regex,
+// the process exits, or the timeout expires
+func handleWaitForOutputPattern(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	processID, err := request.RequireString("process_id")
+	if err != nil {
+		return mcp.NewToolResultError("Missing or invalid 'process_id' argument"), nil
+	}
+
+	pattern, err := request.RequireString("pattern")
+	if err != nil || pattern == "" {
+		return mcp.NewToolResultError("Missing or invalid 'pattern' argument"), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid pattern: %v", err)), nil
+	}
+
+	timeoutMs := getInt64Arg(request, "timeout_ms", DefaultPatternWaitTimeout)
+	if timeoutMs > MaxOutputDelay {
+		return mcp.NewToolResultError(fmt.Sprintf("timeout_ms cannot exceed %d milliseconds (%s)", MaxOutputDelay, msDuration(MaxOutputDelay))), nil
+	}
+	if timeoutMs <= 0 {
+		return mcp.NewToolResultError("timeout_ms must be positive"), nil
+	}
+
+	streams := getStringArg(request, "streams", "both")
+	if streams != "stdout" && streams != "stderr" && streams != "both" {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid streams '%s' (must be stdout, stderr, or both)", streams)), nil
+	}
+
+	search := getStringArg(request, "search", "new")
+	if search != "new" && search != "full" {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid search '%s' (must be new or full)", search)), nil
+	}
+
+	tracker, exists := registry.getProcess(processID)
+	if !exists {
+		return mcp.NewToolResultError(fmt.Sprintf("Process %s not found", processID)), nil
+	}
+
+	// "new" starts at the read cursors of get_partial_process_output, which are left untouched
+	tracker.Mutex.RLock()
+	var scanners []*outputPatternScanner
+	if streams != "stderr" {
+		scanner := &outputPatternScanner{stream: "stdout", buffer: tracker.StdoutBuffer}
+		if search == "new" {
+			scanner.pos = tracker.StdoutCursor
+		}
+		scanners = append(scanners, scanner)
+	}
+	if streams != "stdout" && tracker.StderrBuffer != nil {
+		scanner := &outputPatternScanner{stream: "stderr", buffer: tracker.StderrBuffer}
+		if search == "new" {
+			scanner.pos = tracker.StderrCursor
+		}
+		scanners = append(scanners, scanner)
+	}
+	tracker.Mutex.RUnlock()
+
+	if len(scanners) == 0 {
+		return mcp.NewToolResultError(fmt.Sprintf("Process %s has combined output - wait on stdout instead", processID)), nil
+	}
+
+	start := time.Now()
+	deadline := time.NewTimer(time.Duration(timeoutMs) * time.Millisecond)
+	defer deadline.Stop()
+	ticker := time.NewTicker(time.Duration(DelayCheckInterval) * time.Millisecond)
+	defer ticker.Stop()
+
+	result := func(match *patternMatch, reason string) *mcp.CallToolResult {
+		tracker.Mutex.RLock()
+		response := map[string]any{
+			"process_id": processID,
+			"matched":    match != nil,
+			"status":     tracker.Status,
+			"elapsed_ms": time.Since(start).Milliseconds(),
+		}
+		if tracker.ExitCode != nil {
+			response["exit_code"] = *tracker.ExitCode
+		}
+		tracker.Mutex.RUnlock()
+
+		if match != nil {
+			response["match"] = match
+		} else {
+			response["reason"] = reason
+		}
+
+		resultBytes, _ := json.Marshal(response)
+		return mcp.NewToolResultText(string(resultBytes))
+	}
+
+	for {
+		// Read the status first: a finished process has flushed all of its output
+		tracker.Mutex.RLock()
+		finished := isTerminalStatus(tracker.Status)
+		tracker.Mutex.RUnlock()
+
+		for _, scanner := range scanners {
+			if match := scanner.scan(re, finished); match != nil {
+				return result(match, ""), nil
+			}
+		}
+		if finished {
+			return result(nil, "process_exited"), nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-deadline.C:
+			return result(nil, "timeout"), nil
+		case <-ctx.Done():
+			return mcp.NewToolResultError("request canceled"), nil
+		}
+	}
+}
@@ -4,8 +4,11 @@ package main
 
 import (
 	"fmt"
+	"os"
 	"os/exec"
 	"syscall"
+
+	"golang.org/x/sys/windows"
 )
 
 // configureProcessGroup sets up the process (Windows-specific)
@@ -18,6 +21,21 @@ func configureProcessGroup(cmd *exec.Cmd) {
 	}
 }
 
+// checkCredentialPrivilege rejects user switching, which is not supported on Windows
+func checkCredentialPrivilege(credential *ProcessCredential) error {
+	return fmt.Errorf("run_as_user/run_as_uid is not supported on Windows")
+}
+
+// applyProcessCredential is not supported on Windows
+func applyProcessCredential(cmd *exec.Cmd, credential *ProcessCredential) error {
+	return fmt.Errorf("run_as_user/run_as_uid is not supported on Windows")
+}
+
+// setPTYWinsize is not supported on Windows
+func setPTYWinsize(pty *os.File, cols, rows uint16) error {
+	return fmt.Errorf("pty window size is not supported on Windows")
+}
+
 // terminateProcessGroup sends termination signal to a process (Windows-specific)
 func terminateProcessGroup(pid int) error {
 	// On Windows, we don't have SIGTERM equivalent
@@ -32,3 +50,46 @@ func forceKillProcessGroup(pid int) error {
 	// The caller should use process.Kill() instead
 	return fmt.Errorf("windows force kill requires process.Kill()")
 }
+
+// classifyExit derives the exit reason from a finished process; Windows has no signals
+func classifyExit(state *os.ProcessState) (string, string) {
+	if state.ExitCode() == 0 {
+		return ExitReasonExited, ""
+	}
+	return ExitReasonExitedNonzero, ""
+}
+
+// processAlive reports whether pid exists and has not exited (Windows-specific)
+func processAlive(pid int) bool {
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return err == windows.ERROR_ACCESS_DENIED // Exists, but belongs to someone else
+	}
+	defer windows.CloseHandle(handle)
+
+	var exitCode uint32
+	if err := windows.GetExitCodeProcess(handle, &exitCode); err != nil {
+		return false
+	}
+	return exitCode == 259 // STILL_ACTIVE
+}
+
+// processCommandLine is not supported on Windows
+func processCommandLine(pid int) ([]string, error) {
+	return nil, fmt.Errorf("reading another process's command line is not supported on Windows")
+}
+
+// drainSignal returns nil: Windows has no SIGUSR1, use POST /drain instead
+func drainSignal() os.Signal {
+	return nil
+}
+
+// listZombies is not supported on Windows, which has no defunct process state
+func listZombies() (map[int][]ZombieProcess, error) {
+	return nil, fmt.Errorf("zombie detection is not supported on Windows")
+}
+
+// reapZombie is not supported on Windows
+func reapZombie(zombie ZombieProcess) string {
+	return ""
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ZombieCheckInterval is how often running processes are checked for defunct children
+const ZombieCheckInterval = 30 * time.Second
+
+// What reapZombie did with a zombie
+const (
+	ZombieReaped         = "reaped"          // Sidekick was the parent and collected it
+	ZombieParentSignaled = "parent_signaled" // The parent was sent SIGCHLD as a hint to reap it
+)
+
+// ZombieProcess is a defunct process: it exited, but its parent has not collected its exit status
+type ZombieProcess struct {
+	PID     int    `json:"pid"`
+	PPID    int    `json:"ppid"`
+	Command string `json:"command"`
+	Action  string `json:"action,omitempty"` // Set by reap_zombies
+}
+
+// zombieWarned records the processes whose zombies were already logged, so each warns once
+var zombieWarned sync.Map
+
+// groupZombies returns the zombies in a tracked process's process group. The process itself
+// is left out: sidekick reaps it when it exits.
+func groupZombies(zombies map[int][]ZombieProcess, tracker *ProcessTracker) []ZombieProcess {
+	if tracker.PID <= 0 {
+		return nil
+	}
+	var group []ZombieProcess
+	for _, zombie := range zombies[tracker.PID] {
+		if zombie.PID != tracker.PID {
+			group = append(group, zombie)
+		}
+	}
+	return group
+}
+
+// warnZombies logs a process's zombies the first time they are seen
+func warnZombies(tracker *ProcessTracker, zombies []ZombieProcess) {
+	if len(zombies) == 0 {
+		return
+	}
+	if _, warned := zombieWarned.LoadOrStore(tracker.ID, true); warned {
+		return
+	}
+	LogWarn("Process", fmt.Sprintf("Process group contains %d defunct (zombie) processes: %s", len(zombies), tracker.Command),
+		fmt.Sprintf("ID: %s, PID: %d, first zombie: %d (%s), parent %d - use reap_zombies", tracker.ID, tracker.PID, zombies[0].PID, zombies[0].Command, zombies[0].PPID))
+}
+
+// runningTrackers returns a snapshot of the running processes
+func runningTrackers() []*ProcessTracker {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	var running []*ProcessTracker
+	for _, tracker := range registry.processes {
+		tracker.Mutex.RLock()
+		if tracker.Status == StatusRunning {
+			running = append(running, tracker)
+		}
+		tracker.Mutex.RUnlock()
+	}
+	return running
+}
+
+// watchZombies periodically warns about running processes whose group holds zombies
+func watchZombies() {
+	ticker := time.NewTicker(ZombieCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			zombies, err := listZombies()
+			if err != nil {
+				return // Not supported here
+			}
+			for _, tracker := range runningTrackers() {
+				tracker.Mutex.RLock()
+				group := groupZombies(zombies, tracker)
+				tracker.Mutex.RUnlock()
+				warnZombies(tracker, group)
+			}
+		case <-shutdownChan:
+			return
+		}
+	}
+}
+
+// handleReapZombies reaps what it can of the zombies in tracked process groups: zombies whose
+// parent is sidekick are collected, other parents are sent SIGCHLD
+func handleReapZombies(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	processID := getStringArg(request, "process_id", "")
+
+	var trackers []*ProcessTracker
+	if processID != "" {
+		tracker, exists := registry.getProcess(processID)
+		if !exists {
+			return mcp.NewToolResultError(fmt.Sprintf("Process %s not found", processID)), nil
+		}
+		trackers = append(trackers, tracker)
+	} else {
+		trackers = runningTrackers()
+	}
+
+	zombies, err := listZombies()
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	found, reaped, signaled := 0, 0, 0
+	byProcess := map[string][]ZombieProcess{}
+	for _, tracker := range trackers {
+		tracker.Mutex.RLock()
+		group := groupZombies(zombies, tracker)
+		tracker.Mutex.RUnlock()
+		if len(group) == 0 {
+			continue
+		}
+
+		warnZombies(tracker, group)
+		for i := range group {
+			group[i].Action = reapZombie(group[i])
+			switch group[i].Action {
+			case ZombieReaped:
+				reaped++
+			case ZombieParentSignaled:
+				signaled++
+			}
+		}
+		found += len(group)
+		byProcess[tracker.ID] = group
+	}
+
+	if found > 0 {
+		LogInfo("Process", "Reaped zombie processes", fmt.Sprintf("Found: %d, reaped: %d, parents signaled: %d", found, reaped, signaled))
+	}
+
+	result := map[string]any{
+		"zombies_found":    found,
+		"reaped":           reaped,
+		"parents_signaled": signaled,
+		"processes":        byProcess,
+	}
+	resultBytes, _ := json.Marshal(result)
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
@@ -2,15 +2,24 @@ package main
 
 import (
 	"bufio"
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
+	"syscall"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"github.com/mark3labs/mcp-go/mcp"
@@ -26,45 +35,101 @@ const (
 	StatusKilled    ProcessStatus = "killed"
 )
 
+// Exit reasons recorded on the tracker when a process ends
+const (
+	ExitReasonExited        = "exited"         // Exited with code 0
+	ExitReasonExitedNonzero = "exited_nonzero" // Exited with a non-zero code
+	ExitReasonSignaled      = "signaled"       // Terminated by a signal sidekick did not send
+	ExitReasonOOMSuspected  = "oom_suspected"  // SIGKILL from outside sidekick on Linux, usually the OOM killer
+	ExitReasonKilled        = "killed"         // Killed through sidekick (kill_process, TUI, session cleanup)
+	ExitReasonStartFailed   = "start_failed"   // Never started (see last_error)
+	ExitReasonAdoptedExited = "adopted_exited" // An adopted process went away; its exit code is unknown
+)
+
 type ProcessTracker struct {
-	ID            string         `json:"id"`
-	Name          string         `json:"name,omitempty"`
-	SessionID     string         `json:"session_id,omitempty"` // SSE session that owns this process
-	PID           int            `json:"pid"`
-	Command       string         `json:"command"`
-	Args          []string       `json:"args"`
-	WorkingDir    string         `json:"working_dir"`
-	BufferSize    int64          `json:"buffer_size"`
-	CombineOutput bool           `json:"combine_output"`
-	DelayStart    time.Duration  `json:"delay_start"`
-	SyncDelay     bool           `json:"sync_delay"`
-	StartTime     time.Time      `json:"start_time"`
-	EndTime       *time.Time     `json:"end_time,omitempty"` // ⏰ When process finished
-	Duration      *time.Duration `json:"duration,omitempty"` // ⏱️ Total execution time
-	LastAccessed  time.Time      `json:"last_accessed"`
-	Status        ProcessStatus  `json:"status"`
-	StdoutCursor  int64          `json:"stdout_cursor"`
-	StderrCursor  int64          `json:"stderr_cursor"`
-	StdoutBuffer  *RingBuffer    `json:"-"`
-	StderrBuffer  *RingBuffer    `json:"-"`
-	Process       *exec.Cmd      `json:"-"`
-	StdinWriter   io.WriteCloser `json:"-"`
-	ExitCode      *int           `json:"exit_code,omitempty"`
-	CancelFunc    context.CancelFunc `json:"-"` // Cancel pending delayed spawns during shutdown
-	Mutex         sync.RWMutex   `json:"-"`
+	ID               string             `json:"id"`
+	Name             string             `json:"name,omitempty"`
+	SessionID        string             `json:"session_id,omitempty"` // SSE session that owns this process
+	PID              int                `json:"pid"`
+	Command          string             `json:"command"`
+	Args             []string           `json:"args"`
+	WorkingDir       string             `json:"working_dir"`
+	BufferSize       int64              `json:"buffer_size"`
+	CombineOutput    bool               `json:"combine_output"`
+	DelayStart       time.Duration      `json:"delay_start"`
+	SyncDelay        bool               `json:"sync_delay"`
+	StartTime        time.Time          `json:"start_time"`
+	EndTime          *time.Time         `json:"end_time,omitempty"` // ⏰ When process finished
+	Duration         *time.Duration     `json:"duration,omitempty"` // ⏱️ Total execution time
+	LastAccessed     time.Time          `json:"last_accessed"`
+	Status           ProcessStatus      `json:"status"`
+	StdoutCursor     int64              `json:"stdout_cursor"`
+	StderrCursor     int64              `json:"stderr_cursor"`
+	StdoutBuffer     *RingBuffer        `json:"-"`
+	StderrBuffer     *RingBuffer        `json:"-"`
+	Process          *exec.Cmd          `json:"-"`
+	StdinWriter      io.WriteCloser     `json:"-"`
+	ExitCode         *int               `json:"exit_code,omitempty"`
+	GitBranch        string             `json:"git_branch,omitempty"` // Captured at spawn when capture_git is set
+	GitCommit        string             `json:"git_commit,omitempty"`
+	Credential       *ProcessCredential `json:"-"`                            // User to run as (run_as_user / run_as_uid)
+	LastError        string             `json:"last_error,omitempty"`         // Why the process failed to start
+	Labels           map[string]string  `json:"labels,omitempty"`             // Arbitrary key/value tags for filtering
+	PTY              *os.File           `json:"-"`                            // Controlling terminal for pty-backed processes (nil otherwise)
+	Cols             uint16             `json:"cols,omitempty"`               // Requested terminal width
+	Rows             uint16             `json:"rows,omitempty"`               // Requested terminal height
+	IdempotencyKey   string             `json:"idempotency_key,omitempty"`    // Client-supplied key that deduplicates spawns
+	DedupConsecutive bool               `json:"dedup_consecutive,omitempty"`  // Collapse runs of identical output lines
+	MaxLineBytes     int                `json:"max_line_bytes,omitempty"`     // Longer output lines are truncated with LineTruncatedMarker
+	WaitOnMainOnly   bool               `json:"wait_on_main_only,omitempty"`  // Finish when the main process exits, even if children hold the output pipes
+	OnExitCommand    []string           `json:"on_exit_command,omitempty"`    // Hook run (argv) after the process finishes
+	LinePrefix       string             `json:"line_prefix,omitempty"`        // Template prepended to each stored output line (see expandLinePrefix)
+	OnExitResult     *ExitHookResult    `json:"on_exit_result,omitempty"`     // Outcome of OnExitCommand once it ran
+	ExitReason       string             `json:"exit_reason,omitempty"`        // Why the process ended (see ExitReason* constants)
+	Signal           string             `json:"signal,omitempty"`             // Terminating signal name, e.g. SIGSEGV (Unix only)
+	Adopted          bool               `json:"adopted,omitempty"`            // Started outside sidekick and adopted by PID (see adopt_process)
+	ProgressRegex    string             `json:"progress_regex,omitempty"`     // Pattern whose latest match in the output gives the progress
+	Progress         *progressMatcher   `json:"-"`                            // Compiled ProgressRegex, set at spawn and never replaced
+	RedactPatterns   []string           `json:"redact_patterns,omitempty"`    // Regexes whose matches are stored as RedactionMarker
+	RedactBuiltin    bool               `json:"redact_builtin,omitempty"`     // Also redact builtinRedactPatterns
+	Redactor         *outputRedactor    `json:"-"`                            // Compiled redaction patterns, nil when nothing is redacted
+	TeeStderrToLogs  bool               `json:"tee_stderr_to_logs,omitempty"` // Mirror stderr lines into the logs as source "proc:<name>"
+	FlushPartial     bool               `json:"flush_partial,omitempty"`      // Commit unterminated lines (prompts) after PartialFlushDelay
+	ReadyPattern     string             `json:"ready_pattern,omitempty"`      // Output line pattern that marks the process ready (ready_time)
+	Ready            *readyProbe        `json:"-"`                            // Compiled ReadyPattern, set at spawn and never replaced
+	Env              map[string]string  `json:"-"`                            // Variables the spawn set (env, env_file); get_process_status include_env shows them masked
+	CancelFunc       context.CancelFunc `json:"-"`                            // Cancel pending delayed spawns during shutdown
+	Mutex            sync.RWMutex       `json:"-"`
 }
 
 type OutputResponse struct {
-	ProcessID    string         `json:"process_id"`
-	Stdout       string         `json:"stdout,omitempty"`
-	Stderr       string         `json:"stderr,omitempty"`
-	StdoutCursor int64          `json:"stdout_cursor"`
-	StderrCursor int64          `json:"stderr_cursor"`
-	Status       ProcessStatus  `json:"status"`
-	ExitCode     *int           `json:"exit_code,omitempty"`
-	StartTime    *time.Time     `json:"start_time,omitempty"` // ⏰ When process started
-	EndTime      *time.Time     `json:"end_time,omitempty"`   // ⏰ When process finished
-	Duration     *time.Duration `json:"duration,omitempty"`   // ⏱️ Total execution time
+	ProcessID    string                  `json:"process_id"`
+	Stdout       string                  `json:"stdout,omitempty"`
+	Stderr       string                  `json:"stderr,omitempty"`
+	StdoutCursor int64                   `json:"stdout_cursor"`
+	StderrCursor int64                   `json:"stderr_cursor"`
+	Status       ProcessStatus           `json:"status"`
+	ExitCode     *int                    `json:"exit_code,omitempty"`
+	StartTime    *time.Time              `json:"start_time,omitempty"`    // ⏰ When process started
+	EndTime      *time.Time              `json:"end_time,omitempty"`      // ⏰ When process finished
+	Duration     *time.Duration          `json:"duration,omitempty"`      // ⏱️ Total execution time
+	Preset       string                  `json:"preset,omitempty"`        // Named filter preset that was applied
+	Combined     bool                    `json:"combined,omitempty"`      // Streams merged at read time (combine=true), all in stdout
+	StdoutLines  []string                `json:"stdout_lines,omitempty"`  // Set instead of stdout when format=lines
+	TailOmitted  map[string]TailOmission `json:"tail_omitted,omitempty"`  // Per stream, what tail_bytes left out
+	StderrLines  []string                `json:"stderr_lines,omitempty"`  // Set instead of stderr when format=lines
+	Removed      bool                    `json:"removed,omitempty"`       // Read from the recently removed cache (get_full_process_output)
+	NoNewOutput  bool                    `json:"no_new_output,omitempty"` // skip_if_no_new: nothing new past the cursors, payload omitted
+	PartialLine  bool                    `json:"partial_line,omitempty"`  // flush_partial: the output ends in a line still waiting for its newline
+
+	// Set when the buffers have dropped their oldest output, so earlier output can no longer be read
+	OutputTruncated bool  `json:"output_truncated,omitempty"`
+	DiscardedBytes  int64 `json:"discarded_bytes,omitempty"`
+
+	// Set when compress=gzip: stdout/stderr are base64(gzip(content))
+	Compressed      bool `json:"compressed,omitempty"`
+	OriginalBytes   int  `json:"original_bytes,omitempty"`
+	CompressedBytes int  `json:"compressed_bytes,omitempty"`
 }
 
 type ProcessRegistry struct {
@@ -73,12 +138,67 @@ type ProcessRegistry struct {
 }
 
 const (
-	DefaultBufferSize  = 10 * 1024 * 1024 // 10MB default buffer size
-	MaxOutputDelay     = 120000           // 2 minutes max delay for output tools
-	MaxSpawnDelay      = 300000           // 5 minutes max delay for spawn_process
-	DelayCheckInterval = 100              // Check process status every 100ms during delay
+	DefaultBufferSize          = 10 * 1024 * 1024   // 10MB default buffer size
+	MaxBufferSize              = 100 * 1024 * 1024  // 100MB max buffer size for resize_process_buffer
+	DelayCheckInterval         = 100                // Check process status every 100ms during delay
+	DefaultRunWithStdinTimeout = 30000              // 30 seconds default wait for run_with_stdin
+	PipeFileChunkSize          = 64 * 1024          // 64KB chunks when streaming a file to stdin
+	MaxPipeFileSize            = 1024 * 1024 * 1024 // 1GB max file size for pipe_file_to_process
+	DefaultPipeFileTimeout     = 60000              // 1 minute default for pipe_file_to_process
+	MaxPipeFileTimeout         = 600000             // 10 minutes max for pipe_file_to_process
+	DefaultMaxLineBytes        = 1024 * 1024        // 1MB default cap for a single output line
+	MaxLineBytesLimit          = 64 * 1024 * 1024   // 64MB max for max_line_bytes
+)
+
+// defaultCombineOutput is the combine_output default for spawns that don't set it (--default-combine-output)
+var defaultCombineOutput = false
+
+// defaultOutputStreams is the streams default of get_partial_process_output and
+// get_full_process_output (--default-streams)
+var defaultOutputStreams = "both"
+
+// defaultCombineReads makes the output tools merge stdout and stderr chronologically when a
+// call doesn't set combine, and spawns record line timestamps unless they set timestamp_lines
+// (--default-combine-reads)
+var defaultCombineReads = false
+
+// combineArg reads combine. When the call doesn't set it, --default-combine-reads applies to
+// reads of both streams from processes with line timestamps; an explicit combine fails later
+// on a process without them.
+func combineArg(request mcp.CallToolRequest, streams string, tracker *ProcessTracker) bool {
+	if arguments, ok := request.Params.Arguments.(map[string]any); ok {
+		if _, set := arguments["combine"]; set {
+			return getBoolArg(request, "combine", false)
+		}
+	}
+	return defaultCombineReads && streams == "both" && tracker.StdoutBuffer.HasTimestamps()
+}
+
+// MainExitDrainGrace is how long wait_on_main_only processes keep reading output after the
+// main process exits before the pipes are closed
+const MainExitDrainGrace = 500 * time.Millisecond
+
+const (
+	DefaultKillConfirmTimeout = 5000  // kill_process waits up to 5 seconds for the process to exit
+	MaxKillConfirmTimeout     = 60000 // 1 minute max for kill_process timeout_ms
+	killConfirmPollInterval   = 20 * time.Millisecond
+)
+
+// LineTruncatedMarker is appended to output lines cut at max_line_bytes
+const LineTruncatedMarker = " [sidekick: line truncated]"
+
+// Delay caps in milliseconds, overridable with --max-spawn-delay and --max-output-delay.
+// A delay exactly at the cap is allowed.
+var (
+	MaxOutputDelay int64 = 120000 // 2 minutes max delay for output tools
+	MaxSpawnDelay  int64 = 300000 // 5 minutes max delay for spawn_process
 )
 
+// msDuration converts a millisecond count into a time.Duration for display
+func msDuration(ms int64) time.Duration {
+	return time.Duration(ms) * time.Millisecond
+}
+
 // Argument extraction helpers for MCP tool requests
 func getStringArg(request mcp.CallToolRequest, key, defaultVal string) string {
 	if arguments, ok := request.Params.Arguments.(map[string]any); ok {
@@ -217,7 +337,27 @@ type RingBuffer struct {
 	data       []byte
 	maxSize    int64
 	totalBytes int64
-	mutex      sync.RWMutex
+	lineMarks  []lineMark // Write times of buffered lines, only when timestamps are enabled
+	timestamps bool
+	midLine    bool // The last write did not end with a newline (flush_partial)
+	// Lines trimmed from the front, so line numbers stay stable (get_output_context)
+	discardedLines int64
+	trimmedMidLine bool // The trim cut a line in two; the buffer starts with its tail
+	mutex          sync.RWMutex
+}
+
+// lineMark records when the line starting at an absolute byte offset was written
+type lineMark struct {
+	offset int64
+	at     time.Time
+}
+
+// enableLineTimestamps turns on line timestamps for the process output buffers
+func (tracker *ProcessTracker) enableLineTimestamps() {
+	tracker.StdoutBuffer.EnableTimestamps()
+	if tracker.StderrBuffer != nil {
+		tracker.StderrBuffer.EnableTimestamps()
+	}
 }
 
 // captureProcessEndTime sets the end time and calculates duration for a finished process
@@ -240,13 +380,185 @@ func (rb *RingBuffer) Write(data []byte) {
 	rb.mutex.Lock()
 	defer rb.mutex.Unlock()
 
+	// A write that continues a partial line belongs to the line's existing mark
+	if rb.timestamps && !rb.midLine {
+		rb.lineMarks = append(rb.lineMarks, lineMark{offset: rb.totalBytes, at: time.Now()})
+	}
+
 	rb.data = append(rb.data, data...)
 	rb.totalBytes += int64(len(data))
+	if len(data) > 0 {
+		rb.midLine = data[len(data)-1] != '\n'
+	}
 
 	// Trim from beginning if we exceed max size
 	if int64(len(rb.data)) > rb.maxSize {
 		excess := int64(len(rb.data)) - rb.maxSize
+		rb.countTrimmedLocked(rb.data[:excess])
 		rb.data = rb.data[excess:]
+		rb.trimLineMarksLocked()
+	}
+}
+
+// HasTimestamps reports whether the buffer records line write times
+func (rb *RingBuffer) HasTimestamps() bool {
+	rb.mutex.RLock()
+	defer rb.mutex.RUnlock()
+	return rb.timestamps
+}
+
+// EnableTimestamps starts recording write times so output can be read by age
+func (rb *RingBuffer) EnableTimestamps() {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+	rb.timestamps = true
+}
+
+// trimLineMarksLocked drops marks for lines that are no longer buffered
+// Must be called with rb.mutex held
+func (rb *RingBuffer) trimLineMarksLocked() {
+	discardedBytes := rb.totalBytes - int64(len(rb.data))
+	firstKept := 0
+	for firstKept < len(rb.lineMarks) && rb.lineMarks[firstKept].offset < discardedBytes {
+		firstKept++
+	}
+	rb.lineMarks = rb.lineMarks[firstKept:]
+}
+
+// countTrimmedLocked records the lines in a prefix about to be trimmed
+// Must be called with rb.mutex held
+func (rb *RingBuffer) countTrimmedLocked(trimmed []byte) {
+	if len(trimmed) == 0 {
+		return
+	}
+	rb.discardedLines += int64(bytes.Count(trimmed, []byte{'\n'}))
+	rb.trimmedMidLine = trimmed[len(trimmed)-1] != '\n'
+}
+
+// GetContentSince returns buffered output written at or after since.
+// The second return is false when timestamps are not enabled for this buffer.
+func (rb *RingBuffer) GetContentSince(since time.Time) (string, bool) {
+	rb.mutex.RLock()
+	defer rb.mutex.RUnlock()
+
+	if !rb.timestamps {
+		return "", false
+	}
+
+	for _, mark := range rb.lineMarks {
+		if !mark.at.Before(since) {
+			discardedBytes := rb.totalBytes - int64(len(rb.data))
+			return string(rb.data[max(mark.offset-discardedBytes, 0):]), true
+		}
+	}
+	return "", true
+}
+
+// timedLine is a buffered output line with the time it was written
+type timedLine struct {
+	text string
+	at   time.Time
+}
+
+// GetTimedLinesFromCursor returns the buffered lines at or after cursor with their write times,
+// plus the cursor just past them. The last return is false when timestamps are not enabled.
+func (rb *RingBuffer) GetTimedLinesFromCursor(cursor int64) ([]timedLine, int64, bool) {
+	rb.mutex.RLock()
+	defer rb.mutex.RUnlock()
+
+	if !rb.timestamps {
+		return nil, rb.totalBytes, false
+	}
+
+	discardedBytes := rb.totalBytes - int64(len(rb.data))
+	var lines []timedLine
+	for i, mark := range rb.lineMarks {
+		if mark.offset < cursor {
+			continue
+		}
+		end := rb.totalBytes
+		if i+1 < len(rb.lineMarks) {
+			end = rb.lineMarks[i+1].offset
+		}
+		lines = append(lines, timedLine{
+			text: string(rb.data[mark.offset-discardedBytes : end-discardedBytes]),
+			at:   mark.at,
+		})
+	}
+	return lines, rb.totalBytes, true
+}
+
+// gapMarker is the line gap_marker_ms inserts where no output was written for a while
+func gapMarker(gap time.Duration) string {
+	return fmt.Sprintf("[sidekick] --- %s gap ---\n", gap.Round(100*time.Millisecond))
+}
+
+// mergeTimedLines interleaves two streams chronologically, keeping a's line first on ties.
+// With gapMarker > 0, a marker line goes between lines written further apart than that.
+func mergeTimedLines(a, b []timedLine, since time.Time, gapThreshold time.Duration) string {
+	var builder strings.Builder
+	var previous time.Time
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		var line timedLine
+		if j >= len(b) || (i < len(a) && !b[j].at.Before(a[i].at)) {
+			line = a[i]
+			i++
+		} else {
+			line = b[j]
+			j++
+		}
+		if line.at.Before(since) {
+			continue
+		}
+		if gapThreshold > 0 && !previous.IsZero() && line.at.Sub(previous) > gapThreshold {
+			builder.WriteString(gapMarker(line.at.Sub(previous)))
+		}
+		previous = line.at
+		builder.WriteString(line.text)
+	}
+	return builder.String()
+}
+
+// readCombinedOutput merges stdout and stderr written after the given cursors (and at or after since)
+// in the order the lines were written, marking pauses longer than gapThreshold (0 = none).
+// Returns false when the process has no line timestamps. Must be called with tracker.Mutex held.
+func readCombinedOutput(tracker *ProcessTracker, stdoutCursor, stderrCursor int64, since time.Time, gapThreshold time.Duration) (string, int64, int64, bool) {
+	stdout, stdoutEnd, ok := tracker.StdoutBuffer.GetTimedLinesFromCursor(stdoutCursor)
+	if !ok || tracker.StderrBuffer == nil {
+		return "", stdoutEnd, 0, false
+	}
+	stderr, stderrEnd, ok := tracker.StderrBuffer.GetTimedLinesFromCursor(stderrCursor)
+	if !ok {
+		return "", stdoutEnd, stderrEnd, false
+	}
+	return mergeTimedLines(stdout, stderr, since, gapThreshold), stdoutEnd, stderrEnd, true
+}
+
+// gapMarkerArg reads gap_marker_ms, which only applies to combined reads
+func gapMarkerArg(request mcp.CallToolRequest, combine bool) (time.Duration, *mcp.CallToolResult) {
+	gapMs := getInt64Arg(request, "gap_marker_ms", 0)
+	if gapMs < 0 {
+		return 0, mcp.NewToolResultError("gap_marker_ms cannot be negative")
+	}
+	if gapMs > 0 && !combine {
+		return 0, mcp.NewToolResultError("gap_marker_ms requires combine=true")
+	}
+	return time.Duration(gapMs) * time.Millisecond, nil
+}
+
+// Resize changes the maximum buffer size, trimming the oldest bytes immediately when shrinking
+func (rb *RingBuffer) Resize(maxSize int64) {
+	rb.mutex.Lock()
+	defer rb.mutex.Unlock()
+
+	rb.maxSize = maxSize
+	if int64(len(rb.data)) > rb.maxSize {
+		excess := int64(len(rb.data)) - rb.maxSize
+		rb.countTrimmedLocked(rb.data[:excess])
+		// Copy so the discarded prefix can be garbage collected
+		rb.data = append([]byte(nil), rb.data[excess:]...)
+		rb.trimLineMarksLocked()
 	}
 }
 
@@ -272,6 +584,21 @@ func (rb *RingBuffer) GetContentFromCursor(cursor int64) string {
 	return string(rb.data[effectivePos:])
 }
 
+// GetContentAndOffsetFromCursor is GetContentFromCursor plus the absolute offset where the
+// returned content starts (later than cursor if that part was already evicted)
+func (rb *RingBuffer) GetContentAndOffsetFromCursor(cursor int64) (string, int64) {
+	rb.mutex.RLock()
+	defer rb.mutex.RUnlock()
+
+	discardedBytes := rb.totalBytes - int64(len(rb.data))
+	start := max(cursor, discardedBytes)
+	if start >= rb.totalBytes {
+		return "", rb.totalBytes
+	}
+
+	return string(rb.data[start-discardedBytes:]), start
+}
+
 func (rb *RingBuffer) Len() int {
 	rb.mutex.RLock()
 	defer rb.mutex.RUnlock()
@@ -284,6 +611,53 @@ func (rb *RingBuffer) TotalBytes() int64 {
 	return rb.totalBytes
 }
 
+// LastLine returns the most recent non-empty line in the buffer, without its line ending
+func (rb *RingBuffer) LastLine() string {
+	rb.mutex.RLock()
+	defer rb.mutex.RUnlock()
+
+	end := len(rb.data)
+	for end > 0 {
+		start := bytes.LastIndexByte(rb.data[:end], '\n') + 1
+		if line := strings.TrimRight(string(rb.data[start:end]), "\r\n"); strings.TrimSpace(line) != "" {
+			return line
+		}
+		end = start - 1 // Step over the newline to the previous line
+	}
+	return ""
+}
+
+// EndsMidLine reports whether the buffer ends in a line the process has not finished yet
+func (rb *RingBuffer) EndsMidLine() bool {
+	rb.mutex.RLock()
+	defer rb.mutex.RUnlock()
+	return rb.midLine
+}
+
+// DiscardedBytes returns how many of the oldest bytes were dropped to stay within the max size
+func (rb *RingBuffer) DiscardedBytes() int64 {
+	rb.mutex.RLock()
+	defer rb.mutex.RUnlock()
+	return rb.totalBytes - int64(len(rb.data))
+}
+
+// discardedOutputBytes returns how much output the process has lost to buffer overflow, across both streams
+func (tracker *ProcessTracker) discardedOutputBytes() int64 {
+	discarded := tracker.StdoutBuffer.DiscardedBytes()
+	if tracker.StderrBuffer != nil {
+		discarded += tracker.StderrBuffer.DiscardedBytes()
+	}
+	return discarded
+}
+
+// endsInPartialLine reports whether either output buffer ends in a committed partial line (flush_partial)
+func (tracker *ProcessTracker) endsInPartialLine() bool {
+	if tracker.StdoutBuffer.EndsMidLine() {
+		return true
+	}
+	return tracker.StderrBuffer != nil && tracker.StderrBuffer.EndsMidLine()
+}
+
 // Whitelist of allowed filter commands for security
 var allowedCommands = map[string]bool{
 	// Text Search & Pattern Matching
@@ -347,6 +721,12 @@ func filterOutput(input string, commands [][]string) (string, error) {
 		}
 	}
 
+	// Only --max-filter-concurrency pipelines run at once
+	if err := filterSlots.acquire(FilterQueueWait); err != nil {
+		return input, err
+	}
+	defer filterSlots.release()
+
 	ctx, cancel := context.WithTimeout(context.Background(), filterTimeout)
 	defer cancel()
 
@@ -390,6 +770,54 @@ func filterOutput(input string, commands [][]string) (string, error) {
 	return currentInput, nil
 }
 
+// FilterCommandInfo reports whether an allowed filter command is installed
+type FilterCommandInfo struct {
+	Command   string `json:"command"`
+	Available bool   `json:"available"`
+	Path      string `json:"path,omitempty"`
+}
+
+// listFilterCommands returns every allowed filter command, sorted, with its PATH lookup result
+func listFilterCommands() []FilterCommandInfo {
+	commands := make([]string, 0, len(allowedCommands))
+	for command := range allowedCommands {
+		commands = append(commands, command)
+	}
+	slices.Sort(commands)
+
+	infos := make([]FilterCommandInfo, 0, len(commands))
+	for _, command := range commands {
+		info := FilterCommandInfo{Command: command}
+		if path, err := exec.LookPath(command); err == nil {
+			info.Available = true
+			info.Path = path
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// handleListFilterCommands lists the filter commands usable in output filters
+func handleListFilterCommands(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	commands := listFilterCommands()
+
+	available := 0
+	for _, info := range commands {
+		if info.Available {
+			available++
+		}
+	}
+
+	result := map[string]any{
+		"commands":  commands,
+		"total":     len(commands),
+		"available": available,
+	}
+
+	resultBytes, _ := json.Marshal(result)
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
 var (
 	registry = &ProcessRegistry{
 		processes: make(map[string]*ProcessTracker),
@@ -447,12 +875,57 @@ func cleanupStaleProcesses() {
 	for _, id := range staleProcesses {
 		registry.removeProcess(id)
 	}
+
+	removedProcesses.prune()
+}
+
+// isTerminalStatus reports whether a process has finished and will not change status again
+func isTerminalStatus(status ProcessStatus) bool {
+	return status == StatusCompleted || status == StatusFailed || status == StatusKilled
+}
+
+// reapProcesses removes terminated processes from the registry.
+// An empty statuses list matches every terminal status; olderThan filters on time since the process ended.
+func (r *ProcessRegistry) reapProcesses(statuses []ProcessStatus, olderThan time.Duration) []string {
+	now := time.Now()
+	var reapable []string
+
+	r.mutex.RLock()
+	for id, tracker := range r.processes {
+		tracker.Mutex.RLock()
+		status := tracker.Status
+		endedAt := tracker.StartTime
+		if tracker.EndTime != nil {
+			endedAt = *tracker.EndTime
+		}
+		tracker.Mutex.RUnlock()
+
+		if !isTerminalStatus(status) {
+			continue
+		}
+		if len(statuses) > 0 && !slices.Contains(statuses, status) {
+			continue
+		}
+		if olderThan > 0 && now.Sub(endedAt) < olderThan {
+			continue
+		}
+		reapable = append(reapable, id)
+	}
+	r.mutex.RUnlock()
+
+	for _, id := range reapable {
+		r.removeProcess(id)
+	}
+	return reapable
 }
 
 func (r *ProcessRegistry) addProcess(tracker *ProcessTracker) {
 	r.mutex.Lock()
-	defer r.mutex.Unlock()
 	r.processes[tracker.ID] = tracker
+	r.mutex.Unlock()
+
+	// Expose output streams as MCP resources (outside the registry lock)
+	registerProcessResources(tracker)
 }
 
 func (r *ProcessRegistry) getProcess(id string) (*ProcessTracker, bool) {
@@ -467,6 +940,14 @@ func (r *ProcessRegistry) getProcess(id string) (*ProcessTracker, bool) {
 	return tracker, exists
 }
 
+// peekProcess looks up a process without touching its LastAccessed time
+func (r *ProcessRegistry) peekProcess(id string) (*ProcessTracker, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	tracker, exists := r.processes[id]
+	return tracker, exists
+}
+
 func (r *ProcessRegistry) getAllProcesses() []*ProcessTracker {
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
@@ -486,8 +967,39 @@ func (r *ProcessRegistry) getAllProcesses() []*ProcessTracker {
 
 func (r *ProcessRegistry) removeProcess(id string) {
 	r.mutex.Lock()
-	defer r.mutex.Unlock()
+	tracker, exists := r.processes[id]
 	delete(r.processes, id)
+	r.mutex.Unlock()
+
+	// Terminated processes stay readable by get_full_process_output for a little while
+	if exists {
+		tracker.Mutex.RLock()
+		terminated := isTerminalStatus(tracker.Status)
+		tracker.Mutex.RUnlock()
+		if terminated {
+			removedProcesses.add(tracker)
+		}
+	}
+
+	unregisterProcessResources(id)
+	zombieWarned.Delete(id)
+}
+
+// terminateTracker sends SIGTERM to a process's group, falling back to Kill. Adopted processes
+// are signalled alone: their group isn't sidekick's to kill. It returns the signal delivered
+// ("SIGTERM" or "kill") and the Kill error when both failed. Adopted and Process don't change
+// once a process runs, so tracker.Mutex need not be held.
+func terminateTracker(tracker *ProcessTracker) (string, error) {
+	var err error
+	if tracker.Adopted {
+		err = tracker.Process.Process.Signal(syscall.SIGTERM)
+	} else {
+		err = terminateProcessGroup(tracker.Process.Process.Pid)
+	}
+	if err == nil {
+		return "SIGTERM", nil
+	}
+	return "kill", tracker.Process.Process.Kill()
 }
 
 // killProcessesBySession kills all processes associated with a session
@@ -505,14 +1017,8 @@ func (r *ProcessRegistry) killProcessesBySession(sessionID string) int {
 			// Kill the process
 			tracker.Mutex.Lock()
 			if tracker.Process != nil && tracker.Process.Process != nil {
-				// Try graceful termination first
-				err := terminateProcessGroup(tracker.Process.Process.Pid)
-				if err != nil {
-					// Fallback to standard kill
-					if killErr := tracker.Process.Process.Kill(); killErr != nil {
-						// Process termination failed - may already be dead
-					}
-				}
+				// Try graceful termination first; a failed kill means it may already be dead
+				terminateTracker(tracker)
 				tracker.Status = StatusKilled
 				killedCount++
 
@@ -544,6 +1050,72 @@ func (r *ProcessRegistry) killProcessesBySession(sessionID string) int {
 	return killedCount
 }
 
+// matchesLabels reports whether a process carries every key/value in selector
+// Must be called with tracker.Mutex held (read or write)
+func matchesLabels(tracker *ProcessTracker, selector map[string]string) bool {
+	for key, value := range selector {
+		if labelValue, exists := tracker.Labels[key]; !exists || labelValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+// killProcessesByLabels kills all running or pending processes matching the label selector
+// An empty selector matches every process. Returns the IDs of the killed processes.
+func (r *ProcessRegistry) killProcessesByLabels(selector map[string]string) []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var killed []string
+	for _, tracker := range r.processes {
+		tracker.Mutex.Lock()
+		if !matchesLabels(tracker, selector) {
+			tracker.Mutex.Unlock()
+			continue
+		}
+
+		switch {
+		case tracker.Status == StatusRunning && tracker.Process != nil && tracker.Process.Process != nil:
+			if tracker.StdinWriter != nil {
+				tracker.StdinWriter.Close()
+			}
+			terminateTracker(tracker)
+			tracker.Status = StatusKilled
+			killed = append(killed, tracker.ID)
+			LogInfo("Process", fmt.Sprintf("Process killed by label match: %s", tracker.Command),
+				fmt.Sprintf("PID: %d, ID: %s", tracker.PID, tracker.ID))
+		case tracker.Status == StatusPending:
+			if tracker.CancelFunc != nil {
+				tracker.CancelFunc()
+			}
+			tracker.Status = StatusKilled
+			killed = append(killed, tracker.ID)
+			LogInfo("Process", fmt.Sprintf("Pending process cancelled by label match: %s", tracker.Command),
+				fmt.Sprintf("ID: %s", tracker.ID))
+		}
+		tracker.Mutex.Unlock()
+	}
+
+	return killed
+}
+
+// failProcessStart marks a process as failed to start and records why, both in LastError
+// and in the stdout buffer so output readers and the TUI can see the reason
+func failProcessStart(tracker *ProcessTracker, err error) error {
+	tracker.Mutex.Lock()
+	captureProcessEndTime(tracker) // ⏰ Capture timing for failed start
+	tracker.Status = StatusFailed
+	tracker.LastError = err.Error()
+	tracker.ExitReason = ExitReasonStartFailed
+	tracker.Mutex.Unlock()
+
+	tracker.StdoutBuffer.Write([]byte(fmt.Sprintf("[sidekick] failed to start: %v\n", err)))
+	LogError("Process", fmt.Sprintf("Process failed to start: %s", tracker.Command),
+		fmt.Sprintf("ID: %s, Error: %v", tracker.ID, err))
+	return err
+}
+
 // executeDelayedProcess actually starts the process after any delay
 func executeDelayedProcess(ctx context.Context, tracker *ProcessTracker, envVars map[string]string) error {
 	// Check if cancelled before starting (authoritative cancellation check)
@@ -559,6 +1131,11 @@ func executeDelayedProcess(ctx context.Context, tracker *ProcessTracker, envVars
 	default:
 	}
 
+	// Enforce --allowed-workdir / --allowed-command
+	if err := spawnPolicy.Check(tracker.Command, tracker.WorkingDir); err != nil {
+		return failProcessStart(tracker, err)
+	}
+
 	// Use background context for the process to avoid it being killed when request context is cancelled
 	cmd := exec.CommandContext(context.Background(), tracker.Command, tracker.Args...)
 	if tracker.WorkingDir != "" {
@@ -568,48 +1145,38 @@ func executeDelayedProcess(ctx context.Context, tracker *ProcessTracker, envVars
 	// Configure process group for proper cleanup
 	configureProcessGroup(cmd)
 
-	env := os.Environ()
-	env = append(env, "NO_COLOR=1", "TERM=dumb")
-	for k, v := range envVars {
-		env = append(env, fmt.Sprintf("%s=%s", k, v))
+	// Drop privileges to the requested user
+	if tracker.Credential != nil {
+		if err := applyProcessCredential(cmd, tracker.Credential); err != nil {
+			return failProcessStart(tracker, err)
+		}
 	}
-	cmd.Env = env
+
+	cmd.Env = processEnv(envVars)
 
 	stdinPipe, err := cmd.StdinPipe()
 	if err != nil {
-		tracker.Mutex.Lock()
-		captureProcessEndTime(tracker) // ⏰ Capture timing for failed setup
-		tracker.Status = StatusFailed
-		tracker.Mutex.Unlock()
-		return fmt.Errorf("failed to create stdin pipe: %v", err)
+		return failProcessStart(tracker, fmt.Errorf("failed to create stdin pipe: %v", err))
 	}
 
+	// Tracks the output readers so the final output is buffered before the exit is recorded
+	var streams sync.WaitGroup
+	var outputPipes []io.Closer
+
 	if tracker.CombineOutput {
 		// When combining output, redirect both stdout and stderr to the same buffer
 		stdoutPipe, err := cmd.StdoutPipe()
 		if err != nil {
-			tracker.Mutex.Lock()
-			captureProcessEndTime(tracker) // ⏰ Capture timing for failed setup
-			tracker.Status = StatusFailed
-			tracker.Mutex.Unlock()
-			return fmt.Errorf("failed to create stdout pipe: %v", err)
+			return failProcessStart(tracker, fmt.Errorf("failed to create stdout pipe: %v", err))
 		}
 
 		stderrPipe, err := cmd.StderrPipe()
 		if err != nil {
-			tracker.Mutex.Lock()
-			captureProcessEndTime(tracker) // ⏰ Capture timing for failed setup
-			tracker.Status = StatusFailed
-			tracker.Mutex.Unlock()
-			return fmt.Errorf("failed to create stderr pipe: %v", err)
+			return failProcessStart(tracker, fmt.Errorf("failed to create stderr pipe: %v", err))
 		}
 
 		if err := cmd.Start(); err != nil {
-			tracker.Mutex.Lock()
-			captureProcessEndTime(tracker) // ⏰ Capture timing for failed start
-			tracker.Status = StatusFailed
-			tracker.Mutex.Unlock()
-			return fmt.Errorf("failed to start process: %v", err)
+			return failProcessStart(tracker, fmt.Errorf("failed to start process: %v", err))
 		}
 
 		tracker.Mutex.Lock()
@@ -635,35 +1202,27 @@ func executeDelayedProcess(ctx context.Context, tracker *ProcessTracker, envVars
 
 		tracker.Mutex.Unlock()
 
-		// Stream both stdout and stderr to the same buffer (chronological order preserved)
-		go streamToRingBuffer(stdoutPipe, tracker.StdoutBuffer)
-		go streamToRingBuffer(stderrPipe, tracker.StdoutBuffer)
+		// Stream both stdout and stderr to the same buffer (chronological order preserved).
+		// Each pipe assembles its own lines; the shared writer lands them whole and one at a time.
+		outputPipes = append(outputPipes, stdoutPipe, stderrPipe)
+		streams.Add(2)
+		combined := processLineWriter(tracker, tracker.StdoutBuffer, "stdout")
+		go streamToRingBuffer(stdoutPipe, combined, expandLinePrefix(tracker, "stdout"), &streams, tracker.DedupConsecutive, tracker.MaxLineBytes, tracker.ID+" stdout")
+		go streamToRingBuffer(teeStderrToLogs(tracker, stderrPipe), combined, expandLinePrefix(tracker, "stderr"), &streams, tracker.DedupConsecutive, tracker.MaxLineBytes, tracker.ID+" stderr")
 	} else {
 		// Separate output streams
 		stdoutPipe, err := cmd.StdoutPipe()
 		if err != nil {
-			tracker.Mutex.Lock()
-			captureProcessEndTime(tracker) // ⏰ Capture timing for failed setup
-			tracker.Status = StatusFailed
-			tracker.Mutex.Unlock()
-			return fmt.Errorf("failed to create stdout pipe: %v", err)
+			return failProcessStart(tracker, fmt.Errorf("failed to create stdout pipe: %v", err))
 		}
 
 		stderrPipe, err := cmd.StderrPipe()
 		if err != nil {
-			tracker.Mutex.Lock()
-			captureProcessEndTime(tracker) // ⏰ Capture timing for failed setup
-			tracker.Status = StatusFailed
-			tracker.Mutex.Unlock()
-			return fmt.Errorf("failed to create stderr pipe: %v", err)
+			return failProcessStart(tracker, fmt.Errorf("failed to create stderr pipe: %v", err))
 		}
 
 		if err := cmd.Start(); err != nil {
-			tracker.Mutex.Lock()
-			captureProcessEndTime(tracker) // ⏰ Capture timing for failed start
-			tracker.Status = StatusFailed
-			tracker.Mutex.Unlock()
-			return fmt.Errorf("failed to start process: %v", err)
+			return failProcessStart(tracker, fmt.Errorf("failed to start process: %v", err))
 		}
 
 		tracker.Mutex.Lock()
@@ -689,20 +1248,41 @@ func executeDelayedProcess(ctx context.Context, tracker *ProcessTracker, envVars
 
 		tracker.Mutex.Unlock()
 
-		go streamToRingBuffer(stdoutPipe, tracker.StdoutBuffer)
-		go streamToRingBuffer(stderrPipe, tracker.StderrBuffer)
+		outputPipes = append(outputPipes, stdoutPipe, stderrPipe)
+		streams.Add(2)
+		go streamToRingBuffer(stdoutPipe, processLineWriter(tracker, tracker.StdoutBuffer, "stdout"), expandLinePrefix(tracker, "stdout"), &streams, tracker.DedupConsecutive, tracker.MaxLineBytes, tracker.ID+" stdout")
+		go streamToRingBuffer(teeStderrToLogs(tracker, stderrPipe), processLineWriter(tracker, tracker.StderrBuffer, "stderr"), expandLinePrefix(tracker, "stderr"), &streams, tracker.DedupConsecutive, tracker.MaxLineBytes, tracker.ID+" stderr")
 	}
 
 	go func() {
-		err := cmd.Wait()
+		var err error
+		if tracker.WaitOnMainOnly {
+			err = waitMainOnly(cmd, &streams, stdinPipe, outputPipes)
+		} else {
+			// Drain the pipes first: cmd.Wait closes them, which would drop unread output
+			streams.Wait()
+			err = cmd.Wait()
+		}
+		// Runs after the tracker lock is released so the resource reflects the final state
+		defer finalizeProcessResources(tracker)
+		defer startExitHook(tracker)
+
 		tracker.Mutex.Lock()
 		defer tracker.Mutex.Unlock()
 
+		exitReason, exitSignal := ExitReasonExitedNonzero, ""
+		if cmd.ProcessState != nil {
+			exitReason, exitSignal = classifyExit(cmd.ProcessState)
+		}
+		tracker.Signal = exitSignal
+
 		// If process was already killed (e.g., by session cleanup), don't override the status
 		if tracker.Status == StatusKilled {
+			tracker.ExitReason = ExitReasonKilled
 			captureProcessEndTime(tracker) // ⏰ Still capture timing for killed processes
 			return
 		}
+		tracker.ExitReason = exitReason
 
 		// ⏰ Capture end time and duration for finished processes
 		captureProcessEndTime(tracker)
@@ -734,7 +1314,10 @@ func executeDelayedProcess(ctx context.Context, tracker *ProcessTracker, envVars
 		if tracker.ExitCode != nil {
 			logMsg += fmt.Sprintf(", exit code: %d", *tracker.ExitCode)
 		}
-		logMsg += fmt.Sprintf(", status: %s", tracker.Status)
+		logMsg += fmt.Sprintf(", status: %s, reason: %s", tracker.Status, tracker.ExitReason)
+		if tracker.Signal != "" {
+			logMsg += fmt.Sprintf(", signal: %s", tracker.Signal)
+		}
 		if tracker.SessionID != "" {
 			logMsg += fmt.Sprintf(", session: %s", tracker.SessionID)
 		}
@@ -755,7 +1338,44 @@ func executeDelayedProcess(ctx context.Context, tracker *ProcessTracker, envVars
 	return nil
 }
 
+// waitMainOnly waits for the main process alone, for children that inherit the output pipes
+// and keep them open (daemons). Output is still read for MainExitDrainGrace after the exit,
+// then the pipes are closed - anything the children write later is lost.
+func waitMainOnly(cmd *exec.Cmd, streams *sync.WaitGroup, stdin io.Closer, outputPipes []io.Closer) error {
+	// Process.Wait, unlike cmd.Wait, leaves the pipes open so buffered output can be drained
+	state, err := cmd.Process.Wait()
+	if err != nil {
+		return err
+	}
+	cmd.ProcessState = state
+	_ = stdin.Close()
+
+	drained := make(chan struct{})
+	go func() {
+		streams.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+	case <-time.After(MainExitDrainGrace):
+		for _, pipe := range outputPipes {
+			_ = pipe.Close()
+		}
+		<-drained
+	}
+
+	if !state.Success() {
+		return &exec.ExitError{ProcessState: state}
+	}
+	return nil
+}
+
 func handleSpawnProcess(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if refused := maintenance.refuse("spawns"); refused != nil {
+		return refused, nil
+	}
+
 	command, err := request.RequireString("command")
 	if err != nil {
 		return mcp.NewToolResultError("Missing or invalid 'command' argument"), nil
@@ -763,16 +1383,86 @@ func handleSpawnProcess(ctx context.Context, request mcp.CallToolRequest) (*mcp.
 
 	args := getStringArrayArg(request, "args")
 	workingDir := getStringArg(request, "working_dir", "")
-	envVars := getStringMapArg(request, "env")
-	bufferSize := getInt64Arg(request, "buffer_size", DefaultBufferSize)
-	combineOutput := getBoolArg(request, "combine_output", false)
-	syncDelay := getBoolArg(request, "sync_delay", false)
+	envVars, err := getSpawnEnvArgs(request, workingDir)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	bufferSize := getInt64Arg(request, "buffer_size", DefaultBufferSize)
+	combineOutput := getBoolArg(request, "combine_output", defaultCombineOutput)
+	syncDelay := getBoolArg(request, "sync_delay", false)
 	name := getStringArg(request, "name", "")
+	captureGit := getBoolArg(request, "capture_git", false)
+	labels := getStringMapArg(request, "labels")
+	timestampLines := getBoolArg(request, "timestamp_lines", defaultCombineReads)
+	dedupConsecutive := getBoolArg(request, "dedup_consecutive", false)
+	waitOnMainOnly := getBoolArg(request, "wait_on_main_only", false)
+
+	linePrefix := getStringArg(request, "line_prefix", "")
+	if len(linePrefix) > MaxLinePrefixLength {
+		return mcp.NewToolResultError(fmt.Sprintf("line_prefix cannot exceed %d bytes", MaxLinePrefixLength)), nil
+	}
+	if err := bufferBudget.admit(totalBufferedBytes(registry.getAllProcesses()), bufferSize, combineOutput); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	var progress *progressMatcher
+	progressRegex := getStringArg(request, "progress_regex", "")
+	if progressRegex != "" {
+		if progress, err = newProgressMatcher(progressRegex); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+
+	redactPatterns := getStringArrayArg(request, "redact_patterns")
+	redactBuiltin := getBoolArg(request, "redact_builtin", false)
+	redactor, err := newOutputRedactor(redactPatterns, redactBuiltin)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	teeStderr := getBoolArg(request, "tee_stderr_to_logs", false)
+	flushPartial := getBoolArg(request, "flush_partial", false)
+
+	var ready *readyProbe
+	readyPattern := getStringArg(request, "ready_pattern", "")
+	if readyPattern != "" {
+		if ready, err = newReadyProbe(readyPattern); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+	}
+	if flushPartial && dedupConsecutive {
+		return mcp.NewToolResultError("flush_partial cannot be combined with dedup_consecutive"), nil
+	}
+
+	// The exit hook is checked against the spawn policy now, so a bad hook fails the spawn
+	onExitCommand := getStringArrayArg(request, "on_exit_command")
+	if len(onExitCommand) > 0 {
+		if onExitCommand[0] == "" {
+			return mcp.NewToolResultError("on_exit_command must start with a command"), nil
+		}
+		if err := spawnPolicy.Check(onExitCommand[0], workingDir); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("on_exit_command: %v", err)), nil
+		}
+	}
+
+	maxLineBytes := getIntArg(request, "max_line_bytes", DefaultMaxLineBytes)
+	if maxLineBytes < 1 || maxLineBytes > MaxLineBytesLimit {
+		return mcp.NewToolResultError(fmt.Sprintf("max_line_bytes must be between 1 and %d", MaxLineBytesLimit)), nil
+	}
+
+	cols, rows, err := getWinsizeArgs(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	credential, err := resolveProcessCredential(getStringArg(request, "run_as_user", ""), getIntArg(request, "run_as_uid", -1))
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
 	// Handle delay with validation
 	delayMs := getInt64Arg(request, "delay", 0)
 	if delayMs > MaxSpawnDelay {
-		return mcp.NewToolResultError(fmt.Sprintf("Delay cannot exceed %d milliseconds (5 minutes)", MaxSpawnDelay)), nil
+		return mcp.NewToolResultError(fmt.Sprintf("Delay cannot exceed %d milliseconds (%s)", MaxSpawnDelay, msDuration(MaxSpawnDelay))), nil
 	}
 	if delayMs < 0 {
 		return mcp.NewToolResultError("Delay cannot be negative"), nil
@@ -783,21 +1473,61 @@ func handleSpawnProcess(ctx context.Context, request mcp.CallToolRequest) (*mcp.
 	sessionID := ExtractSessionFromContext(ctx)
 
 	processID := uuid.New().String()
+
+	// A retried spawn with the same idempotency key returns the original process
+	idempotencyKey := getStringArg(request, "idempotency_key", "")
+	registered := false
+	if idempotencyKey != "" {
+		existingID, reserved := spawnIdempotency.Reserve(registry, sessionID, idempotencyKey, processID)
+		if !reserved {
+			return idempotentSpawnResult(existingID), nil
+		}
+
+		// Keep the key only if the process made it into the registry
+		defer func() {
+			if registered {
+				spawnIdempotency.Confirm(sessionID, idempotencyKey)
+			} else {
+				spawnIdempotency.Release(sessionID, idempotencyKey)
+			}
+		}()
+	}
+
 	tracker := &ProcessTracker{
-		ID:            processID,
-		Name:          name,
-		SessionID:     sessionID,
-		Command:       command,
-		Args:          args,
-		WorkingDir:    workingDir,
-		BufferSize:    bufferSize,
-		CombineOutput: combineOutput,
-		DelayStart:    delay,
-		SyncDelay:     syncDelay,
-		StartTime:     time.Now(),
-		LastAccessed:  time.Now(),
-		Status:        StatusRunning, // Will be changed based on delay logic
-		StdoutBuffer:  NewRingBuffer(bufferSize),
+		ID:               processID,
+		Name:             name,
+		SessionID:        sessionID,
+		Command:          command,
+		Args:             args,
+		WorkingDir:       workingDir,
+		BufferSize:       bufferSize,
+		CombineOutput:    combineOutput,
+		DelayStart:       delay,
+		SyncDelay:        syncDelay,
+		StartTime:        time.Now(),
+		LastAccessed:     time.Now(),
+		Status:           StatusRunning, // Will be changed based on delay logic
+		StdoutBuffer:     NewRingBuffer(bufferSize),
+		Credential:       credential,
+		Labels:           labels,
+		Cols:             cols,
+		Rows:             rows,
+		IdempotencyKey:   idempotencyKey,
+		DedupConsecutive: dedupConsecutive,
+		MaxLineBytes:     maxLineBytes,
+		WaitOnMainOnly:   waitOnMainOnly,
+		OnExitCommand:    onExitCommand,
+		LinePrefix:       linePrefix,
+		ProgressRegex:    progressRegex,
+		Progress:         progress,
+		RedactPatterns:   redactPatterns,
+		RedactBuiltin:    redactBuiltin,
+		Redactor:         redactor,
+		TeeStderrToLogs:  teeStderr,
+		FlushPartial:     flushPartial,
+		ReadyPattern:     readyPattern,
+		Ready:            ready,
+		Env:              envVars,
 	}
 
 	// Only create stderr buffer if not combining output
@@ -805,6 +1535,16 @@ func handleSpawnProcess(ctx context.Context, request mcp.CallToolRequest) (*mcp.
 		tracker.StderrBuffer = NewRingBuffer(bufferSize)
 	}
 
+	// Record line write times so output can be read by age (since_ms_ago)
+	if timestampLines {
+		tracker.enableLineTimestamps()
+	}
+
+	// Record the git branch/commit of the working directory (opt-in)
+	if captureGit {
+		tracker.GitBranch, tracker.GitCommit = captureGitContext(workingDir)
+	}
+
 	// Handle delay logic
 	var result map[string]any
 	if delay > 0 {
@@ -818,17 +1558,14 @@ func handleSpawnProcess(ctx context.Context, request mcp.CallToolRequest) (*mcp.
 			}
 
 			registry.addProcess(tracker)
+			registered = true
 
 			// Add to session manager if in SSE mode
 			if sessionID != "" && sessionManager != nil {
 				sessionManager.AddProcessToSession(sessionID, processID)
 			}
 
-			result = map[string]any{
-				"process_id": processID,
-				"pid":        tracker.PID,
-				"status":     string(tracker.Status),
-			}
+			result = spawnResult(tracker)
 
 		} else {
 			// Async mode: set pending status, register immediately, start background delay
@@ -839,6 +1576,7 @@ func handleSpawnProcess(ctx context.Context, request mcp.CallToolRequest) (*mcp.
 			tracker.CancelFunc = cancelFunc
 
 			registry.addProcess(tracker)
+			registered = true
 
 			// Add to session manager if in SSE mode
 			if sessionID != "" && sessionManager != nil {
@@ -864,11 +1602,7 @@ func handleSpawnProcess(ctx context.Context, request mcp.CallToolRequest) (*mcp.
 				}
 			}()
 
-			result = map[string]any{
-				"process_id": processID,
-				"pid":        0, // No PID yet since process hasn't started
-				"status":     string(tracker.Status),
-			}
+			result = spawnResult(tracker) // pid stays 0 until the delayed start
 		}
 	} else {
 		// No delay: execute immediately (original behavior)
@@ -878,40 +1612,216 @@ func handleSpawnProcess(ctx context.Context, request mcp.CallToolRequest) (*mcp.
 		}
 
 		registry.addProcess(tracker)
+		registered = true
 
 		// Add to session manager if in SSE mode
 		if sessionID != "" && sessionManager != nil {
 			sessionManager.AddProcessToSession(sessionID, processID)
 		}
 
-		result = map[string]any{
-			"process_id": processID,
-			"pid":        tracker.PID,
-			"status":     string(tracker.Status),
-		}
+		result = spawnResult(tracker)
 	}
 
 	resultBytes, _ := json.Marshal(result)
 	return mcp.NewToolResultText(string(resultBytes)), nil
 }
 
-func handleSpawnMultipleProcesses(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-	// Parse the processes array
-	var processes []map[string]any
-	if arguments, ok := request.Params.Arguments.(map[string]any); ok {
-		if procs, exists := arguments["processes"]; exists {
-			if procsList, ok := procs.([]any); ok {
-				for _, proc := range procsList {
-					if procMap, ok := proc.(map[string]any); ok {
-						processes = append(processes, procMap)
-					}
-				}
+// processEnv returns the environment a spawned process gets: sidekick's own, with colors and
+// terminal features turned off, plus envVars
+func processEnv(envVars map[string]string) []string {
+	env := os.Environ()
+	env = append(env, "NO_COLOR=1", "TERM=dumb")
+	for k, v := range envVars {
+		env = append(env, fmt.Sprintf("%s=%s", k, v))
+	}
+	return env
+}
+
+// spawnResult describes a just-spawned process from one snapshot taken under its mutex. A command
+// that exits before the spawn returns is reported with its final status and exit code, never a mix.
+func spawnResult(tracker *ProcessTracker) map[string]any {
+	tracker.Mutex.RLock()
+	defer tracker.Mutex.RUnlock()
+
+	result := map[string]any{
+		"process_id": tracker.ID,
+		"pid":        tracker.PID,
+		"status":     string(tracker.Status),
+	}
+	if tracker.ExitCode != nil {
+		result["exit_code"] = *tracker.ExitCode
+	}
+	if tracker.ExitReason != "" {
+		result["exit_reason"] = tracker.ExitReason
+	}
+	return result
+}
+
+// idempotentSpawnResult answers a duplicate spawn with the process already started for its key
+func idempotentSpawnResult(processID string) *mcp.CallToolResult {
+	result := map[string]any{
+		"process_id":        processID,
+		"pid":               0,
+		"status":            string(StatusPending), // Original spawn is still starting
+		"idempotent_replay": true,
+	}
+
+	if tracker, exists := registry.getProcess(processID); exists {
+		tracker.Mutex.RLock()
+		result["pid"] = tracker.PID
+		result["status"] = string(tracker.Status)
+		tracker.Mutex.RUnlock()
+	}
+
+	resultBytes, _ := json.Marshal(result)
+	return mcp.NewToolResultText(string(resultBytes))
+}
+
+// spawnEntryFieldTypes lists the fields accepted in a spawn_multiple_processes entry
+var spawnEntryFieldTypes = map[string]string{
+	"command":         "string",
+	"args":            "array of strings",
+	"name":            "string",
+	"working_dir":     "string",
+	"env":             "object of strings",
+	"labels":          "object of strings",
+	"buffer_size":     "number",
+	"delay":           "number",
+	"combine_output":  "boolean",
+	"sync_delay":      "boolean",
+	"capture_git":     "boolean",
+	"timestamp_lines": "boolean",
+	"line_prefix":     "string",
+}
+
+// hasSpawnFieldType reports whether value is of the JSON type named in spawnEntryFieldTypes
+func hasSpawnFieldType(value any, kind string) bool {
+	switch kind {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "array of strings":
+		list, ok := value.([]any)
+		if !ok {
+			return false
+		}
+		for _, item := range list {
+			if _, ok := item.(string); !ok {
+				return false
+			}
+		}
+		return true
+	case "object of strings":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return false
+		}
+		for _, item := range object {
+			if _, ok := item.(string); !ok {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// validateSpawnEntries checks every spawn_multiple_processes entry before anything is spawned,
+// so a malformed field is reported instead of being silently dropped
+func validateSpawnEntries(arguments any) ([]map[string]any, error) {
+	var procs any
+	if argsMap, ok := arguments.(map[string]any); ok {
+		procs = argsMap["processes"]
+	}
+	if procs == nil {
+		return nil, fmt.Errorf("No processes specified")
+	}
+	procsList, ok := procs.([]any)
+	if !ok {
+		return nil, fmt.Errorf("'processes' must be an array of process configurations")
+	}
+	if len(procsList) == 0 {
+		return nil, fmt.Errorf("No processes specified")
+	}
+
+	processes := make([]map[string]any, 0, len(procsList))
+	used := totalBufferedBytes(registry.getAllProcesses()) // Each entry's buffers count against the next
+	for i, proc := range procsList {
+		procConfig, ok := proc.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("Process %d: must be an object", i)
+		}
+
+		fields := make([]string, 0, len(procConfig))
+		for field := range procConfig {
+			fields = append(fields, field)
+		}
+		slices.Sort(fields) // Report the same error for the same input
+
+		for _, field := range fields {
+			kind, known := spawnEntryFieldTypes[field]
+			if !known {
+				return nil, fmt.Errorf("Process %d: unknown field '%s'", i, field)
+			}
+			if !hasSpawnFieldType(procConfig[field], kind) {
+				return nil, fmt.Errorf("Process %d: '%s' must be %s", i, field, withArticle(kind))
+			}
+		}
+
+		if command, _ := procConfig["command"].(string); command == "" {
+			return nil, fmt.Errorf("Process %d missing required 'command' field", i)
+		}
+		if bufferSize, exists := procConfig["buffer_size"].(float64); exists && bufferSize <= 0 {
+			return nil, fmt.Errorf("Process %d: 'buffer_size' must be positive", i)
+		}
+		bufferSize := float64(DefaultBufferSize)
+		if size, exists := procConfig["buffer_size"].(float64); exists {
+			bufferSize = size
+		}
+		combineOutput := defaultCombineOutput
+		if combine, exists := procConfig["combine_output"].(bool); exists {
+			combineOutput = combine
+		}
+		if err := bufferBudget.admit(used, int64(bufferSize), combineOutput); err != nil {
+			return nil, fmt.Errorf("Process %d: %v", i, err)
+		}
+		used += bufferCapacity(int64(bufferSize), combineOutput)
+		if linePrefix, _ := procConfig["line_prefix"].(string); len(linePrefix) > MaxLinePrefixLength {
+			return nil, fmt.Errorf("Process %d: 'line_prefix' cannot exceed %d bytes", i, MaxLinePrefixLength)
+		}
+		if delayMs, exists := procConfig["delay"].(float64); exists {
+			if int64(delayMs) > MaxSpawnDelay {
+				return nil, fmt.Errorf("Process %d: Delay cannot exceed %d milliseconds (%s)", i, MaxSpawnDelay, msDuration(MaxSpawnDelay))
+			}
+			if delayMs < 0 {
+				return nil, fmt.Errorf("Process %d: Delay cannot be negative", i)
 			}
 		}
+
+		processes = append(processes, procConfig)
+	}
+
+	return processes, nil
+}
+
+// withArticle prefixes a type description with "a" or "an"
+func withArticle(kind string) string {
+	if strings.ContainsRune("aeiou", rune(kind[0])) {
+		return "an " + kind
 	}
+	return "a " + kind
+}
 
-	if len(processes) == 0 {
-		return mcp.NewToolResultError("No processes specified"), nil
+func handleSpawnMultipleProcesses(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	processes, err := validateSpawnEntries(request.Params.Arguments)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
 	}
 
 	// Results to return
@@ -925,18 +1835,22 @@ func handleSpawnMultipleProcesses(ctx context.Context, request mcp.CallToolReque
 		name      string
 		processID string
 		ctx       context.Context
+		startAt   time.Time
 	}
 
 	var deferredProcesses []processInfo
 	var deferredMode bool
 
+	// Delays are cumulative: entry N starts at the sum of the delays of entries 0..N, measured
+	// from now. Waiting for absolute times keeps the time spent starting earlier processes from
+	// pushing later ones back.
+	scheduleStart := time.Now()
+	var startOffset time.Duration
+
 	// Process each configuration
 	for i, procConfig := range processes {
-		// Extract configuration for this process
-		command, exists := procConfig["command"].(string)
-		if !exists {
-			return mcp.NewToolResultError(fmt.Sprintf("Process %d missing required 'command' field", i)), nil
-		}
+		// Extract configuration for this process (types were checked by validateSpawnEntries)
+		command := procConfig["command"].(string)
 
 		// Extract optional args
 		args := []string{}
@@ -975,7 +1889,7 @@ func handleSpawnMultipleProcesses(ctx context.Context, request mcp.CallToolReque
 		}
 
 		// Extract combine output
-		combineOutput := false
+		combineOutput := defaultCombineOutput
 		if co, exists := procConfig["combine_output"]; exists {
 			if coBool, ok := co.(bool); ok {
 				combineOutput = coBool
@@ -986,16 +1900,11 @@ func handleSpawnMultipleProcesses(ctx context.Context, request mcp.CallToolReque
 		delay := time.Duration(0)
 		if d, exists := procConfig["delay"]; exists {
 			if dFloat, ok := d.(float64); ok {
-				delayMs := int64(dFloat)
-				if delayMs > MaxSpawnDelay {
-					return mcp.NewToolResultError(fmt.Sprintf("Process %d: Delay cannot exceed %d milliseconds (5 minutes)", i, MaxSpawnDelay)), nil
-				}
-				if delayMs < 0 {
-					return mcp.NewToolResultError(fmt.Sprintf("Process %d: Delay cannot be negative", i)), nil
-				}
-				delay = time.Duration(delayMs) * time.Millisecond
+				delay = time.Duration(int64(dFloat)) * time.Millisecond
 			}
 		}
+		startOffset += delay
+		startAt := scheduleStart.Add(startOffset)
 
 		// Extract sync_delay
 		syncDelay := false
@@ -1005,6 +1914,30 @@ func handleSpawnMultipleProcesses(ctx context.Context, request mcp.CallToolReque
 			}
 		}
 
+		// Extract capture_git
+		captureGit, _ := procConfig["capture_git"].(bool)
+
+		// Extract timestamp_lines
+		timestampLines := defaultCombineReads
+		if tl, ok := procConfig["timestamp_lines"].(bool); ok {
+			timestampLines = tl
+		}
+
+		// Extract line_prefix
+		linePrefix, _ := procConfig["line_prefix"].(string)
+
+		// Extract labels
+		labels := make(map[string]string)
+		if l, exists := procConfig["labels"]; exists {
+			if labelMap, ok := l.(map[string]any); ok {
+				for k, v := range labelMap {
+					if vStr, ok := v.(string); ok {
+						labels[k] = vStr
+					}
+				}
+			}
+		}
+
 		// Create tracker
 		processID := uuid.New().String()
 
@@ -1026,12 +1959,23 @@ func handleSpawnMultipleProcesses(ctx context.Context, request mcp.CallToolReque
 			LastAccessed:  time.Now(),
 			Status:        StatusRunning,
 			StdoutBuffer:  NewRingBuffer(bufferSize),
+			Labels:        labels,
+			LinePrefix:    linePrefix,
+			Env:           envVars,
 		}
 
 		if !combineOutput {
 			tracker.StderrBuffer = NewRingBuffer(bufferSize)
 		}
 
+		if timestampLines {
+			tracker.enableLineTimestamps()
+		}
+
+		if captureGit {
+			tracker.GitBranch, tracker.GitCommit = captureGitContext(workingDir)
+		}
+
 		// Determine if we need to defer this process
 		shouldDefer := deferredMode || (!syncDelay && (delay > 0 || deferredMode))
 
@@ -1058,29 +2002,29 @@ func handleSpawnMultipleProcesses(ctx context.Context, request mcp.CallToolReque
 				name:      name,
 				processID: processID,
 				ctx:       deferCtx,
+				startAt:   startAt,
 			})
 
 			results = append(results, map[string]any{
-				"index":      i,
-				"name":       name,
-				"process_id": processID,
-				"pid":        0,
-				"status":     "pending",
+				"index":           i,
+				"name":            name,
+				"process_id":      processID,
+				"pid":             0,
+				"status":          "pending",
+				"start_offset_ms": startOffset.Milliseconds(),
 			})
 		} else {
 			// Process immediately (sync mode or no delay in non-deferred mode)
-			if delay > 0 {
-				// Wait for the delay
-				time.Sleep(delay)
-			}
+			time.Sleep(time.Until(startAt))
 
 			err := executeDelayedProcess(ctx, tracker, envVars)
 			if err != nil {
 				results = append(results, map[string]any{
-					"index":      i,
-					"name":       name,
-					"process_id": processID,
-					"error":      err.Error(),
+					"index":           i,
+					"name":            name,
+					"process_id":      processID,
+					"error":           err.Error(),
+					"start_offset_ms": startOffset.Milliseconds(),
 				})
 				continue
 			}
@@ -1092,20 +2036,18 @@ func handleSpawnMultipleProcesses(ctx context.Context, request mcp.CallToolReque
 				sessionManager.AddProcessToSession(sessionID, processID)
 			}
 
-			results = append(results, map[string]any{
-				"index":      i,
-				"name":       name,
-				"process_id": processID,
-				"pid":        tracker.PID,
-				"status":     string(tracker.Status),
-			})
+			result := spawnResult(tracker)
+			result["index"] = i
+			result["name"] = name
+			result["start_offset_ms"] = startOffset.Milliseconds()
+			results = append(results, result)
 		}
 	}
 
 	// If we have deferred processes, start them in a goroutine
 	if len(deferredProcesses) > 0 {
 		go func() {
-			// Process each deferred process with its delay
+			// Start each deferred process at its scheduled time
 			for _, info := range deferredProcesses {
 				// Check if cancelled before waiting
 				select {
@@ -1120,20 +2062,18 @@ func handleSpawnMultipleProcesses(ctx context.Context, request mcp.CallToolReque
 				default:
 				}
 
-				// Wait for the delay with cancellation support
-				if info.tracker.DelayStart > 0 {
-					select {
-					case <-time.After(info.tracker.DelayStart):
-						// Delay completed
-					case <-info.ctx.Done():
-						// Cancelled during delay (e.g., shutdown)
-						info.tracker.Mutex.Lock()
-						if info.tracker.Status == StatusPending {
-							info.tracker.Status = StatusKilled
-						}
-						info.tracker.Mutex.Unlock()
-						continue
+				// Wait for the scheduled start with cancellation support
+				select {
+				case <-time.After(time.Until(info.startAt)):
+					// Start time reached
+				case <-info.ctx.Done():
+					// Cancelled during delay (e.g., shutdown)
+					info.tracker.Mutex.Lock()
+					if info.tracker.Status == StatusPending {
+						info.tracker.Status = StatusKilled
 					}
+					info.tracker.Mutex.Unlock()
+					continue
 				}
 
 				// Execute the process
@@ -1153,14 +2093,260 @@ func handleSpawnMultipleProcesses(ctx context.Context, request mcp.CallToolReque
 	return mcp.NewToolResultText(string(resultBytes)), nil
 }
 
-func streamToRingBuffer(reader io.ReadCloser, buffer *RingBuffer) {
+// MaxLinePrefixLength caps line_prefix templates
+const MaxLinePrefixLength = 256
+
+// expandLinePrefix fills in a process's line_prefix for one stream. Placeholders: {name} (the
+// command's base name if the process is unnamed), {pid}, {id} (first 8 characters), and {stream}.
+// Must be called once the process has started, so {pid} is known.
+func expandLinePrefix(tracker *ProcessTracker, stream string) string {
+	if tracker.LinePrefix == "" {
+		return ""
+	}
+
+	name := tracker.Name
+	if name == "" {
+		name = filepath.Base(tracker.Command)
+	}
+	return strings.NewReplacer(
+		"{name}", name,
+		"{pid}", strconv.Itoa(tracker.PID),
+		"{id}", tracker.ID[:min(8, len(tracker.ID))],
+		"{stream}", stream,
+	).Replace(tracker.LinePrefix)
+}
+
+// lineWriter writes whole output lines into a buffer. Streams that share a buffer (combine_output)
+// share one lineWriter, so their lines are serialized: each line lands with its newline in a single
+// write, never split by a line from the other stream. flush_partial lines may land in pieces (see
+// partialFlusher); a line from the other stream ends an open piece with a newline first.
+type lineWriter struct {
+	mu           sync.Mutex
+	buffer       *RingBuffer
+	notify       func()
+	onOverflow   func()                   // Called once, the first time the buffer drops output
+	observe      func(line string)        // Sees each line before its prefix is added (progress_regex)
+	redact       func(line string) string // Rewrites each line before it is stored (redact_patterns)
+	overflowed   bool
+	partialAfter time.Duration   // Commit a pending unterminated line after this long (flush_partial, 0 = never)
+	open         *partialFlusher // Stream whose committed partial line ends the buffer, if any
+}
+
+func newLineWriter(buffer *RingBuffer, notify func()) *lineWriter {
+	return &lineWriter{buffer: buffer, notify: notify}
+}
+
+// processLineWriter returns the lineWriter for one of the process's output buffers, which warns
+// in the logs the first time that buffer overflows
+func processLineWriter(tracker *ProcessTracker, buffer *RingBuffer, stream string) *lineWriter {
+	writer := newLineWriter(buffer, processOutputNotifier(tracker.ID, stream))
+	writer.onOverflow = func() {
+		LogWarn("Process", "Output buffer full, discarding oldest output: "+tracker.Command,
+			fmt.Sprintf("ID: %s, stream: %s (raise buffer_size to keep more)", tracker.ID, stream))
+	}
+	if tracker.Progress != nil {
+		writer.observe = tracker.Progress.observe
+	}
+	if probe := tracker.Ready; probe != nil {
+		progress := writer.observe
+		writer.observe = func(line string) {
+			if progress != nil {
+				progress(line)
+			}
+			probe.observe(line)
+		}
+	}
+	if tracker.Redactor != nil {
+		writer.redact = tracker.Redactor.redact
+	}
+	if tracker.FlushPartial {
+		writer.partialAfter = PartialFlushDelay
+	}
+	return writer
+}
+
+// writePrefixed writes prefix+line, letting the observer see the line as the process printed it.
+// Secrets are redacted before the prefix is added, so patterns can anchor on the line start.
+func (w *lineWriter) writePrefixed(prefix, line string) {
+	if w.observe != nil {
+		w.observe(line)
+	}
+	if w.redact != nil {
+		line = w.redact(line)
+	}
+	w.writeLine(prefix + line)
+}
+
+// writeLine appends line and a newline to the buffer
+func (w *lineWriter) writeLine(line string) {
+	w.append(nil, false, func(bool) string { return line + "\n" })
+}
+
+// append writes text on behalf of owner (nil for whole lines written directly). A partial line
+// left open by another stream is ended first, so lines never run together. text learns whether
+// owner's own partial line is still the end of the buffer; open marks text as a new partial line.
+func (w *lineWriter) append(owner *partialFlusher, open bool, text func(continued bool) string) {
+	w.mu.Lock()
+	data := text(owner != nil && w.open == owner)
+	if w.open != nil && w.open != owner {
+		data = "\n" + data
+	}
+	w.open = nil
+	if open {
+		w.open = owner
+	}
+	w.buffer.Write([]byte(data))
+	overflowed := !w.overflowed && w.onOverflow != nil && w.buffer.DiscardedBytes() > 0
+	if overflowed {
+		w.overflowed = true
+	}
+	w.mu.Unlock()
+
+	if overflowed {
+		w.onOverflow()
+	}
+	w.notify() // Outside the lock, so a slow subscriber never holds up the other stream
+}
+
+func streamToRingBuffer(reader io.ReadCloser, out *lineWriter, prefix string, done *sync.WaitGroup, dedup bool, maxLineBytes int, source string) {
+	defer done.Done()
 	defer reader.Close()
 
+	write := func(line string) {
+		out.writePrefixed(prefix, line)
+	}
+
+	if maxLineBytes <= 0 {
+		maxLineBytes = DefaultMaxLineBytes
+	}
+
+	var deduper lineDeduper
 	scanner := bufio.NewScanner(reader)
+	// Room for a full line plus its \r\n, so an oversized line is always detected
+	scanner.Buffer(make([]byte, 0, min(maxLineBytes+2, bufio.MaxScanTokenSize)), maxLineBytes+2)
+	scanner.Split(cappedLineSplitter(maxLineBytes))
+	if out.partialAfter > 0 && !dedup {
+		flusher := newPartialFlusher(out, prefix)
+		defer flusher.stop()
+		scanner.Split(flusher.split(cappedLineSplitter(maxLineBytes)))
+		write = flusher.finish
+	}
 	for scanner.Scan() {
-		line := scanner.Text() + "\n"
-		buffer.Write([]byte(line))
+		if !dedup {
+			write(scanner.Text())
+			continue
+		}
+		for _, line := range deduper.push(scanner.Text()) {
+			write(line)
+		}
+	}
+	for _, line := range deduper.flush() {
+		write(line)
 	}
+
+	// A scanner error stops Scan for good; keep draining so the process never blocks on a full pipe
+	if err := scanner.Err(); err != nil && !errors.Is(err, os.ErrClosed) {
+		if errors.Is(err, bufio.ErrTooLong) {
+			LogWarn("Process", "Output line too long, discarding the rest of the stream", fmt.Sprintf("Stream: %s, max line bytes: %d", source, maxLineBytes))
+		} else {
+			LogWarn("Process", "Output stream read failed", fmt.Sprintf("Stream: %s, error: %v", source, err))
+		}
+		_, _ = io.Copy(io.Discard, reader)
+	}
+}
+
+// cappedLineSplitter splits like bufio.ScanLines, but a line longer than maxBytes is cut
+// (on a UTF-8 boundary) and marked with LineTruncatedMarker, and the rest of it is skipped.
+// Memory stays bounded by maxBytes no matter how long the line gets.
+func cappedLineSplitter(maxBytes int) bufio.SplitFunc {
+	discarding := false // Skipping the remainder of a truncated line
+
+	return func(data []byte, atEOF bool) (int, []byte, error) {
+		if atEOF && len(data) == 0 {
+			return 0, nil, nil
+		}
+
+		newline := bytes.IndexByte(data, '\n')
+		if discarding {
+			if newline < 0 {
+				return len(data), nil, nil
+			}
+			discarding = false
+			return newline + 1, nil, nil
+		}
+
+		if newline >= 0 {
+			if line := bytes.TrimSuffix(data[:newline], []byte("\r")); len(line) <= maxBytes {
+				return newline + 1, line, nil
+			}
+		} else if len(data) <= maxBytes {
+			if atEOF {
+				return len(data), bytes.TrimSuffix(data, []byte("\r")), nil
+			}
+			return 0, nil, nil // Request more data
+		}
+
+		cut := maxBytes
+		for cut > 0 && !utf8.RuneStart(data[cut]) {
+			cut--
+		}
+		token := append(append([]byte{}, data[:cut]...), LineTruncatedMarker...)
+		if newline >= 0 {
+			return newline + 1, token, nil
+		}
+		discarding = !atEOF
+		return len(data), token, nil
+	}
+}
+
+// DedupFlushEvery bounds how many repeats are held back before a "(repeated xN)" line is written
+const DedupFlushEvery = 1000
+
+// lineDeduper collapses runs of identical lines: the first line is passed through immediately
+// and the rest of the run becomes a single "<line> (repeated xN)" line once the run ends.
+// Only the lines actually written reach the buffer, so cursors and total bytes stay consistent.
+type lineDeduper struct {
+	last    string
+	started bool
+	repeats int // Copies of last held back since it was written
+}
+
+// push returns the lines to write for the next input line
+func (d *lineDeduper) push(line string) []string {
+	if d.started && line == d.last {
+		d.repeats++
+		if d.repeats >= DedupFlushEvery {
+			return d.flush()
+		}
+		return nil
+	}
+
+	lines := d.flush()
+	d.last = line
+	d.started = true
+	return append(lines, line)
+}
+
+// flush returns the pending "(repeated xN)" line, if any, and resets the repeat count
+func (d *lineDeduper) flush() []string {
+	if d.repeats == 0 {
+		return nil
+	}
+	line := fmt.Sprintf("%s (repeated x%d)", d.last, d.repeats)
+	d.repeats = 0
+	return []string{line}
+}
+
+// hasNewOutput reports whether the streams a partial read would return have output past the cursors.
+// Must be called with tracker.Mutex held.
+func hasNewOutput(tracker *ProcessTracker, streams string, combine bool) bool {
+	wantStdout := streams == "stdout" || streams == "both" || tracker.CombineOutput || combine
+	wantStderr := (streams == "stderr" || streams == "both" || combine) && !tracker.CombineOutput && tracker.StderrBuffer != nil
+
+	if wantStdout && tracker.StdoutBuffer.TotalBytes() > tracker.StdoutCursor {
+		return true
+	}
+	return wantStderr && tracker.StderrBuffer.TotalBytes() > tracker.StderrCursor
 }
 
 func handleGetPartialProcessOutput(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -1169,32 +2355,87 @@ func handleGetPartialProcessOutput(ctx context.Context, request mcp.CallToolRequ
 		return mcp.NewToolResultError("Missing or invalid 'process_id' argument"), nil
 	}
 
-	streams := getStringArg(request, "streams", "both")
+	streams := getStringArg(request, "streams", defaultOutputStreams)
 	maxLines := getIntArg(request, "max_lines", -1)
 	filters := getFiltersArg(request, "filters")
 
+	// Expand a named preset ahead of any explicit filters
+	preset := getStringArg(request, "preset", "")
+	if preset != "" {
+		presetFilters, err := resolveFilterPreset(preset)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		filters = append(presetFilters, filters...)
+	}
+
 	// Handle delay with validation
 	delayMs := getInt64Arg(request, "delay", 0)
 	if delayMs > MaxOutputDelay {
-		return mcp.NewToolResultError(fmt.Sprintf("Delay cannot exceed %d milliseconds (2 minutes)", MaxOutputDelay)), nil
+		return mcp.NewToolResultError(fmt.Sprintf("Delay cannot exceed %d milliseconds (%s)", MaxOutputDelay, msDuration(MaxOutputDelay))), nil
 	}
 	if delayMs < 0 {
 		return mcp.NewToolResultError("Delay cannot be negative"), nil
 	}
 	delay := time.Duration(delayMs) * time.Millisecond
 
+	sinceMsAgo := getInt64Arg(request, "since_ms_ago", 0)
+	if sinceMsAgo < 0 {
+		return mcp.NewToolResultError("since_ms_ago cannot be negative"), nil
+	}
+
+	skipIfNoNew := getBoolArg(request, "skip_if_no_new", false)
+
+	format := getStringArg(request, "format", "text")
+	if format != "text" && format != "lines" {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid format '%s' (use 'text' or 'lines')", format)), nil
+	}
+
+	tailBytes := getIntArg(request, "tail_bytes", 0)
+	if tailBytes < 0 {
+		return mcp.NewToolResultError("tail_bytes cannot be negative"), nil
+	}
+	omitted := map[string]TailOmission{}
+	tail := func(stream, content string) string {
+		content, omission := tailOnLineBoundary(content, tailBytes)
+		if omission.Bytes > 0 {
+			omitted[stream] = omission
+		}
+		return content
+	}
+
 	tracker, exists := registry.getProcess(processID)
 	if !exists {
 		return mcp.NewToolResultError(fmt.Sprintf("Process %s not found", processID)), nil
 	}
+	combine := combineArg(request, streams, tracker)
+	gapThreshold, errResult := gapMarkerArg(request, combine)
+	if errResult != nil {
+		return errResult, nil
+	}
 
 	// Wait with smart delay (returns early if process terminates)
 	if err := waitWithSmartDelay(ctx, tracker, delay); err != nil {
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
+	// Filters run after the lock is released so a slow filter never blocks the process
 	tracker.Mutex.Lock()
-	defer tracker.Mutex.Unlock()
+	unlock := sync.OnceFunc(tracker.Mutex.Unlock)
+	defer unlock()
+
+	// Polling loops get a compact marker instead of an empty payload while nothing happens
+	if skipIfNoNew && sinceMsAgo == 0 && (tracker.Status == StatusRunning || tracker.Status == StatusPending) &&
+		!hasNewOutput(tracker, streams, combine) {
+		resultBytes, _ := json.Marshal(&OutputResponse{
+			ProcessID:    processID,
+			StdoutCursor: tracker.StdoutCursor,
+			StderrCursor: tracker.StderrCursor,
+			Status:       tracker.Status,
+			NoNewOutput:  true,
+		})
+		return mcp.NewToolResultText(string(resultBytes)), nil
+	}
 
 	response := &OutputResponse{
 		ProcessID:    processID,
@@ -1205,92 +2446,219 @@ func handleGetPartialProcessOutput(ctx context.Context, request mcp.CallToolRequ
 		StartTime:    &tracker.StartTime,
 		EndTime:      tracker.EndTime,
 		Duration:     tracker.Duration,
+		Preset:       preset,
+		TailOmitted:  omitted,
+	}
+	if discarded := tracker.discardedOutputBytes(); discarded > 0 {
+		response.OutputTruncated = true
+		response.DiscardedBytes = discarded
 	}
+	response.PartialLine = tracker.endsInPartialLine()
 
-	if tracker.CombineOutput {
-		// When output is combined, everything is in StdoutBuffer
-		if streams == "stderr" {
-			// Special case: user wants stderr but output is combined
-			return mcp.NewToolResultError("Process has combined output - stderr not available separately. Use 'stdout' or 'both' streams."), nil
+	// Read-time combine: merge the separate streams chronologically using line timestamps
+	var readStdout, readStderr bool
+	if combine && !tracker.CombineOutput {
+		var since time.Time
+		stdoutCursor, stderrCursor := tracker.StdoutCursor, tracker.StderrCursor
+		if sinceMsAgo > 0 {
+			since = time.Now().Add(-time.Duration(sinceMsAgo) * time.Millisecond)
+			stdoutCursor, stderrCursor = 0, 0
 		}
 
-		// Get combined output from StdoutBuffer
-		stdout := extractNewContentFromRingBuffer(tracker.StdoutBuffer, tracker.StdoutCursor, maxLines)
-
-		// Apply filters if provided
-		if len(filters) > 0 {
-			filteredOutput, filterErr := filterOutput(stdout, filters)
-			if filterErr != nil {
-				// Return warning but still include original output
-				response.Stdout = fmt.Sprintf("FILTER WARNING: %v\n\n%s", filterErr, stdout)
-			} else {
-				response.Stdout = filteredOutput
-			}
-		} else {
-			response.Stdout = stdout
+		merged, stdoutEnd, stderrEnd, ok := readCombinedOutput(tracker, stdoutCursor, stderrCursor, since, gapThreshold)
+		if !ok {
+			return mcp.NewToolResultError("combine requires line timestamps - spawn the process with timestamp_lines=true"), nil
 		}
+		response.Stdout = limitLines(tail("stdout", merged), maxLines)
+		response.Combined = true
+		readStdout = true
 
-		response.StdoutCursor = tracker.StdoutBuffer.TotalBytes()
+		// Time-window reads leave the cursors unchanged
+		if sinceMsAgo == 0 {
+			tracker.StdoutCursor, tracker.StderrCursor = stdoutEnd, stderrEnd
+			response.StdoutCursor, response.StderrCursor = stdoutEnd, stderrEnd
+		}
+	} else if sinceMsAgo > 0 {
+		// Time-window read: return recent lines without touching the cursors
+		if tracker.CombineOutput && streams == "stderr" {
+			return mcp.NewToolResultError("Process has combined output - stderr not available separately. Use 'stdout' or 'both' streams."), nil
+		}
+
+		since := time.Now().Add(-time.Duration(sinceMsAgo) * time.Millisecond)
+		if streams == "stdout" || streams == "both" {
+			stdout, ok := tracker.StdoutBuffer.GetContentSince(since)
+			if !ok {
+				return mcp.NewToolResultError("since_ms_ago requires line timestamps - spawn the process with timestamp_lines=true"), nil
+			}
+			response.Stdout = limitLines(tail("stdout", stdout), maxLines)
+			readStdout = true
+		}
+		if (streams == "stderr" || streams == "both") && tracker.StderrBuffer != nil {
+			stderr, _ := tracker.StderrBuffer.GetContentSince(since)
+			response.Stderr = limitLines(tail("stderr", stderr), maxLines)
+			readStderr = true
+		}
+	} else if tracker.CombineOutput {
+		// When output is combined, everything is in StdoutBuffer
+		if streams == "stderr" {
+			// Special case: user wants stderr but output is combined
+			return mcp.NewToolResultError("Process has combined output - stderr not available separately. Use 'stdout' or 'both' streams."), nil
+		}
+
+		// Get combined output from StdoutBuffer
+		response.Stdout = limitLines(tail("stdout", tracker.StdoutBuffer.GetContentFromCursor(tracker.StdoutCursor)), maxLines)
+		readStdout = true
+
+		response.StdoutCursor = tracker.StdoutBuffer.TotalBytes()
 		tracker.StdoutCursor = response.StdoutCursor
 	} else {
 		// Separate output streams (original behavior)
 		if streams == "stdout" || streams == "both" {
-			stdout := extractNewContentFromRingBuffer(tracker.StdoutBuffer, tracker.StdoutCursor, maxLines)
-
-			// Apply filters to stdout if provided
-			if len(filters) > 0 {
-				filteredOutput, filterErr := filterOutput(stdout, filters)
-				if filterErr != nil {
-					response.Stdout = fmt.Sprintf("FILTER WARNING: %v\n\n%s", filterErr, stdout)
-				} else {
-					response.Stdout = filteredOutput
-				}
-			} else {
-				response.Stdout = stdout
-			}
+			response.Stdout = limitLines(tail("stdout", tracker.StdoutBuffer.GetContentFromCursor(tracker.StdoutCursor)), maxLines)
+			readStdout = true
 
 			response.StdoutCursor = tracker.StdoutBuffer.TotalBytes()
 			tracker.StdoutCursor = response.StdoutCursor
 		}
 
 		if streams == "stderr" || streams == "both" {
-			stderr := extractNewContentFromRingBuffer(tracker.StderrBuffer, tracker.StderrCursor, maxLines)
-
-			// Apply filters to stderr if provided
-			if len(filters) > 0 {
-				filteredOutput, filterErr := filterOutput(stderr, filters)
-				if filterErr != nil {
-					response.Stderr = fmt.Sprintf("FILTER WARNING: %v\n\n%s", filterErr, stderr)
-				} else {
-					response.Stderr = filteredOutput
-				}
-			} else {
-				response.Stderr = stderr
-			}
+			response.Stderr = limitLines(tail("stderr", tracker.StderrBuffer.GetContentFromCursor(tracker.StderrCursor)), maxLines)
+			readStderr = true
 
 			response.StderrCursor = tracker.StderrBuffer.TotalBytes()
 			tracker.StderrCursor = response.StderrCursor
 		}
 	}
 
+	unlock()
+	filterOutputResponse(response, filters, readStdout, readStderr)
+
+	if format == "lines" {
+		formatOutputLines(response)
+	}
+
 	resultBytes, _ := json.Marshal(response)
 	return mcp.NewToolResultText(string(resultBytes)), nil
 }
 
-func extractNewContentFromRingBuffer(buffer *RingBuffer, cursor int64, maxLines int) string {
-	newContent := buffer.GetContentFromCursor(cursor)
-	if maxLines > 0 && newContent != "" {
-		lines := strings.Split(newContent, "\n")
+// limitLines keeps the first maxLines lines of content (maxLines <= 0 means no limit)
+func limitLines(content string, maxLines int) string {
+	if maxLines > 0 && content != "" {
+		lines := strings.Split(content, "\n")
 		if len(lines) > maxLines {
 			lines = lines[:maxLines]
-			newContent = strings.Join(lines, "\n")
-			if !strings.HasSuffix(newContent, "\n") && len(lines) > 0 {
-				newContent += "\n"
+			content = strings.Join(lines, "\n")
+			if !strings.HasSuffix(content, "\n") && len(lines) > 0 {
+				content += "\n"
+			}
+		}
+	}
+
+	return content
+}
+
+// TailOmission describes the output that tail_bytes left out of a stream
+type TailOmission struct {
+	Bytes            int  `json:"bytes"`
+	Lines            int  `json:"lines"`                        // Complete lines dropped
+	PartialFirstLine bool `json:"partial_first_line,omitempty"` // The last line alone exceeded tail_bytes and was cut
+}
+
+// tailOnLineBoundary keeps roughly the last tailBytes bytes of content, starting at a line boundary.
+// If the last line alone is longer than tailBytes, its end is returned (cut on a UTF-8 boundary).
+// tailBytes <= 0 keeps everything.
+func tailOnLineBoundary(content string, tailBytes int) (string, TailOmission) {
+	if tailBytes <= 0 || len(content) <= tailBytes {
+		return content, TailOmission{}
+	}
+
+	start := len(content) - tailBytes
+	partial := false
+	if content[start-1] != '\n' {
+		if newline := strings.IndexByte(content[start:], '\n'); newline >= 0 && start+newline+1 < len(content) {
+			start += newline + 1
+		} else {
+			partial = true
+			for start < len(content) && !utf8.RuneStart(content[start]) {
+				start++
 			}
 		}
 	}
 
-	return newContent
+	return content[start:], TailOmission{
+		Bytes:            start,
+		Lines:            strings.Count(content[:start], "\n"),
+		PartialFirstLine: partial,
+	}
+}
+
+// splitOutputLines splits output into lines without their \n or \r\n terminators.
+// A trailing newline ends the last line rather than starting an empty one.
+func splitOutputLines(content string) []string {
+	if content == "" {
+		return []string{}
+	}
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return lines
+}
+
+// formatOutputLines replaces stdout/stderr with stdout_lines/stderr_lines (format=lines)
+func formatOutputLines(response *OutputResponse) {
+	response.StdoutLines = splitOutputLines(response.Stdout)
+	response.StderrLines = splitOutputLines(response.Stderr)
+	response.Stdout, response.Stderr = "", ""
+}
+
+// compressOutputResponse replaces stdout/stderr with base64(gzip(content)) and records the sizes
+func compressOutputResponse(response *OutputResponse) error {
+	response.OriginalBytes = len(response.Stdout) + len(response.Stderr)
+
+	for _, stream := range []*string{&response.Stdout, &response.Stderr} {
+		if *stream == "" {
+			continue
+		}
+
+		var compressed bytes.Buffer
+		writer := gzip.NewWriter(&compressed)
+		if _, err := writer.Write([]byte(*stream)); err != nil {
+			return err
+		}
+		if err := writer.Close(); err != nil {
+			return err
+		}
+
+		response.CompressedBytes += compressed.Len()
+		*stream = base64.StdEncoding.EncodeToString(compressed.Bytes())
+	}
+
+	response.Compressed = true
+	return nil
+}
+
+// applyOutputFilters runs content through the filter pipeline, keeping the original output with a warning on failure
+func applyOutputFilters(content string, filters [][]string) string {
+	if len(filters) == 0 {
+		return content
+	}
+	filteredOutput, filterErr := filterOutput(content, filters)
+	if filterErr != nil {
+		return fmt.Sprintf("FILTER WARNING: %v\n\n%s", filterErr, content)
+	}
+	return filteredOutput
+}
+
+// filterOutputResponse applies the filters to the streams that were read. The caller must not
+// hold tracker.Mutex, since a filter can wait for a slot and run for up to filterTimeout.
+func filterOutputResponse(response *OutputResponse, filters [][]string, stdout, stderr bool) {
+	if stdout {
+		response.Stdout = applyOutputFilters(response.Stdout, filters)
+	}
+	if stderr {
+		response.Stderr = applyOutputFilters(response.Stderr, filters)
+	}
 }
 
 func handleGetFullProcessOutput(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -1299,14 +2667,50 @@ func handleGetFullProcessOutput(ctx context.Context, request mcp.CallToolRequest
 		return mcp.NewToolResultError("Missing or invalid 'process_id' argument"), nil
 	}
 
-	streams := getStringArg(request, "streams", "both")
+	streams := getStringArg(request, "streams", defaultOutputStreams)
 	maxLines := getIntArg(request, "max_lines", -1)
 	filters := getFiltersArg(request, "filters")
 
+	compress := getStringArg(request, "compress", "none")
+	if compress != "none" && compress != "gzip" {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid compress value '%s' (use 'none' or 'gzip')", compress)), nil
+	}
+
+	format := getStringArg(request, "format", "text")
+	if format != "text" && format != "lines" {
+		return mcp.NewToolResultError(fmt.Sprintf("Invalid format '%s' (use 'text' or 'lines')", format)), nil
+	}
+
+	tailBytes := getIntArg(request, "tail_bytes", 0)
+	if tailBytes < 0 {
+		return mcp.NewToolResultError("tail_bytes cannot be negative"), nil
+	}
+	omitted := map[string]TailOmission{}
+	tail := func(stream, content string) string {
+		content, omission := tailOnLineBoundary(content, tailBytes)
+		if omission.Bytes > 0 {
+			omitted[stream] = omission
+		}
+		return content
+	}
+	if format == "lines" && compress == "gzip" {
+		return mcp.NewToolResultError("format 'lines' cannot be combined with compress 'gzip'"), nil
+	}
+
+	// Expand a named preset ahead of any explicit filters
+	preset := getStringArg(request, "preset", "")
+	if preset != "" {
+		presetFilters, err := resolveFilterPreset(preset)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		filters = append(presetFilters, filters...)
+	}
+
 	// Handle delay with validation
 	delayMs := getInt64Arg(request, "delay", 0)
 	if delayMs > MaxOutputDelay {
-		return mcp.NewToolResultError(fmt.Sprintf("Delay cannot exceed %d milliseconds (2 minutes)", MaxOutputDelay)), nil
+		return mcp.NewToolResultError(fmt.Sprintf("Delay cannot exceed %d milliseconds (%s)", MaxOutputDelay, msDuration(MaxOutputDelay))), nil
 	}
 	if delayMs < 0 {
 		return mcp.NewToolResultError("Delay cannot be negative"), nil
@@ -1314,8 +2718,17 @@ func handleGetFullProcessOutput(ctx context.Context, request mcp.CallToolRequest
 	delay := time.Duration(delayMs) * time.Millisecond
 
 	tracker, exists := registry.getProcess(processID)
+	removed := false
 	if !exists {
-		return mcp.NewToolResultError(fmt.Sprintf("Process %s not found", processID)), nil
+		// A process removed in the last minute can still be read one final time
+		if tracker, removed = removedProcesses.get(processID); !removed {
+			return mcp.NewToolResultError(fmt.Sprintf("Process %s not found", processID)), nil
+		}
+	}
+	combine := combineArg(request, streams, tracker)
+	gapThreshold, errResult := gapMarkerArg(request, combine)
+	if errResult != nil {
+		return errResult, nil
 	}
 
 	// Wait with smart delay (returns early if process terminates)
@@ -1323,8 +2736,10 @@ func handleGetFullProcessOutput(ctx context.Context, request mcp.CallToolRequest
 		return mcp.NewToolResultError(err.Error()), nil
 	}
 
+	// Filters run after the lock is released so a slow filter never blocks the process
 	tracker.Mutex.Lock()
-	defer tracker.Mutex.Unlock()
+	unlock := sync.OnceFunc(tracker.Mutex.Unlock)
+	defer unlock()
 
 	// Handle cursor values properly for combined vs separate output
 	var stdoutCursor, stderrCursor int64
@@ -1347,9 +2762,26 @@ func handleGetFullProcessOutput(ctx context.Context, request mcp.CallToolRequest
 		StartTime:    &tracker.StartTime,
 		EndTime:      tracker.EndTime,
 		Duration:     tracker.Duration,
-	}
-
-	if tracker.CombineOutput {
+		Preset:       preset,
+		TailOmitted:  omitted,
+		Removed:      removed,
+	}
+	if discarded := tracker.discardedOutputBytes(); discarded > 0 {
+		response.OutputTruncated = true
+		response.DiscardedBytes = discarded
+	}
+
+	var readStdout, readStderr bool
+	if combine && !tracker.CombineOutput {
+		// Read-time combine: merge the separate streams chronologically using line timestamps
+		merged, _, _, ok := readCombinedOutput(tracker, 0, 0, time.Time{}, gapThreshold)
+		if !ok {
+			return mcp.NewToolResultError("combine requires line timestamps - spawn the process with timestamp_lines=true"), nil
+		}
+		response.Stdout = limitLines(tail("stdout", merged), maxLines)
+		response.Combined = true
+		readStdout = true
+	} else if tracker.CombineOutput {
 		// When output is combined, everything is in StdoutBuffer
 		if streams == "stderr" {
 			// Special case: user wants stderr but output is combined
@@ -1357,7 +2789,7 @@ func handleGetFullProcessOutput(ctx context.Context, request mcp.CallToolRequest
 		}
 
 		// Get combined output from StdoutBuffer
-		fullStdout := tracker.StdoutBuffer.GetContent()
+		fullStdout := tail("stdout", tracker.StdoutBuffer.GetContent())
 		if maxLines > 0 && fullStdout != "" {
 			lines := strings.Split(fullStdout, "\n")
 			if len(lines) > maxLines {
@@ -1368,23 +2800,12 @@ func handleGetFullProcessOutput(ctx context.Context, request mcp.CallToolRequest
 				}
 			}
 		}
-
-		// Apply filters if provided
-		if len(filters) > 0 {
-			filteredOutput, filterErr := filterOutput(fullStdout, filters)
-			if filterErr != nil {
-				// Return warning but still include original output
-				response.Stdout = fmt.Sprintf("FILTER WARNING: %v\n\n%s", filterErr, fullStdout)
-			} else {
-				response.Stdout = filteredOutput
-			}
-		} else {
-			response.Stdout = fullStdout
-		}
+		response.Stdout = fullStdout
+		readStdout = true
 	} else {
 		// Separate output streams (original behavior)
 		if streams == "stdout" || streams == "both" {
-			fullStdout := tracker.StdoutBuffer.GetContent()
+			fullStdout := tail("stdout", tracker.StdoutBuffer.GetContent())
 			if maxLines > 0 && fullStdout != "" {
 				lines := strings.Split(fullStdout, "\n")
 				if len(lines) > maxLines {
@@ -1395,22 +2816,12 @@ func handleGetFullProcessOutput(ctx context.Context, request mcp.CallToolRequest
 					}
 				}
 			}
-
-			// Apply filters to stdout if provided
-			if len(filters) > 0 {
-				filteredOutput, filterErr := filterOutput(fullStdout, filters)
-				if filterErr != nil {
-					response.Stdout = fmt.Sprintf("FILTER WARNING: %v\n\n%s", filterErr, fullStdout)
-				} else {
-					response.Stdout = filteredOutput
-				}
-			} else {
-				response.Stdout = fullStdout
-			}
+			response.Stdout = fullStdout
+			readStdout = true
 		}
 
 		if streams == "stderr" || streams == "both" {
-			fullStderr := tracker.StderrBuffer.GetContent()
+			fullStderr := tail("stderr", tracker.StderrBuffer.GetContent())
 			if maxLines > 0 && fullStderr != "" {
 				lines := strings.Split(fullStderr, "\n")
 				if len(lines) > maxLines {
@@ -1421,18 +2832,21 @@ func handleGetFullProcessOutput(ctx context.Context, request mcp.CallToolRequest
 					}
 				}
 			}
+			response.Stderr = fullStderr
+			readStderr = true
+		}
+	}
 
-			// Apply filters to stderr if provided
-			if len(filters) > 0 {
-				filteredOutput, filterErr := filterOutput(fullStderr, filters)
-				if filterErr != nil {
-					response.Stderr = fmt.Sprintf("FILTER WARNING: %v\n\n%s", filterErr, fullStderr)
-				} else {
-					response.Stderr = filteredOutput
-				}
-			} else {
-				response.Stderr = fullStderr
-			}
+	unlock()
+	filterOutputResponse(response, filters, readStdout, readStderr)
+
+	if format == "lines" {
+		formatOutputLines(response)
+	}
+
+	if compress == "gzip" {
+		if err := compressOutputResponse(response); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to compress output: %v", err)), nil
 		}
 	}
 
@@ -1440,6 +2854,25 @@ func handleGetFullProcessOutput(ctx context.Context, request mcp.CallToolRequest
 	return mcp.NewToolResultText(string(resultBytes)), nil
 }
 
+// writeProcessInput writes input to a running process's stdin (send_process_input, broadcast_input)
+func writeProcessInput(tracker *ProcessTracker, input string) error {
+	tracker.Mutex.Lock()
+	defer tracker.Mutex.Unlock()
+
+	if tracker.Status != StatusRunning {
+		return fmt.Errorf("Process %s is not running (status: %s)", tracker.ID, tracker.Status)
+	}
+
+	if tracker.StdinWriter == nil {
+		return fmt.Errorf("Process stdin is not available")
+	}
+
+	if _, err := tracker.StdinWriter.Write([]byte(input)); err != nil {
+		return fmt.Errorf("Failed to write to process stdin: %v", err)
+	}
+	return nil
+}
+
 func handleSendProcessInput(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	processID, err := request.RequireString("process_id")
 	if err != nil {
@@ -1458,26 +2891,14 @@ func handleSendProcessInput(ctx context.Context, request mcp.CallToolRequest) (*
 		return mcp.NewToolResultError(fmt.Sprintf("Process %s not found", processID)), nil
 	}
 
-	tracker.Mutex.Lock()
-	defer tracker.Mutex.Unlock()
-
-	if tracker.Status != StatusRunning {
-		return mcp.NewToolResultError(fmt.Sprintf("Process %s is not running (status: %s)", processID, tracker.Status)), nil
-	}
-
-	if tracker.StdinWriter == nil {
-		return mcp.NewToolResultError("Process stdin is not available"), nil
-	}
-
 	// Prepare the final input to send
 	finalInput := input
 	if autoNewline {
 		finalInput = input + "\n"
 	}
 
-	_, err = tracker.StdinWriter.Write([]byte(finalInput))
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Failed to write to process stdin: %v", err)), nil
+	if err := writeProcessInput(tracker, finalInput); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
 	}
 
 	// Prepare result message
@@ -1499,12 +2920,222 @@ func handleSendProcessInput(ctx context.Context, request mcp.CallToolRequest) (*
 	return mcp.NewToolResultText(string(resultBytes)), nil
 }
 
+// handleRunWithStdin writes input to a running process, closes its stdin, waits for it to exit,
+// and returns the complete output in one call (the "pipe data through a command" pattern)
+func handleRunWithStdin(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	processID, err := request.RequireString("process_id")
+	if err != nil {
+		return mcp.NewToolResultError("Missing or invalid 'process_id' argument"), nil
+	}
+
+	input := getStringArg(request, "input", "")
+
+	timeoutMs := getInt64Arg(request, "timeout", DefaultRunWithStdinTimeout)
+	if timeoutMs > MaxOutputDelay {
+		return mcp.NewToolResultError(fmt.Sprintf("Timeout cannot exceed %d milliseconds (%s)", MaxOutputDelay, msDuration(MaxOutputDelay))), nil
+	}
+	if timeoutMs <= 0 {
+		return mcp.NewToolResultError("Timeout must be positive"), nil
+	}
+
+	tracker, exists := registry.getProcess(processID)
+	if !exists {
+		return mcp.NewToolResultError(fmt.Sprintf("Process %s not found", processID)), nil
+	}
+
+	tracker.Mutex.Lock()
+	if tracker.Status != StatusRunning {
+		tracker.Mutex.Unlock()
+		return mcp.NewToolResultError(fmt.Sprintf("Process %s is not running (status: %s)", processID, tracker.Status)), nil
+	}
+	if tracker.StdinWriter == nil {
+		tracker.Mutex.Unlock()
+		return mcp.NewToolResultError("Process stdin is not available"), nil
+	}
+
+	if _, err := tracker.StdinWriter.Write([]byte(input)); err != nil {
+		tracker.Mutex.Unlock()
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to write to process stdin: %v", err)), nil
+	}
+
+	// Send EOF - the process sees end of input and can finish
+	closeErr := tracker.StdinWriter.Close()
+	tracker.StdinWriter = nil
+	tracker.Mutex.Unlock()
+	if closeErr != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to close process stdin: %v", closeErr)), nil
+	}
+
+	// Wait for exit (returns early as soon as the process terminates)
+	if err := waitWithSmartDelay(ctx, tracker, time.Duration(timeoutMs)*time.Millisecond); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	tracker.Mutex.RLock()
+	defer tracker.Mutex.RUnlock()
+
+	result := map[string]any{
+		"process_id": processID,
+		"status":     tracker.Status,
+		"exit_code":  tracker.ExitCode,
+		"timed_out":  tracker.Status == StatusRunning,
+		"bytes_sent": len(input),
+		"stdout":     tracker.StdoutBuffer.GetContent(),
+	}
+	if tracker.StderrBuffer != nil {
+		result["stderr"] = tracker.StderrBuffer.GetContent()
+	}
+	if tracker.Duration != nil {
+		result["duration"] = tracker.Duration.String()
+	}
+
+	resultBytes, _ := json.Marshal(result)
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// handlePipeFileToProcess streams a server-side file into a running process's stdin in chunks,
+// so large inputs never have to pass through JSON or be held in memory
+func handlePipeFileToProcess(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	processID, err := request.RequireString("process_id")
+	if err != nil {
+		return mcp.NewToolResultError("Missing or invalid 'process_id' argument"), nil
+	}
+
+	path, err := request.RequireString("path")
+	if err != nil {
+		return mcp.NewToolResultError("Missing or invalid 'path' argument"), nil
+	}
+
+	closeStdin := getBoolArg(request, "close_stdin", false)
+
+	maxBytes := getInt64Arg(request, "max_bytes", MaxPipeFileSize)
+	if maxBytes <= 0 || maxBytes > MaxPipeFileSize {
+		return mcp.NewToolResultError(fmt.Sprintf("max_bytes must be between 1 and %d", int64(MaxPipeFileSize))), nil
+	}
+
+	timeoutMs := getInt64Arg(request, "timeout", DefaultPipeFileTimeout)
+	if timeoutMs <= 0 || timeoutMs > MaxPipeFileTimeout {
+		return mcp.NewToolResultError(fmt.Sprintf("Timeout must be between 1 and %d milliseconds (10 minutes)", MaxPipeFileTimeout)), nil
+	}
+
+	// Files are confined like working directories when --allowed-workdir is set
+	if err := spawnPolicy.CheckPath(path); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to open file: %v", err)), nil
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to stat file: %v", err)), nil
+	}
+	if !info.Mode().IsRegular() {
+		return mcp.NewToolResultError(fmt.Sprintf("%s is not a regular file", path)), nil
+	}
+	if info.Size() > maxBytes {
+		return mcp.NewToolResultError(fmt.Sprintf("File is %d bytes, exceeding max_bytes (%d)", info.Size(), maxBytes)), nil
+	}
+
+	tracker, exists := registry.getProcess(processID)
+	if !exists {
+		return mcp.NewToolResultError(fmt.Sprintf("Process %s not found", processID)), nil
+	}
+
+	// Take the writer and release the lock - streaming can take a while
+	tracker.Mutex.RLock()
+	status := tracker.Status
+	stdin := tracker.StdinWriter
+	tracker.Mutex.RUnlock()
+
+	if status != StatusRunning {
+		return mcp.NewToolResultError(fmt.Sprintf("Process %s is not running (status: %s)", processID, status)), nil
+	}
+	if stdin == nil {
+		return mcp.NewToolResultError("Process stdin is not available"), nil
+	}
+
+	// Bound blocked writes (a process that stops reading) as well as the overall copy
+	deadline := time.Now().Add(time.Duration(timeoutMs) * time.Millisecond)
+	if d, ok := stdin.(interface{ SetWriteDeadline(time.Time) error }); ok {
+		d.SetWriteDeadline(deadline)
+		defer d.SetWriteDeadline(time.Time{})
+	}
+
+	var written int64
+	reader := io.LimitReader(file, maxBytes) // The file may grow after Stat
+	chunk := make([]byte, PipeFileChunkSize)
+	for {
+		if ctx.Err() != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("request canceled after writing %d bytes", written)), nil
+		}
+		if time.Now().After(deadline) {
+			return mcp.NewToolResultError(fmt.Sprintf("Timed out after writing %d of %d bytes", written, info.Size())), nil
+		}
+
+		n, readErr := reader.Read(chunk)
+		if n > 0 {
+			w, writeErr := stdin.Write(chunk[:n])
+			written += int64(w)
+			if writeErr != nil {
+				if errors.Is(writeErr, os.ErrDeadlineExceeded) {
+					return mcp.NewToolResultError(fmt.Sprintf("Timed out after writing %d of %d bytes (process is not reading stdin)", written, info.Size())), nil
+				}
+				return mcp.NewToolResultError(fmt.Sprintf("Failed to write to process stdin after %d bytes: %v", written, writeErr)), nil
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to read file after %d bytes: %v", written, readErr)), nil
+		}
+	}
+
+	stdinClosed := false
+	if closeStdin {
+		tracker.Mutex.Lock()
+		if tracker.StdinWriter == stdin {
+			if err := stdin.Close(); err != nil {
+				tracker.Mutex.Unlock()
+				return mcp.NewToolResultError(fmt.Sprintf("Failed to close process stdin: %v", err)), nil
+			}
+			tracker.StdinWriter = nil
+			stdinClosed = true
+		}
+		tracker.Mutex.Unlock()
+	}
+
+	result := map[string]any{
+		"process_id":   processID,
+		"status":       "input_sent",
+		"path":         path,
+		"bytes_sent":   written,
+		"stdin_closed": stdinClosed,
+	}
+
+	resultBytes, _ := json.Marshal(result)
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// MaxLastLinePreview caps the last_line preview in list_processes, in characters
+const MaxLastLinePreview = 200
+
 func handleListProcesses(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	processes := registry.getAllProcesses()
+	labelSelector := getStringMapArg(request, "labels")
+	includeLastLine := getBoolArg(request, "include_last_line", false)
 
 	result := make([]map[string]any, 0, len(processes))
 	for _, tracker := range processes {
 		tracker.Mutex.RLock()
+		if !matchesLabels(tracker, labelSelector) {
+			tracker.Mutex.RUnlock()
+			continue
+		}
 		processInfo := map[string]any{
 			"id":             tracker.ID,
 			"name":           tracker.Name,
@@ -1523,6 +3154,27 @@ func handleListProcesses(ctx context.Context, request mcp.CallToolRequest) (*mcp
 		if tracker.ExitCode != nil {
 			processInfo["exit_code"] = *tracker.ExitCode
 		}
+		if tracker.GitCommit != "" {
+			processInfo["git_branch"] = tracker.GitBranch
+			processInfo["git_commit"] = tracker.GitCommit
+		}
+		if tracker.LastError != "" {
+			processInfo["last_error"] = tracker.LastError
+		}
+		if len(tracker.Labels) > 0 {
+			processInfo["labels"] = tracker.Labels
+		}
+		if tracker.Adopted {
+			processInfo["adopted"] = true
+		}
+		if includeLastLine {
+			processInfo["last_line"] = truncateText(tracker.StdoutBuffer.LastLine(), MaxLastLinePreview)
+			if tracker.StderrBuffer != nil {
+				if line := tracker.StderrBuffer.LastLine(); line != "" {
+					processInfo["last_stderr_line"] = truncateText(line, MaxLastLinePreview)
+				}
+			}
+		}
 		tracker.Mutex.RUnlock()
 		result = append(result, processInfo)
 	}
@@ -1531,12 +3183,115 @@ func handleListProcesses(ctx context.Context, request mcp.CallToolRequest) (*mcp
 	return mcp.NewToolResultText(string(resultBytes)), nil
 }
 
-func handleKillProcess(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+func handleResizeProcessBuffer(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	processID, err := request.RequireString("process_id")
+	if err != nil {
+		return mcp.NewToolResultError("Missing or invalid 'process_id' argument"), nil
+	}
+
+	bufferSize := getInt64Arg(request, "buffer_size", 0)
+	if bufferSize <= 0 {
+		return mcp.NewToolResultError("buffer_size must be a positive number of bytes"), nil
+	}
+	if bufferSize > MaxBufferSize {
+		return mcp.NewToolResultError(fmt.Sprintf("buffer_size cannot exceed %d bytes (%s)", MaxBufferSize, formatBytes(MaxBufferSize))), nil
+	}
+
+	tracker, exists := registry.getProcess(processID)
+	if !exists {
+		return mcp.NewToolResultError(fmt.Sprintf("Process %s not found", processID)), nil
+	}
+
+	// The process's own output is replaced by the resized buffers
+	used := totalBufferedBytes(registry.getAllProcesses())
+	tracker.Mutex.RLock()
+	combineOutput := tracker.StderrBuffer == nil
+	used -= trackerBufferedBytes(tracker)
+	tracker.Mutex.RUnlock()
+	if err := bufferBudget.admit(used, bufferSize, combineOutput); err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	tracker.Mutex.Lock()
+	previousSize := tracker.BufferSize
+	tracker.BufferSize = bufferSize
+	tracker.StdoutBuffer.Resize(bufferSize)
+	if tracker.StderrBuffer != nil {
+		tracker.StderrBuffer.Resize(bufferSize)
+	}
+	tracker.Mutex.Unlock()
+
+	LogInfo("Process", fmt.Sprintf("Buffer resized: %s → %s", formatBytes(previousSize), formatBytes(bufferSize)),
+		fmt.Sprintf("ID: %s", processID))
+
+	result := map[string]any{
+		"process_id":           processID,
+		"buffer_size":          bufferSize,
+		"previous_buffer_size": previousSize,
+		"stdout_size":          tracker.StdoutBuffer.Len(),
+	}
+	if tracker.StderrBuffer != nil {
+		result["stderr_size"] = tracker.StderrBuffer.Len()
+	}
+
+	resultBytes, _ := json.Marshal(result)
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+func handleReapProcesses(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	var statuses []ProcessStatus
+	if status := getStringArg(request, "status", ""); status != "" {
+		if !isTerminalStatus(ProcessStatus(status)) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid status '%s': must be completed, failed, or killed", status)), nil
+		}
+		statuses = append(statuses, ProcessStatus(status))
+	}
+
+	olderThanMs := getInt64Arg(request, "older_than_ms", 0)
+	if olderThanMs < 0 {
+		return mcp.NewToolResultError("older_than_ms must be non-negative"), nil
+	}
+
+	reaped := registry.reapProcesses(statuses, time.Duration(olderThanMs)*time.Millisecond)
+	if len(reaped) > 0 {
+		LogInfo("ProcessReap", fmt.Sprintf("Reaped %d terminated processes", len(reaped)), "")
+	}
+
+	result := map[string]any{
+		"reaped":      len(reaped),
+		"process_ids": reaped,
+	}
+	resultBytes, _ := json.Marshal(result)
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+// getWinsizeArgs reads optional cols/rows, which must be given together and fit a terminal size
+func getWinsizeArgs(request mcp.CallToolRequest) (uint16, uint16, error) {
+	cols := getIntArg(request, "cols", 0)
+	rows := getIntArg(request, "rows", 0)
+	if cols == 0 && rows == 0 {
+		return 0, 0, nil
+	}
+	if cols < 1 || cols > 65535 || rows < 1 || rows > 65535 {
+		return 0, 0, fmt.Errorf("cols and rows must both be between 1 and 65535")
+	}
+	return uint16(cols), uint16(rows), nil
+}
+
+func handleSetProcessWinsize(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	processID, err := request.RequireString("process_id")
 	if err != nil {
 		return mcp.NewToolResultError("Missing or invalid 'process_id' argument"), nil
 	}
 
+	cols, rows, err := getWinsizeArgs(request)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+	if cols == 0 {
+		return mcp.NewToolResultError("Missing 'cols' and 'rows' arguments"), nil
+	}
+
 	tracker, exists := registry.getProcess(processID)
 	if !exists {
 		return mcp.NewToolResultError(fmt.Sprintf("Process %s not found", processID)), nil
@@ -1545,10 +3300,66 @@ func handleKillProcess(ctx context.Context, request mcp.CallToolRequest) (*mcp.C
 	tracker.Mutex.Lock()
 	defer tracker.Mutex.Unlock()
 
+	tracker.Cols, tracker.Rows = cols, rows
+
+	result := map[string]any{
+		"process_id": processID,
+		"cols":       cols,
+		"rows":       rows,
+		"applied":    false,
+	}
+
+	// Only pty-backed processes have a terminal to resize; others just record the size
+	if tracker.PTY == nil {
+		result["message"] = "Process is not pty-backed - size recorded but not applied"
+	} else {
+		if err := setPTYWinsize(tracker.PTY, cols, rows); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Failed to set window size: %v", err)), nil
+		}
+		result["applied"] = true
+	}
+
+	resultBytes, _ := json.Marshal(result)
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+func handleKillAllProcesses(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	labelSelector := getStringMapArg(request, "labels")
+	killed := registry.killProcessesByLabels(labelSelector)
+
+	result := map[string]any{
+		"killed":      len(killed),
+		"process_ids": killed,
+	}
+	resultBytes, _ := json.Marshal(result)
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
+
+func handleKillProcess(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	processID, err := request.RequireString("process_id")
+	if err != nil {
+		return mcp.NewToolResultError("Missing or invalid 'process_id' argument"), nil
+	}
+
+	timeoutMs := getInt64Arg(request, "timeout_ms", DefaultKillConfirmTimeout)
+	if timeoutMs < 0 || timeoutMs > MaxKillConfirmTimeout {
+		return mcp.NewToolResultError(fmt.Sprintf("timeout_ms must be between 0 and %d", MaxKillConfirmTimeout)), nil
+	}
+
+	tracker, exists := registry.getProcess(processID)
+	if !exists {
+		return mcp.NewToolResultError(fmt.Sprintf("Process %s not found", processID)), nil
+	}
+
+	tracker.Mutex.Lock()
+
 	if tracker.Status != StatusRunning {
-		return mcp.NewToolResultError(fmt.Sprintf("Process %s is not running (status: %s)", processID, tracker.Status)), nil
+		status := tracker.Status
+		tracker.Mutex.Unlock()
+		return mcp.NewToolResultError(fmt.Sprintf("Process %s is not running (status: %s)", processID, status)), nil
 	}
 
+	signal := ""
 	if tracker.Process != nil && tracker.Process.Process != nil {
 		// Close stdin first to signal the process
 		if tracker.StdinWriter != nil {
@@ -1559,13 +3370,8 @@ func handleKillProcess(ctx context.Context, request mcp.CallToolRequest) (*mcp.C
 		}
 
 		// Kill the entire process group (Unix) or process (Windows)
-		err := terminateProcessGroup(tracker.Process.Process.Pid)
-		if err != nil {
-			// If platform-specific termination fails, use standard process.Kill()
-			if tracker.Process.Process != nil {
-				tracker.Process.Process.Kill()
-			}
-		}
+		signal, _ = terminateTracker(tracker)
+		// Marks the kill as intentional; the exit goroutine records the end once the process is reaped
 		tracker.Status = StatusKilled
 
 		// Log manual kill (SSE mode only)
@@ -1586,23 +3392,62 @@ func handleKillProcess(ctx context.Context, request mcp.CallToolRequest) (*mcp.C
 			LogInfo("Process", "Process terminated: "+tracker.Command, logMsg)
 		}
 	}
+	tracker.Mutex.Unlock()
+
+	// The lock must be released here: the exit goroutine needs it to record the exit
+	confirmed := waitForProcessExit(ctx, tracker, time.Duration(timeoutMs)*time.Millisecond)
+
+	tracker.Mutex.RLock()
+	status := tracker.Status
+	tracker.Mutex.RUnlock()
 
 	result := map[string]any{
 		"process_id": processID,
-		"status":     string(tracker.Status),
-		"message":    "Process terminated",
+		"status":     string(status),
+		"confirmed":  confirmed,
+		"signal":     signal,
+	}
+	if confirmed {
+		result["message"] = "Process terminated"
+	} else {
+		result["message"] = fmt.Sprintf("Signal sent, but the process had not exited after %dms", timeoutMs)
 	}
 
 	resultBytes, _ := json.Marshal(result)
 	return mcp.NewToolResultText(string(resultBytes)), nil
 }
 
+// waitForProcessExit polls until the exit goroutine has reaped the process (its end time is set)
+// or timeout expires. Returns whether the process is confirmed gone.
+func waitForProcessExit(ctx context.Context, tracker *ProcessTracker, timeout time.Duration) bool {
+	exited := func() bool {
+		tracker.Mutex.RLock()
+		defer tracker.Mutex.RUnlock()
+		return tracker.EndTime != nil
+	}
+
+	deadline := time.Now().Add(timeout)
+	for !exited() {
+		if !time.Now().Before(deadline) {
+			return false
+		}
+		select {
+		case <-time.After(min(time.Until(deadline), killConfirmPollInterval)):
+		case <-ctx.Done():
+			return exited()
+		}
+	}
+	return true
+}
+
 func handleGetProcessStatus(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 	processID, err := request.RequireString("process_id")
 	if err != nil {
 		return mcp.NewToolResultError("Missing or invalid 'process_id' argument"), nil
 	}
 
+	includeEnv := getBoolArg(request, "include_env", false)
+
 	tracker, exists := registry.getProcess(processID)
 	if !exists {
 		return mcp.NewToolResultError(fmt.Sprintf("Process %s not found", processID)), nil
@@ -1631,6 +3476,13 @@ func handleGetProcessStatus(ctx context.Context, request mcp.CallToolRequest) (*
 		"stdout_total":   tracker.StdoutBuffer.TotalBytes(),
 	}
 
+	// Output that no longer fits in the buffer is dropped oldest first
+	discarded := tracker.discardedOutputBytes()
+	result["output_truncated"] = discarded > 0
+	if discarded > 0 {
+		result["discarded_bytes"] = discarded
+	}
+
 	// ⏰ Add timing information for completed processes
 	if tracker.EndTime != nil {
 		result["end_time"] = tracker.EndTime.Format(time.RFC3339)
@@ -1639,6 +3491,10 @@ func handleGetProcessStatus(ctx context.Context, request mcp.CallToolRequest) (*
 		result["duration_ms"] = int64(*tracker.Duration / time.Millisecond)
 		result["duration"] = tracker.Duration.String()
 	}
+	addProcessTimings(result, tracker, time.Now())
+	if includeEnv {
+		result["env"], result["env_masked"] = maskedEnv(tracker.Env)
+	}
 
 	if tracker.CombineOutput {
 		// When output is combined, stderr info is not relevant
@@ -1656,10 +3512,78 @@ func handleGetProcessStatus(ctx context.Context, request mcp.CallToolRequest) (*
 		}
 	}
 
+	if tracker.LastError != "" {
+		result["last_error"] = tracker.LastError
+	}
+	if tracker.ExitReason != "" {
+		result["exit_reason"] = tracker.ExitReason
+	}
+	if tracker.Signal != "" {
+		result["signal"] = tracker.Signal
+	}
+	if tracker.LinePrefix != "" {
+		result["line_prefix"] = tracker.LinePrefix
+	}
+	if tracker.FlushPartial {
+		result["flush_partial"] = true
+		result["partial_line"] = tracker.endsInPartialLine()
+	}
+	if tracker.Progress != nil {
+		result["progress_regex"] = tracker.ProgressRegex
+		if progress := tracker.Progress.snapshot(); progress != nil {
+			result["progress_current"] = progress.Current
+			result["progress_total"] = progress.Total
+			result["progress_percent"] = progress.Percent
+			result["progress_updated_at"] = progress.UpdatedAt.Format(time.RFC3339)
+		}
+	}
+	if tracker.Status == StatusRunning {
+		// Defunct children usually mean a parent that stopped reaping, e.g. after daemonizing
+		if zombies, err := listZombies(); err == nil {
+			group := groupZombies(zombies, tracker)
+			result["has_zombies"] = len(group) > 0
+			if len(group) > 0 {
+				result["zombies"] = group
+				warnZombies(tracker, group)
+			}
+		}
+	}
+	if pipes := processPipes.forProcess(tracker.ID); len(pipes) > 0 {
+		result["pipes"] = pipes
+	}
+	if tracker.Redactor != nil {
+		result["redact_patterns"] = len(tracker.RedactPatterns)
+		result["redact_builtin"] = tracker.RedactBuiltin
+	}
+	if tracker.TeeStderrToLogs {
+		result["tee_stderr_to_logs"] = true
+	}
+	if len(tracker.OnExitCommand) > 0 {
+		result["on_exit_command"] = tracker.OnExitCommand
+	}
+	if tracker.OnExitResult != nil {
+		result["on_exit_result"] = tracker.OnExitResult
+	}
+	if len(tracker.Labels) > 0 {
+		result["labels"] = tracker.Labels
+	}
+	if tracker.Adopted {
+		result["adopted"] = true
+	}
+	if tracker.Credential != nil {
+		result["run_as_user"] = tracker.Credential.Username
+		result["run_as_uid"] = tracker.Credential.UID
+	}
+
 	if tracker.ExitCode != nil {
 		result["exit_code"] = *tracker.ExitCode
 	}
 
+	if tracker.GitCommit != "" {
+		result["git_branch"] = tracker.GitBranch
+		result["git_commit"] = tracker.GitCommit
+	}
+
 	resultBytes, _ := json.Marshal(result)
 	return mcp.NewToolResultText(string(resultBytes)), nil
 }
@@ -2,6 +2,8 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 	"time"
@@ -10,15 +12,57 @@ import (
 	"github.com/rivo/tview"
 )
 
+// ProcessSortColumn represents the column used to order processes within a session
+type ProcessSortColumn int
+
+const (
+	SortByTime ProcessSortColumn = iota
+	SortByStatus
+	SortByName
+	SortByPID
+)
+
+// String returns the display name of the sort column
+func (c ProcessSortColumn) String() string {
+	switch c {
+	case SortByStatus:
+		return "Status"
+	case SortByName:
+		return "Name"
+	case SortByPID:
+		return "PID"
+	default:
+		return "Time"
+	}
+}
+
+// statusSortRank orders statuses so active processes come first
+var statusSortRank = map[ProcessStatus]int{
+	StatusRunning:   0,
+	StatusPending:   1,
+	StatusFailed:    2,
+	StatusKilled:    3,
+	StatusCompleted: 4,
+}
+
+// Default column widths used until the terminal size is known
+const (
+	minNameWidth    = 15
+	minCommandWidth = 40
+)
+
 // ProcessesPageView represents the processes list page - IDIOMATIC INCREMENTAL UPDATE IMPLEMENTATION
 type ProcessesPageView struct {
 	tuiApp          *TUIApp
 	view            *tview.Flex
 	table           *tview.Table
 	statusBar       *tview.TextView
+	sortColumn      ProcessSortColumn
 	reversedSort    bool
 	lastProcessData map[string]*ProcessTracker // Cache for incremental updates
 	lastSessionData map[string][]*ProcessTracker
+	lastTableWidth  int       // Triggers a rebuild when the terminal is resized
+	lastViewed      time.Time // Processes started after this are marked NEW
 	isInitialized   bool
 }
 
@@ -31,6 +75,7 @@ func NewProcessesPageView(tuiApp *TUIApp) *ProcessesPageView {
 		reversedSort:    true, // Default to newest first
 		lastProcessData: make(map[string]*ProcessTracker),
 		lastSessionData: make(map[string][]*ProcessTracker),
+		lastViewed:      time.Now(),
 		isInitialized:   false,
 	}
 
@@ -61,7 +106,7 @@ func (p *ProcessesPageView) setupTable() {
 // setupStatusBar configures the status bar
 func (p *ProcessesPageView) setupStatusBar() {
 	p.statusBar.SetBorder(true).SetTitle(" Controls ").SetTitleAlign(tview.AlignLeft)
-	p.statusBar.SetText("[yellow]↑↓[white]: Navigate | [yellow]Enter[white]: View Details | [yellow]K[white]: Kill Process | [yellow]Del[white]: Remove Process | [yellow]R[white]: Sort | [yellow]Tab[white]: Switch Page | [yellow]Q[white]: Quit\n[grey]Pages: [yellow]1[white]: Processes | [yellow]2[white]: Notifications | [yellow]3[white]: Agents Q&A | [yellow]4[white]: Logs | [yellow]5[white]: Features[grey]")
+	p.statusBar.SetText(statusBarText(ProcessesPage))
 	p.statusBar.SetTextAlign(tview.AlignCenter)
 	p.statusBar.SetDynamicColors(true)
 }
@@ -90,6 +135,19 @@ func (p *ProcessesPageView) handleTableKeys(event *tcell.EventKey) *tcell.EventK
 		case 'r', 'R':
 			p.toggleSort()
 			return nil
+		case 's', 'S':
+			p.cycleSortColumn()
+			return nil
+		case 'x', 'X':
+			p.reapTerminatedProcesses()
+			return nil
+		case 'e', 'E':
+			p.exportTable()
+			return nil
+		case 'a', 'A':
+			p.MarkViewed()
+			p.Refresh()
+			return nil
 		}
 	}
 	return event
@@ -131,6 +189,8 @@ func (p *ProcessesPageView) killSelectedProcess() {
 	// Get the process ID from the last column
 	processIDCell := p.table.GetCell(row, 6) // ID column
 	if processIDCell == nil || processIDCell.Text == "" {
+		// Session header rows have no ID - kill the whole session instead
+		p.killSelectedSession(row)
 		return
 	}
 
@@ -162,6 +222,38 @@ func (p *ProcessesPageView) killSelectedProcess() {
 	})
 }
 
+// killSelectedSession kills every running or pending process of the session whose header is at row
+func (p *ProcessesPageView) killSelectedSession(row int) {
+	sessionName, ok := p.table.GetCell(row, 0).GetReference().(string)
+	if !ok {
+		return
+	}
+	sessionID := sessionName
+	if sessionID == "No Session" {
+		sessionID = ""
+	}
+
+	active := 0
+	for _, tracker := range registry.getAllProcesses() {
+		tracker.Mutex.RLock()
+		if tracker.SessionID == sessionID && (tracker.Status == StatusRunning || tracker.Status == StatusPending) {
+			active++
+		}
+		tracker.Mutex.RUnlock()
+	}
+	if active == 0 {
+		return
+	}
+
+	label := fmt.Sprintf("All %d running processes in session %s", active, sessionName)
+	ShowKillConfirmation(p.tuiApp.app, p.tuiApp.pages, label, func() {
+		killed := registry.killProcessesBySession(sessionID)
+		LogInfo("ProcessKill", fmt.Sprintf("Session killed by user: %s", sessionName),
+			fmt.Sprintf("Processes killed: %d", killed))
+		p.Update()
+	})
+}
+
 // performKillProcess actually kills the process
 func (p *ProcessesPageView) performKillProcess(processID string) {
 	tracker, exists := registry.getProcess(processID)
@@ -189,18 +281,12 @@ func (p *ProcessesPageView) performKillProcess(processID string) {
 	// Mark as being killed
 	tracker.Status = StatusKilled
 
-	// Get the process handle before releasing the mutex
-	process := tracker.Process.Process
 	tracker.Mutex.Unlock()
 
 	// Kill the process (outside of mutex to avoid blocking)
-	err := terminateProcessGroup(pid)
-	if err != nil && process != nil {
-		// Fallback to standard kill
-		if killErr := process.Kill(); killErr != nil {
-			LogError("ProcessKill", "Failed to kill process",
-				fmt.Sprintf("PID: %d, Command: %s, Error: %v", pid, command, killErr))
-		}
+	if _, err := terminateTracker(tracker); err != nil {
+		LogError("ProcessKill", "Failed to kill process",
+			fmt.Sprintf("PID: %d, Command: %s, Error: %v", pid, command, err))
 	}
 
 	// Log the kill action
@@ -233,7 +319,66 @@ func (p *ProcessesPageView) removeSelectedProcess() {
 	}
 }
 
-// toggleSort toggles the sort order (newest first vs oldest first)
+// reapTerminatedProcesses removes all completed, failed, and killed processes from the registry
+func (p *ProcessesPageView) reapTerminatedProcesses() {
+	reaped := registry.reapProcesses(nil, 0)
+	if len(reaped) == 0 {
+		return
+	}
+
+	LogInfo("ProcessReap", fmt.Sprintf("Reaped %d terminated processes from TUI", len(reaped)), "")
+	p.Update()
+}
+
+// exportTable writes the processes shown in the table, in table order, to a CSV file in the
+// temp directory (export_processes does the same for clients)
+func (p *ProcessesPageView) exportTable() {
+	var records []ProcessExportRecord
+	for row := 1; row < p.table.GetRowCount(); row++ {
+		cell := p.table.GetCell(row, 6) // ID column, empty on session headers
+		if cell == nil || cell.Text == "" {
+			continue
+		}
+		if process, exists := registry.getProcess(cell.Text); exists {
+			records = append(records, exportRecord(process))
+		}
+	}
+
+	path := filepath.Join(os.TempDir(), fmt.Sprintf("sidekick-processes-%s.csv", time.Now().Format("20060102-150405")))
+	data, err := encodeProcessExport(records, "csv")
+	if err == nil {
+		err = writeProcessExport(path, data)
+	}
+	if err != nil {
+		p.showStatusMessage(fmt.Sprintf("[red]Export failed:[white] %s", tview.Escape(err.Error())))
+		return
+	}
+	LogInfo("Process", "Process table exported from TUI", fmt.Sprintf("Path: %s, processes: %d", path, len(records)))
+	p.showStatusMessage(fmt.Sprintf("[green]Exported %d processes to[white] %s", len(records), tview.Escape(path)))
+}
+
+// showStatusMessage replaces the key hints in the status bar for a few seconds
+func (p *ProcessesPageView) showStatusMessage(message string) {
+	p.statusBar.SetText(message + "\n" + pagesStatusLine)
+	time.AfterFunc(statusMessageDuration, func() {
+		p.tuiApp.app.QueueUpdateDraw(func() {
+			p.statusBar.SetText(statusBarText(ProcessesPage))
+		})
+	})
+}
+
+// MarkViewed moves the last-viewed marker to now, clearing the NEW markers on the next rebuild
+func (p *ProcessesPageView) MarkViewed() {
+	p.lastViewed = time.Now()
+}
+
+// isNew reports whether a process started since the page was last viewed
+// Must be called with process.Mutex held (read or write)
+func (p *ProcessesPageView) isNew(process *ProcessTracker) bool {
+	return process.StartTime.After(p.lastViewed)
+}
+
+// toggleSort toggles the sort direction (newest first vs oldest first for time)
 func (p *ProcessesPageView) toggleSort() {
 	p.reversedSort = !p.reversedSort
 	// Force full refresh when sort changes
@@ -241,6 +386,62 @@ func (p *ProcessesPageView) toggleSort() {
 	p.Refresh()
 }
 
+// cycleSortColumn switches to the next sort column (Time → Status → Name → PID)
+func (p *ProcessesPageView) cycleSortColumn() {
+	p.sortColumn = (p.sortColumn + 1) % (SortByPID + 1)
+	// Time defaults to newest first, other columns to ascending
+	p.reversedSort = p.sortColumn == SortByTime
+	p.isInitialized = false
+	p.Refresh()
+}
+
+// sortSessionGroups orders the processes of each session by the selected column.
+// Groups arrive sorted by start time, so the stable sort keeps time as the tie-breaker.
+func (p *ProcessesPageView) sortSessionGroups(sessionGroups map[string][]*ProcessTracker) {
+	if p.sortColumn == SortByTime {
+		return
+	}
+
+	for _, processes := range sessionGroups {
+		// Snapshot the sort keys so we don't hold process locks inside the comparator
+		type sortKey struct {
+			status int
+			name   string
+			pid    int
+		}
+		keys := make(map[*ProcessTracker]sortKey, len(processes))
+		for _, process := range processes {
+			process.Mutex.RLock()
+			name := process.Name
+			if name == "" {
+				name = process.Command
+			}
+			keys[process] = sortKey{
+				status: statusSortRank[process.Status],
+				name:   strings.ToLower(name),
+				pid:    process.PID,
+			}
+			process.Mutex.RUnlock()
+		}
+
+		sort.SliceStable(processes, func(i, j int) bool {
+			a, b := keys[processes[i]], keys[processes[j]]
+			if p.reversedSort {
+				a, b = b, a
+			}
+			switch p.sortColumn {
+			case SortByStatus:
+				return a.status < b.status
+			case SortByName:
+				return a.name < b.name
+			case SortByPID:
+				return a.pid < b.pid
+			}
+			return false
+		})
+	}
+}
+
 // Refresh refreshes the processes list - FORCE FULL REBUILD
 func (p *ProcessesPageView) Refresh() {
 	p.isInitialized = false
@@ -255,10 +456,20 @@ func (p *ProcessesPageView) Update() {
 // populateTableIncremental uses IDIOMATIC INCREMENTAL UPDATE pattern to avoid visual jumps
 func (p *ProcessesPageView) populateTableIncremental() {
 	// Get current processes grouped by session
-	sessionGroups := GetProcessesBySession(p.reversedSort)
+	timeReversed := p.reversedSort
+	if p.sortColumn != SortByTime {
+		timeReversed = true // Newest first among equal keys
+	}
+	sessionGroups := GetProcessesBySession(timeReversed)
+	p.sortSessionGroups(sessionGroups)
+
+	// Column widths depend on the terminal size - rebuild when it changes
+	_, _, width, _ := p.table.GetInnerRect()
+	widthChanged := width != p.lastTableWidth
+	p.lastTableWidth = width
 
 	// If not initialized or major changes, do full rebuild
-	if !p.isInitialized || p.majorChangesDetected(sessionGroups) {
+	if !p.isInitialized || widthChanged || p.majorChangesDetected(sessionGroups) {
 		p.fullRebuild(sessionGroups)
 		p.isInitialized = true
 		p.lastSessionData = p.copySessionData(sessionGroups)
@@ -285,12 +496,17 @@ func (p *ProcessesPageView) majorChangesDetected(newSessionGroups map[string][]*
 		}
 	}
 
-	// Check if process count per session changed significantly
+	// Check if process count or order per session changed (order shifts when sorting by status)
 	for sessionName, processes := range newSessionGroups {
 		if oldProcesses, exists := p.lastSessionData[sessionName]; exists {
 			if len(processes) != len(oldProcesses) {
 				return true
 			}
+			for i := range processes {
+				if processes[i].ID != oldProcesses[i].ID {
+					return true
+				}
+			}
 		}
 	}
 
@@ -446,7 +662,7 @@ func (p *ProcessesPageView) buildTableContent(sessionGroups map[string][]*Proces
 		}
 
 		// Session header row - spans first column, others empty
-		p.table.SetCell(row, 0, tview.NewTableCell(sessionText).SetTextColor(sessionColor))
+		p.table.SetCell(row, 0, tview.NewTableCell(sessionText).SetTextColor(sessionColor).SetReference(sessionName))
 		for col := 1; col < 7; col++ {
 			p.table.SetCell(row, col, tview.NewTableCell("").SetSelectable(false))
 		}
@@ -501,14 +717,40 @@ func (p *ProcessesPageView) updateTableTitle(sessionGroups map[string][]*Process
 		totalProcesses += len(processes)
 	}
 
-	sortOrder := "↓ Newest First"
-	if !p.reversedSort {
-		sortOrder = "↑ Oldest First"
+	var sortOrder string
+	if p.sortColumn == SortByTime {
+		sortOrder = "↓ Newest First"
+		if !p.reversedSort {
+			sortOrder = "↑ Oldest First"
+		}
+	} else {
+		sortOrder = fmt.Sprintf("Sort: %s ↑", p.sortColumn)
+		if p.reversedSort {
+			sortOrder = fmt.Sprintf("Sort: %s ↓", p.sortColumn)
+		}
 	}
 	title := fmt.Sprintf(" Processes (%d) - %s ", totalProcesses, sortOrder)
+	if newCount := p.countNew(sessionGroups); newCount > 0 {
+		title = fmt.Sprintf(" Processes (%d, %d new) - %s ", totalProcesses, newCount, sortOrder)
+	}
 	p.table.SetTitle(title)
 }
 
+// countNew counts the processes started since the page was last viewed
+func (p *ProcessesPageView) countNew(sessionGroups map[string][]*ProcessTracker) int {
+	count := 0
+	for _, processes := range sessionGroups {
+		for _, process := range processes {
+			process.Mutex.RLock()
+			if p.isNew(process) {
+				count++
+			}
+			process.Mutex.RUnlock()
+		}
+	}
+	return count
+}
+
 // processDataChanged checks if process data has changed between two instances
 func (p *ProcessesPageView) processDataChanged(old, new *ProcessTracker) bool {
 	old.Mutex.RLock()
@@ -570,16 +812,44 @@ func (p *ProcessesPageView) getSessionStatus(processes []*ProcessTracker) string
 	return "Inactive"
 }
 
+// columnWidths returns the name and command column widths for the current table width.
+// Extra space beyond the fixed columns goes mostly to the command.
+func (p *ProcessesPageView) columnWidths() (int, int) {
+	// Session (38) + Status (9) + PID (7) + Time (12) + ID (36) + cell padding
+	const fixedColumnsWidth = 110
+
+	spare := p.lastTableWidth - fixedColumnsWidth - minNameWidth - minCommandWidth
+	if spare <= 0 {
+		return minNameWidth, minCommandWidth
+	}
+	nameExtra := spare / 4
+	return minNameWidth + nameExtra, minCommandWidth + spare - nameExtra
+}
+
+// truncateText shortens text to the given width in runes, adding an ellipsis
+func truncateText(text string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return text
+	}
+	if width <= 3 {
+		return string(runes[:width])
+	}
+	return string(runes[:width-3]) + "..."
+}
+
 // formatName formats process name for display
 func (p *ProcessesPageView) formatName(process *ProcessTracker) string {
 	name := process.Name
 	if name == "" {
 		name = "-"
 	}
-	if len(name) > 15 {
-		name = name[:12] + "..."
+	nameWidth, _ := p.columnWidths()
+	if p.isNew(process) {
+		// Keep the marker inside the column width
+		return "[yellow::b]NEW[-:-:-] " + truncateText(name, max(nameWidth-4, 1))
 	}
-	return name
+	return truncateText(name, nameWidth)
 }
 
 // formatCommand formats process command for display
@@ -588,10 +858,8 @@ func (p *ProcessesPageView) formatCommand(process *ProcessTracker) string {
 	if len(process.Args) > 0 {
 		command += " " + strings.Join(process.Args, " ")
 	}
-	if len(command) > 40 {
-		command = command[:37] + "..."
-	}
-	return command
+	_, commandWidth := p.columnWidths()
+	return truncateText(command, commandWidth)
 }
 
 // formatTime formats time display for processes - shows duration for completed, start time for running
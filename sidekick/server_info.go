@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"runtime"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ServerRuntimeInfo records how sidekick was started, reported by server_info
+type ServerRuntimeInfo struct {
+	SSEMode       bool
+	TUIMode       bool
+	ProcessesMode bool
+	Address       string
+}
+
+// serverRuntimeInfo is filled in by main after flag parsing
+var serverRuntimeInfo ServerRuntimeInfo
+
+// handleServerInfo reports the sidekick version, platform, transports, limits, and optional features
+func handleServerInfo(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	transports := []string{"stdio"}
+	if serverRuntimeInfo.SSEMode {
+		transports = []string{"sse", "streamable-http"}
+	}
+
+	maxQuestionBytes, maxAnswerBytes, truncateOversized := agentQARegistry.SizeLimits()
+
+	result := map[string]any{
+		"version":    version,
+		"go_version": runtime.Version(),
+		"platform":   runtime.GOOS + "/" + runtime.GOARCH,
+		"transports": transports,
+		"limits": map[string]any{
+			"max_processes":            0, // No cap on tracked processes
+			"default_buffer_size":      DefaultBufferSize,
+			"default_combine_output":   defaultCombineOutput,
+			"default_streams":          defaultOutputStreams,
+			"default_combine_reads":    defaultCombineReads,
+			"max_filter_concurrency":   cap(filterSlots.slots),
+			"max_spawn_delay_ms":       MaxSpawnDelay,
+			"max_output_delay_ms":      MaxOutputDelay,
+			"max_question_bytes":       maxQuestionBytes,
+			"max_answer_bytes":         maxAnswerBytes,
+			"truncate_oversized_qa":    truncateOversized,
+			"default_question_retries": DefaultQuestionRetries,
+			"max_question_retries":     MaxQuestionRetries,
+			"log_max_entries":          logger.Stats().Capacity,
+			"max_sse_connections":      sseConnections.Stats().Max,
+			"max_total_buffer_bytes":   bufferBudget.Limit(),
+		},
+		"features": map[string]any{
+			"processes":           serverRuntimeInfo.ProcessesMode,
+			"process_resources":   serverRuntimeInfo.ProcessesMode,
+			"spawn_confinement":   spawnPolicy.Active(),
+			"run_as_user":         runtime.GOOS != "windows",
+			"notifications_speak": runtime.GOOS == "darwin",
+			"tui":                 serverRuntimeInfo.TUIMode,
+			"pty":                 false,
+			"tls":                 false,
+			"auth":                false,
+		},
+	}
+	if serverRuntimeInfo.SSEMode {
+		result["address"] = serverRuntimeInfo.Address
+	}
+
+	resultBytes, _ := json.Marshal(result)
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
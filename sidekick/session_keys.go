@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sync"
+	"time"
+)
+
+const (
+	SessionKeyParam       = "session_key"   // Query parameter on the SSE connect URL
+	SessionKeyHeader      = "X-Session-Key" // Header alternative to SessionKeyParam
+	SessionKeyGracePeriod = 30 * time.Second
+)
+
+// keyedSessionPrefix keeps keyed session IDs apart from transport session IDs, so a client
+// can't take over another client's live session by using its transport ID as the key
+const keyedSessionPrefix = "key:"
+
+// keyedSessionIDBytes is how much of the salted key hash a keyed session ID keeps
+const keyedSessionIDBytes = 16
+
+// sessionKeyPattern limits keys to URL- and log-safe identifiers
+var sessionKeyPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// sessionKeyContextKey carries the SSE connect request's session key to the register hook
+type sessionKeyContextKey struct{}
+
+// sessionKeyFromRequest returns the session key an SSE client asked for ("" = none)
+func sessionKeyFromRequest(r *http.Request) (string, error) {
+	key := r.URL.Query().Get(SessionKeyParam)
+	if key == "" {
+		key = r.Header.Get(SessionKeyHeader)
+	}
+	if key != "" && !sessionKeyPattern.MatchString(key) {
+		return "", fmt.Errorf("invalid session key: use 1-128 letters, digits, '.', '_' or '-'")
+	}
+	return key, nil
+}
+
+// withSessionKey stores key in the request context for the register hook
+func withSessionKey(r *http.Request, key string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), sessionKeyContextKey{}, key))
+}
+
+// SessionKeys maps SSE transport sessions to stable, client-chosen session keys. Processes
+// belong to the key, so a client that reconnects with the same key within
+// SessionKeyGracePeriod keeps them; the transport session ID changes on every connect.
+type SessionKeys struct {
+	mu          sync.Mutex
+	byTransport map[string]string      // transport session ID -> keyed session ID
+	connections map[string]int         // keyed session ID -> connected transport sessions
+	closing     map[string]*time.Timer // keyed session ID -> pending close after the last transport left
+	grace       time.Duration
+	salt        []byte // Random per server run, so session IDs shown to other clients don't reveal keys
+}
+
+var sessionKeys = NewSessionKeys(SessionKeyGracePeriod)
+
+// NewSessionKeys creates an empty key map whose sessions close grace after their last transport
+func NewSessionKeys(grace time.Duration) *SessionKeys {
+	salt := make([]byte, sha256.Size)
+	rand.Read(salt)
+	return &SessionKeys{
+		byTransport: make(map[string]string),
+		connections: make(map[string]int),
+		closing:     make(map[string]*time.Timer),
+		grace:       grace,
+		salt:        salt,
+	}
+}
+
+// sessionID returns the opaque keyed session ID for key: "key:" and a salted hash of the key.
+// Session IDs are listed to every client, so the key itself must not be recoverable from them.
+func (k *SessionKeys) sessionID(key string) string {
+	mac := hmac.New(sha256.New, k.salt)
+	mac.Write([]byte(key))
+	return keyedSessionPrefix + hex.EncodeToString(mac.Sum(nil)[:keyedSessionIDBytes])
+}
+
+// bind attaches a transport session to key, cancelling a pending close. Returns the keyed
+// session ID and true when the key resumes an existing session.
+func (k *SessionKeys) bind(transportID, key string) (string, bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	sessionID := k.sessionID(key)
+	resumed := k.connections[sessionID] > 0
+	if timer, pending := k.closing[sessionID]; pending {
+		timer.Stop()
+		delete(k.closing, sessionID)
+		resumed = true
+	}
+	k.byTransport[transportID] = sessionID
+	k.connections[sessionID]++
+	return sessionID, resumed
+}
+
+// resolve returns the session that owns work done on a transport session: its keyed session
+// ID, or the transport session ID itself when no key was given
+func (k *SessionKeys) resolve(transportID string) string {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if sessionID, keyed := k.byTransport[transportID]; keyed {
+		return sessionID
+	}
+	return transportID
+}
+
+// release detaches a transport session. When it was the key's last transport, closeSession
+// runs for the keyed session after the grace period unless a client reconnects with the key
+// first. Returns the keyed session ID, or false for transport sessions without one.
+func (k *SessionKeys) release(transportID string, closeSession func(string)) (string, bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	sessionID, keyed := k.byTransport[transportID]
+	if !keyed {
+		return "", false
+	}
+	delete(k.byTransport, transportID)
+	if k.connections[sessionID]--; k.connections[sessionID] > 0 {
+		return sessionID, true
+	}
+	delete(k.connections, sessionID)
+
+	var timer *time.Timer
+	timer = time.AfterFunc(k.grace, func() {
+		k.mu.Lock()
+		current := k.closing[sessionID]
+		if current == timer {
+			delete(k.closing, sessionID)
+		}
+		k.mu.Unlock()
+		if current == timer {
+			closeSession(sessionID)
+		}
+	})
+	k.closing[sessionID] = timer
+	return sessionID, true
+}
@@ -89,6 +89,21 @@ func (sm *SessionManager) MarkSessionDisconnected(sessionID string) []string {
 	return []string{}
 }
 
+// MarkSessionConnected marks a session connected again after a client resumed it,
+// creating it if needed
+func (sm *SessionManager) MarkSessionConnected(sessionID string) {
+	session := sm.EnsureSessionExists(sessionID)
+
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if session.Status == SessionDisconnected {
+		session.Status = SessionConnected
+		session.Context, session.Cancel = context.WithCancel(context.Background())
+		LogInfo("Session", "Session marked as connected", fmt.Sprintf("SessionID: %s", sessionID))
+	}
+}
+
 // RemoveSession removes a session and returns its process IDs
 func (sm *SessionManager) RemoveSession(sessionID string) []string {
 	sm.mu.Lock()
@@ -147,7 +162,8 @@ func ExtractSessionFromContext(ctx context.Context) string {
 	// Extract session from context using mark3labs/mcp-go method
 	session := server.ClientSessionFromContext(ctx)
 	if session != nil {
-		sessionID := session.SessionID()
+		// Sessions opened with a session key are tracked under the key across reconnects
+		sessionID := sessionKeys.resolve(session.SessionID())
 		LogInfo("Session", "Extracted session ID from context", fmt.Sprintf("SessionID: '%s', Length: %d", sessionID, len(sessionID)))
 
 		// Validate session ID format
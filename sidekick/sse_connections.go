@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// sseConnectionContextKey marks an SSE connect request that was admitted under the
+// --max-sse-connections cap, so the session hooks can count it
+type sseConnectionContextKey struct{}
+
+// SSEConnectionStats is a snapshot of the open SSE connections, for /healthz and connection_stats
+type SSEConnectionStats struct {
+	Current  int   `json:"current"`  // Open SSE streams with a registered session
+	Pending  int   `json:"pending"`  // Admitted, not yet registered
+	Peak     int   `json:"peak"`     // Most streams open at once since start
+	Max      int   `json:"max"`      // --max-sse-connections, 0 = unlimited
+	Total    int64 `json:"total"`    // Sessions registered since start
+	Rejected int64 `json:"rejected"` // Connects refused because the cap was reached
+}
+
+// SSEConnections counts SSE streams and enforces --max-sse-connections. A connect is admitted
+// in the HTTP handler (holding a pending slot) and becomes a connection in the register hook;
+// the unregister hook frees it.
+type SSEConnections struct {
+	mu       sync.Mutex
+	max      int
+	pending  int
+	sessions map[string]bool // Transport session IDs of the open streams
+	peak     int
+	total    int64
+	rejected int64
+}
+
+// sseConnections tracks the SSE streams of this server
+var sseConnections = &SSEConnections{sessions: make(map[string]bool)}
+
+// sseAdmission is one admitted connect request, until its session registers or the request ends
+type sseAdmission struct {
+	once sync.Once
+}
+
+// SetMax sets the connection cap (0 = unlimited). Streams already open are never closed.
+func (c *SSEConnections) SetMax(max int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.max = max
+}
+
+// admit reserves a slot for a new SSE stream, or returns nil when the cap is reached
+func (c *SSEConnections) admit() *sseAdmission {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.max > 0 && len(c.sessions)+c.pending >= c.max {
+		c.rejected++
+		return nil
+	}
+	c.pending++
+	return &sseAdmission{}
+}
+
+// settle gives up an admission's pending slot, once
+func (c *SSEConnections) settle(admission *sseAdmission) {
+	admission.once.Do(func() {
+		c.mu.Lock()
+		c.pending--
+		c.mu.Unlock()
+	})
+}
+
+// connected turns the admission carried by ctx into an open connection for sessionID
+func (c *SSEConnections) connected(ctx context.Context, sessionID string) {
+	admission, ok := ctx.Value(sseConnectionContextKey{}).(*sseAdmission)
+	if !ok {
+		return // Not an SSE stream
+	}
+	c.settle(admission)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sessions[sessionID] = true
+	c.total++
+	if len(c.sessions) > c.peak {
+		c.peak = len(c.sessions)
+	}
+}
+
+// disconnected frees sessionID's connection, if it is an SSE stream
+func (c *SSEConnections) disconnected(sessionID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.sessions, sessionID)
+}
+
+// Stats returns the current counts
+func (c *SSEConnections) Stats() SSEConnectionStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return SSEConnectionStats{
+		Current:  len(c.sessions),
+		Pending:  c.pending,
+		Peak:     c.peak,
+		Max:      c.max,
+		Total:    c.total,
+		Rejected: c.rejected,
+	}
+}
+
+// serveLimitedSSE serves an SSE connect under the --max-sse-connections cap, answering 503
+// when it is reached
+func serveLimitedSSE(next http.Handler, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		next.ServeHTTP(w, r)
+		return
+	}
+
+	admission := sseConnections.admit()
+	if admission == nil {
+		stats := sseConnections.Stats()
+		LogWarn("HTTPServer", "SSE connection rejected, limit reached", fmt.Sprintf("Max: %d, remote: %s", stats.Max, r.RemoteAddr))
+		w.Header().Set("Retry-After", "30")
+		http.Error(w, fmt.Sprintf("Too many SSE connections (limit %d)", stats.Max), http.StatusServiceUnavailable)
+		return
+	}
+	// Frees the slot if the stream ends before its session registered
+	defer sseConnections.settle(admission)
+
+	next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), sseConnectionContextKey{}, admission)))
+}
+
+// handleConnectionStats reports the open SSE connections and optionally changes the cap
+func handleConnectionStats(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	if args, ok := request.Params.Arguments.(map[string]any); ok {
+		if _, set := args["max_connections"]; set {
+			max := getIntArg(request, "max_connections", -1)
+			if max < 0 {
+				return mcp.NewToolResultError("max_connections must be 0 (unlimited) or positive"), nil
+			}
+			sseConnections.SetMax(max)
+			LogInfo("HTTPServer", "SSE connection limit changed", fmt.Sprintf("Max: %d", max))
+		}
+	}
+
+	resultBytes, _ := json.Marshal(sseConnections.Stats())
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
@@ -8,9 +8,11 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"path"
 	"regexp"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -193,25 +195,187 @@ func loggingMiddleware(next http.Handler, transport string) http.Handler {
 	})
 }
 
+// sseKeepAliveComment is an SSE comment line; clients ignore it, intermediaries see traffic
+const sseKeepAliveComment = ": ping\n\n"
+
+// keepAliveWriter serializes writes to an SSE stream so heartbeat comments
+// are only ever written between complete events, never inside one
+type keepAliveWriter struct {
+	http.ResponseWriter
+	mu      sync.Mutex
+	started bool // Set once the handler has written, so headers are already sent
+	closed  bool // Set when the handler returns; the writer must not be used after that
+}
+
+func (kw *keepAliveWriter) Write(b []byte) (int, error) {
+	kw.mu.Lock()
+	defer kw.mu.Unlock()
+	kw.started = true
+	return kw.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher if the underlying ResponseWriter supports it
+func (kw *keepAliveWriter) Flush() {
+	kw.mu.Lock()
+	defer kw.mu.Unlock()
+	if flusher, ok := kw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// ping writes a heartbeat comment once the stream is established
+func (kw *keepAliveWriter) ping() error {
+	kw.mu.Lock()
+	defer kw.mu.Unlock()
+	if !kw.started || kw.closed {
+		return nil
+	}
+	if _, err := io.WriteString(kw.ResponseWriter, sseKeepAliveComment); err != nil {
+		return err
+	}
+	if flusher, ok := kw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return nil
+}
+
+func (kw *keepAliveWriter) close() {
+	kw.mu.Lock()
+	defer kw.mu.Unlock()
+	kw.closed = true
+}
+
+// sseKeepAliveMiddleware writes a ": ping" comment on each SSE stream every interval
+// to keep proxies and clients from dropping idle connections
+func sseKeepAliveMiddleware(next http.Handler, interval time.Duration) http.Handler {
+	if interval <= 0 {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		kw := &keepAliveWriter{ResponseWriter: w}
+		done := make(chan struct{})
+		defer func() {
+			kw.close()
+			close(done)
+		}()
+
+		go func() {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					if err := kw.ping(); err != nil {
+						return
+					}
+				case <-done:
+					return
+				case <-r.Context().Done():
+					return
+				}
+			}
+		}()
+
+		next.ServeHTTP(kw, r)
+	})
+}
+
+// DefaultSSEKeepAlive is the default interval between SSE heartbeat comments
+const DefaultSSEKeepAlive = 15 * time.Second
+
 // SSEServerConfig holds configuration for the HTTP server
 type SSEServerConfig struct {
-	Host string
-	Port string
+	Host              string
+	Port              string
+	KeepAlive         time.Duration // Interval between ": ping" comments on SSE streams (0 = disabled)
+	MaxSSEConnections int           // Open SSE streams allowed at once; more connects get 503 (0 = unlimited)
+}
+
+// httpBasePath prefixes every HTTP endpoint (--base-path), e.g. "/sidekick" serves
+// /sidekick/mcp/sse and /sidekick/healthz. Empty mounts them at the root.
+var httpBasePath = ""
+
+// normalizeBasePath validates a --base-path value and returns it with a leading slash and
+// without a trailing one ("" for the root)
+func normalizeBasePath(basePath string) (string, error) {
+	basePath = strings.TrimRight(strings.TrimSpace(basePath), "/")
+	if basePath == "" {
+		return "", nil
+	}
+	if !strings.HasPrefix(basePath, "/") {
+		basePath = "/" + basePath
+	}
+	if strings.ContainsAny(basePath, "?#% \t") || path.Clean(basePath) != basePath {
+		return "", fmt.Errorf("invalid base path %q: use a plain path such as /sidekick", basePath)
+	}
+	return basePath, nil
+}
+
+// routePath returns the request path relative to httpBasePath, or false when the request is
+// outside the base path
+func routePath(r *http.Request) (string, bool) {
+	rest, ok := strings.CutPrefix(r.URL.Path, httpBasePath)
+	if !ok || (rest != "" && !strings.HasPrefix(rest, "/")) {
+		return "", false
+	}
+	return rest, true
 }
 
 // combinedHandler routes requests to either SSE or Streamable HTTP transport
 type combinedHandler struct {
-	sseServer                   *server.SSEServer
-	streamableHTTPServer        *server.StreamableHTTPServer
+	sseServer                     *server.SSEServer
+	sseHandler                    http.Handler // sseServer wrapped with the keepalive heartbeat
+	streamableHTTPServer          *server.StreamableHTTPServer
 	streamableHTTPStrippedHandler http.Handler
 }
 
 func (h *combinedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	path := r.URL.Path
+	// Routing works on the path below --base-path; the SSE server matches the full path itself
+	path, ok := routePath(r)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	// Health and drain control live outside the MCP endpoints
+	switch path {
+	case "/healthz":
+		handleHealthz(w, r)
+		return
+	case "/drain":
+		handleDrainRequest(w, r)
+		return
+	}
+
+	// While draining, existing sessions keep working but no new ones are opened
+	if httpDrain.isDraining() && isNewSessionRequest(r) {
+		w.Header().Set("Retry-After", "30")
+		http.Error(w, "Sidekick is draining and not accepting new sessions", http.StatusServiceUnavailable)
+		return
+	}
 
 	// Route to SSE server for SSE-specific endpoints
 	// SSE uses: GET /mcp/sse for event stream, POST /mcp/message for messages
-	if strings.HasPrefix(path, "/mcp/sse") || strings.HasPrefix(path, "/mcp/message") {
+	if strings.HasPrefix(path, "/mcp/sse") {
+		// A session key lets a reconnecting client resume its session (see SessionKeys)
+		key, err := sessionKeyFromRequest(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if key != "" {
+			r = withSessionKey(r, key)
+		}
+		serveLimitedSSE(h.sseHandler, w, r)
+		return
+	}
+	if strings.HasPrefix(path, "/mcp/message") {
 		h.sseServer.ServeHTTP(w, r)
 		return
 	}
@@ -244,8 +408,7 @@ func StartSSEServer(mcpServer *server.MCPServer, config SSEServerConfig) error {
 	// Create SSE server for SSE transport (Claude Code, etc.)
 	sseServer := server.NewSSEServer(mcpServer,
 		server.WithBaseURL(fmt.Sprintf("http://%s:%s", config.Host, config.Port)),
-		server.WithStaticBasePath("/mcp"),
-		server.WithKeepAlive(true),
+		server.WithStaticBasePath(httpBasePath+"/mcp"), // Also puts the prefix in the advertised message URL
 	)
 
 	// Create Streamable HTTP server for Streamable HTTP transport (Codex, etc.)
@@ -260,6 +423,8 @@ func StartSSEServer(mcpServer *server.MCPServer, config SSEServerConfig) error {
 		server.WithHeartbeatInterval(30*time.Second), // Keep connection alive
 	)
 
+	sseConnections.SetMax(config.MaxSSEConnections)
+
 	// Store servers globally for session tracking
 	globalSSEServer = sseServer
 	globalStreamableHTTPServer = streamableHTTPServer
@@ -268,17 +433,19 @@ func StartSSEServer(mcpServer *server.MCPServer, config SSEServerConfig) error {
 	// Use http.StripPrefix for StreamableHTTP since WithEndpointPath only works with Start()
 	// Wrap with logging middleware for debugging HTTP errors
 	streamableHTTPWithLogging := loggingMiddleware(
-		http.StripPrefix("/mcp", streamableHTTPServer),
+		http.StripPrefix(httpBasePath+"/mcp", streamableHTTPServer),
 		"StreamableHTTP",
 	)
 	handler := &combinedHandler{
 		sseServer:                     sseServer,
+		sseHandler:                    sseKeepAliveMiddleware(sseServer, config.KeepAlive),
 		streamableHTTPServer:          streamableHTTPServer,
 		streamableHTTPStrippedHandler: streamableHTTPWithLogging,
 	}
 
-	LogInfo("HTTPServer", "SSE endpoint available", fmt.Sprintf("URL: http://%s/mcp/sse", addr))
-	LogInfo("HTTPServer", "Streamable HTTP endpoint available", fmt.Sprintf("URL: http://%s/mcp", addr))
+	LogInfo("HTTPServer", "SSE endpoint available", fmt.Sprintf("URL: http://%s%s/mcp/sse", addr, httpBasePath))
+	LogInfo("HTTPServer", "Streamable HTTP endpoint available", fmt.Sprintf("URL: http://%s%s/mcp", addr, httpBasePath))
+	LogInfo("HTTPServer", "Health endpoint available", fmt.Sprintf("URL: http://%s%s/healthz", addr, httpBasePath))
 
 	// Create HTTP server with combined handler
 	// Set very large timeouts (24 hours) to support long-running tool calls like get_next_question
@@ -352,8 +519,38 @@ func StartSSEServer(mcpServer *server.MCPServer, config SSEServerConfig) error {
 	}
 }
 
+// handleSessionRegistered binds a new transport session to the session key its client
+// connected with, if any
+func handleSessionRegistered(ctx context.Context, transportID string) {
+	key, _ := ctx.Value(sessionKeyContextKey{}).(string)
+	if key == "" {
+		return
+	}
+	// Only the opaque keyed session ID is logged, never the key
+	if sessionID, resumed := sessionKeys.bind(transportID, key); resumed {
+		sessionManager.MarkSessionConnected(sessionID)
+		LogInfo("HTTPServer", "Session resumed with session key", fmt.Sprintf("KeyedSessionID: %s, SessionID: %s", sessionID, transportID))
+	} else {
+		LogInfo("HTTPServer", "Session opened with session key", fmt.Sprintf("KeyedSessionID: %s, SessionID: %s", sessionID, transportID))
+	}
+}
+
 // handleSessionClosed is called when a session is closed
 func handleSessionClosed(sessionID string) {
+	// Stop pushing output updates to the departed transport session
+	outputSubscriptions.RemoveSession(sessionID)
+
+	// A keyed session outlives its transport for the grace period, so a reconnect can resume it
+	if keyedID, keyed := sessionKeys.release(sessionID, closeSession); keyed {
+		LogInfo("HTTPServer", "Keyed session disconnected, keeping its processes",
+			fmt.Sprintf("KeyedSessionID: %s, SessionID: %s, grace: %s", keyedID, sessionID, SessionKeyGracePeriod))
+		return
+	}
+	closeSession(sessionID)
+}
+
+// closeSession marks a session disconnected and kills its processes
+func closeSession(sessionID string) {
 	LogInfo("HTTPServer", "Session disconnected, cleaning up", fmt.Sprintf("SessionID: %s", sessionID))
 
 	// Mark session as disconnected (but keep it in memory)
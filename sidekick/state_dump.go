@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// stateDumpFile is where the state snapshot is written on SIGTERM/SIGINT (--state-dump-file, empty = disabled)
+var stateDumpFile string
+
+// StateSnapshot is a point-in-time view of what sidekick is tracking.
+// Processes carry metadata only; output buffers are never included.
+type StateSnapshot struct {
+	Version   string            `json:"version"`
+	Timestamp time.Time         `json:"timestamp"`
+	PID       int               `json:"pid"`
+	Processes []json.RawMessage `json:"processes"`
+	Sessions  []SessionSnapshot `json:"sessions"`
+}
+
+// SessionSnapshot describes a client session in a state snapshot
+type SessionSnapshot struct {
+	ID        string        `json:"id"`
+	Status    SessionStatus `json:"status"`
+	Processes []string      `json:"processes"`
+}
+
+// buildStateSnapshot captures the process registry and sessions, processes ordered by start time
+func buildStateSnapshot(r *ProcessRegistry, sm *SessionManager) *StateSnapshot {
+	r.mutex.RLock()
+	trackers := make([]*ProcessTracker, 0, len(r.processes))
+	for _, tracker := range r.processes {
+		trackers = append(trackers, tracker)
+	}
+	r.mutex.RUnlock()
+
+	type entry struct {
+		start time.Time
+		data  json.RawMessage
+	}
+	entries := make([]entry, 0, len(trackers))
+	for _, tracker := range trackers {
+		tracker.Mutex.RLock()
+		data, err := json.Marshal(tracker)
+		start := tracker.StartTime
+		tracker.Mutex.RUnlock()
+		if err != nil {
+			continue
+		}
+		entries = append(entries, entry{start: start, data: data})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].start.Before(entries[j].start)
+	})
+
+	snapshot := &StateSnapshot{
+		Version:   version,
+		Timestamp: time.Now(),
+		PID:       os.Getpid(),
+		Processes: make([]json.RawMessage, 0, len(entries)),
+		Sessions:  []SessionSnapshot{},
+	}
+	for _, e := range entries {
+		snapshot.Processes = append(snapshot.Processes, e.data)
+	}
+
+	sm.mu.RLock()
+	for _, session := range sm.sessions {
+		snapshot.Sessions = append(snapshot.Sessions, SessionSnapshot{
+			ID:        session.ID,
+			Status:    session.Status,
+			Processes: append([]string{}, session.Processes...),
+		})
+	}
+	sm.mu.RUnlock()
+	sort.Slice(snapshot.Sessions, func(i, j int) bool {
+		return snapshot.Sessions[i].ID < snapshot.Sessions[j].ID
+	})
+
+	return snapshot
+}
+
+// writeStateDump writes the current state snapshot to path
+func writeStateDump(path string) error {
+	data, err := json.MarshalIndent(buildStateSnapshot(registry, sessionManager), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// dumpStateOnSignal writes the snapshot to --state-dump-file, if configured, before shutting down
+func dumpStateOnSignal() {
+	if stateDumpFile == "" {
+		return
+	}
+	if err := writeStateDump(stateDumpFile); err != nil {
+		LogError("Main", "Failed to write state dump", fmt.Sprintf("Path: %s, error: %v", stateDumpFile, err))
+		return
+	}
+	LogInfo("Main", "State dump written", fmt.Sprintf("Path: %s", stateDumpFile))
+}
+
+// handleDumpState returns a JSON snapshot of all tracked processes (metadata only) and sessions
+func handleDumpState(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	resultBytes, err := json.Marshal(buildStateSnapshot(registry, sessionManager))
+	if err != nil {
+		return mcp.NewToolResultError(fmt.Sprintf("Failed to build state snapshot: %v", err)), nil
+	}
+	return mcp.NewToolResultText(string(resultBytes)), nil
+}
@@ -7,6 +7,7 @@ import (
 	"os/signal"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -26,10 +27,14 @@ const (
 	FeaturesPage
 )
 
+// tuiRefreshInterval is how often the TUI checks for changes (set via --tui-refresh)
+var tuiRefreshInterval = 1 * time.Second
+
 // TUIApp represents the main TUI application - IDIOMATIC IMPLEMENTATION
 type TUIApp struct {
 	app               *tview.Application
 	pages             *tview.Pages
+	header            *tview.TextView
 	processesPage     *ProcessesPageView
 	processDetailPage *ProcessDetailPageView
 	notificationsPage *NotificationsPageView
@@ -58,6 +63,13 @@ type TUIApp struct {
 	dataChangeFlags      map[string]bool
 	adaptiveInterval     time.Duration
 	consecutiveNoChanges int
+
+	// ⏸️ Live updates can be paused to read a stable screen
+	paused      bool
+	pausedMutex sync.RWMutex
+
+	// 🔧 Whether the header currently shows maintenance mode
+	headerMaintenance atomic.Bool
 }
 
 // NewTUIApp creates a new TUI application using idiomatic patterns
@@ -67,6 +79,7 @@ func NewTUIApp() *TUIApp {
 	tuiApp := &TUIApp{
 		app:            tview.NewApplication(),
 		pages:          tview.NewPages(),
+		header:         tview.NewTextView(),
 		currentPage:    ProcessesPage,
 		ctx:            ctx,
 		cancel:         cancel,
@@ -97,8 +110,15 @@ func NewTUIApp() *TUIApp {
 	tuiApp.pages.AddPage("agents_qa", tuiApp.agentsQAPage.GetView(), true, false)
 	tuiApp.pages.AddPage("features", tuiApp.featuresPage.GetView(), true, false)
 
-	// Set up the main layout
-	tuiApp.app.SetRoot(tuiApp.pages, true)
+	// Set up the main layout with a one-line header above the pages
+	tuiApp.header.SetDynamicColors(true)
+	tuiApp.header.SetBackgroundColor(tcell.ColorBlack)
+	tuiApp.updateHeader()
+	layout := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(tuiApp.header, 1, 0, false).
+		AddItem(tuiApp.pages, 0, 1, true)
+	tuiApp.app.SetRoot(layout, true)
 
 	// Set up global key handlers
 	tuiApp.app.SetInputCapture(tuiApp.handleGlobalKeys)
@@ -136,7 +156,8 @@ func (t *TUIApp) handleSignals() {
 func (t *TUIApp) handleGlobalKeys(event *tcell.EventKey) *tcell.EventKey {
 	// If an overlay modal is showing, pass all events through to it
 	if t.pages.HasPage("log-detail") || t.pages.HasPage("quit-confirmation") ||
-		t.pages.HasPage("kill-confirmation") || t.pages.HasPage("shutdown-modal") {
+		t.pages.HasPage("kill-confirmation") || t.pages.HasPage("shutdown-modal") ||
+		t.pages.HasPage("help") {
 		return event
 	}
 
@@ -155,6 +176,11 @@ func (t *TUIApp) handleGlobalKeys(event *tcell.EventKey) *tcell.EventKey {
 		}
 	}
 
+	// Check if we're in the Q&A page with the filter input focused, or completing a yq/ya copy
+	if t.currentPage == AgentsQAPage && t.agentsQAPage != nil && (t.agentsQAPage.filterVisible || t.agentsQAPage.pendingYank) {
+		return event
+	}
+
 	// Check if we're in the features page with webhook input field focused
 	if t.currentPage == FeaturesPage && t.featuresPage != nil && t.featuresPage.inputVisible {
 		// Pass all keys to the input field — it handles Enter/Esc via DoneFunc
@@ -187,6 +213,12 @@ func (t *TUIApp) handleGlobalKeys(event *tcell.EventKey) *tcell.EventKey {
 		case '5':
 			t.SwitchToPage(FeaturesPage)
 			return nil
+		case 'p', 'P':
+			t.TogglePause()
+			return nil
+		case '?':
+			ShowHelpModal(t.app, t.pages, t.currentPage)
+			return nil
 		case 'q', 'Q':
 			// Show quit confirmation dialog
 			ShowQuitConfirmation(t.app, t.pages, func() {
@@ -252,6 +284,16 @@ func (t *TUIApp) switchToPrevPage() {
 
 // SwitchToPage switches to the specified page
 func (t *TUIApp) SwitchToPage(page PageType) {
+	// Leaving a page counts as having seen its rows - only later ones are marked NEW
+	if page != t.currentPage {
+		switch t.currentPage {
+		case ProcessesPage:
+			t.processesPage.MarkViewed()
+		case NotificationsPage:
+			t.notificationsPage.MarkViewed()
+		}
+	}
+
 	t.currentPage = page
 
 	// 🔋 Clear currentProcessID when leaving process detail page
@@ -290,14 +332,50 @@ func (t *TUIApp) ShowProcessDetail(processID string) {
 	t.SwitchToPage(ProcessDetailPage)
 }
 
+// TogglePause pauses or resumes live updates
+func (t *TUIApp) TogglePause() {
+	t.pausedMutex.Lock()
+	t.paused = !t.paused
+	t.pausedMutex.Unlock()
+
+	t.updateHeader()
+}
+
+// IsPaused reports whether live updates are paused
+func (t *TUIApp) IsPaused() bool {
+	t.pausedMutex.RLock()
+	defer t.pausedMutex.RUnlock()
+	return t.paused
+}
+
+// updateHeader renders the header line with the live update state
+func (t *TUIApp) updateHeader() {
+	state := fmt.Sprintf("[green]● LIVE[white] (refresh: %s, press [yellow]p[white] to pause)", tuiRefreshInterval)
+
+	if t.IsPaused() {
+		state = "[red]⏸ PAUSED[white] (press [yellow]p[white] to resume)"
+	}
+	inMaintenance := maintenance.isEnabled()
+	t.headerMaintenance.Store(inMaintenance)
+	if inMaintenance {
+		state += " | [red::b]MAINTENANCE[-:-:-] (new spawns and questions refused)"
+	}
+	t.header.SetText(fmt.Sprintf(" [yellow::b]Sidekick[-:-:-] %s | %s | [yellow]?[white]: Help", version, state))
+}
+
 // updateRoutine runs background updates using IDIOMATIC SMART UPDATE PATTERN
 func (t *TUIApp) updateRoutine() {
-	ticker := time.NewTicker(1 * time.Second) // Faster for better responsiveness
+	ticker := time.NewTicker(tuiRefreshInterval)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ticker.C:
+			// Maintenance mode is toggled by a tool, so the header follows it here
+			if maintenance.isEnabled() != t.headerMaintenance.Load() {
+				t.app.QueueUpdateDraw(t.updateHeader)
+			}
+
 			// Smart update detection - only update when something actually changed
 			if t.shouldUpdate() {
 				// IDIOMATIC PATTERN: Always use QueueUpdateDraw from goroutines!
@@ -366,6 +444,11 @@ func (t *TUIApp) checkRecoveryMode() {
 
 // shouldUpdate determines if a screen update is necessary using smart detection
 func (t *TUIApp) shouldUpdate() bool {
+	// ⏸️ Hold the current screen while paused
+	if t.IsPaused() {
+		return false
+	}
+
 	now := time.Now()
 
 	// 🔋 Adaptive intervals: Longer delays when no changes detected
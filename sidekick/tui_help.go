@@ -0,0 +1,179 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// KeyBinding documents a single TUI shortcut.
+// Short is the label shown in the page status bar; bindings without one only appear in the help overlay.
+type KeyBinding struct {
+	Key         string
+	Short       string
+	Description string
+}
+
+// globalKeyBindings are available on every page
+var globalKeyBindings = []KeyBinding{
+	{Key: "1-5", Description: "Switch to Processes / Notifications / Agents Q&A / Logs / Features"},
+	{Key: "Tab / Shift+Tab", Description: "Next / previous page (or focus, on pages with several panels)"},
+	{Key: "p", Description: "Pause / resume live updates"},
+	{Key: "?", Short: "Help", Description: "Show this help"},
+	{Key: "Esc", Description: "Back to Processes, or quit from Processes"},
+	{Key: "q", Description: "Quit"},
+}
+
+// pageKeyBindings are the shortcuts specific to each page
+var pageKeyBindings = map[PageType][]KeyBinding{
+	ProcessesPage: {
+		{Key: "↑↓", Short: "Navigate", Description: "Move selection"},
+		{Key: "Enter", Short: "View Details", Description: "Open the selected process"},
+		{Key: "K", Short: "Kill Process", Description: "Kill the selected process, or every process of the selected session header (asks for confirmation)"},
+		{Key: "Del", Short: "Remove Process", Description: "Remove the selected process from the list"},
+		{Key: "X", Short: "Reap Finished", Description: "Remove all completed, failed, and killed processes"},
+		{Key: "A", Short: "Mark Seen", Description: "Clear the NEW markers on processes started since you last viewed this page"},
+		{Key: "E", Short: "Export", Description: "Write the processes shown to a CSV file in the temp directory (path shown in the status bar)"},
+		{Key: "S", Short: "Sort Column", Description: "Cycle sort column (Time, Status, Name, PID)"},
+		{Key: "R", Short: "Reverse", Description: "Reverse sort direction"},
+		{Key: "Tab", Short: "Switch Page", Description: "Go to the next page"},
+		{Key: "Q", Short: "Quit", Description: "Quit"},
+	},
+	ProcessDetailPage: {
+		{Key: "Tab", Short: "Switch Focus", Description: "Switch between output and input field"},
+		{Key: "Enter", Short: "Send Input", Description: "Send the input field to the process stdin"},
+		{Key: "S", Short: "Toggle Auto-scroll", Description: "Toggle following new output"},
+		{Key: "Esc", Short: "Back", Description: "Return to Processes"},
+		{Key: "Q", Short: "Quit", Description: "Quit"},
+	},
+	NotificationsPage: {
+		{Key: "↑↓", Short: "Navigate", Description: "Move selection"},
+		{Key: "Tab", Short: "Switch Focus", Description: "Switch between history and controls"},
+		{Key: "Enter", Short: "Activate", Description: "Activate the focused control"},
+		{Key: "A", Short: "Mark Seen", Description: "Clear the NEW markers on notifications received since you last viewed this page"},
+		{Key: "Esc", Short: "Back", Description: "Return to Processes"},
+		{Key: "Q", Short: "Quit", Description: "Quit"},
+	},
+	AgentsQAPage: {
+		{Key: "↑↓", Short: "Navigate", Description: "Move selection"},
+		{Key: "Enter", Short: "View Details", Description: "Show the selected specialist or question"},
+		{Key: "yq / ya", Short: "Copy Q/A", Description: "Copy the shown question / answer to the system clipboard"},
+		{Key: "/", Short: "Filter", Description: "Filter by from, question text, status or ID prefix; a full question ID jumps to it (empty clears)"},
+		{Key: "Tab", Short: "Switch Focus", Description: "Switch between list and details"},
+		{Key: "Q", Short: "Quit", Description: "Quit"},
+	},
+	LogsPage: {
+		{Key: "Enter", Short: "View Details", Description: "Show the full log entry"},
+		{Key: "Tab", Short: "Switch panels", Description: "Switch between table and buttons"},
+		{Key: "f", Short: "Filter", Description: "Cycle level filter (All, Error, Warn, Info)"},
+		{Key: "s", Short: "Source", Description: "Cycle source filter (All, then each source in the log); combines with the level filter"},
+		{Key: "c", Short: "Clear", Description: "Clear all logs"},
+		{Key: "↑↓", Short: "Navigate", Description: "Move selection"},
+	},
+	FeaturesPage: {
+		{Key: "↑↓", Short: "Navigate", Description: "Move selection"},
+		{Key: "Enter", Short: "Toggle/Edit", Description: "Toggle or edit the selected feature"},
+		{Key: "Esc", Short: "Back", Description: "Return to Processes"},
+		{Key: "Q", Short: "Quit", Description: "Quit"},
+	},
+}
+
+// pageDisplayNames are the page names used in the help overlay
+var pageDisplayNames = map[PageType]string{
+	ProcessesPage:     "Processes",
+	ProcessDetailPage: "Process Detail",
+	NotificationsPage: "Notifications",
+	AgentsQAPage:      "Agents Q&A",
+	LogsPage:          "Logs",
+	FeaturesPage:      "Features",
+}
+
+// pagesStatusLine is the page switcher hint shared by all status bars
+const pagesStatusLine = "[grey]Pages: [yellow]1[white]: Processes | [yellow]2[white]: Notifications | [yellow]3[white]: Agents Q&A | [yellow]4[white]: Logs | [yellow]5[white]: Features[grey]"
+
+// statusBarText builds a page's status bar from the centralized key bindings
+func statusBarText(page PageType) string {
+	var parts []string
+	for _, binding := range append(pageKeyBindings[page], globalKeyBindings...) {
+		if binding.Short == "" {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("[yellow]%s[white]: %s", binding.Key, binding.Short))
+	}
+	return strings.Join(parts, " | ") + "\n" + pagesStatusLine
+}
+
+// helpText renders all shortcuts, with the current page first
+func helpText(currentPage PageType) string {
+	var builder strings.Builder
+
+	writeSection := func(title string, bindings []KeyBinding) {
+		builder.WriteString(fmt.Sprintf("[yellow::b]%s[-:-:-]\n", title))
+		for _, binding := range bindings {
+			builder.WriteString(fmt.Sprintf("  [aqua]%-16s[white] %s\n", binding.Key, binding.Description))
+		}
+		builder.WriteString("\n")
+	}
+
+	writeSection(pageDisplayNames[currentPage]+" (current page)", pageKeyBindings[currentPage])
+	writeSection("Global", globalKeyBindings)
+
+	for _, page := range []PageType{ProcessesPage, ProcessDetailPage, NotificationsPage, AgentsQAPage, LogsPage, FeaturesPage} {
+		if page == currentPage {
+			continue
+		}
+		writeSection(pageDisplayNames[page], pageKeyBindings[page])
+	}
+
+	builder.WriteString("[grey]Press [yellow]Esc[grey] or [yellow]?[grey] to close | [yellow]↑↓[grey] to scroll[white]")
+	return builder.String()
+}
+
+// ShowHelpModal displays an overlay listing the keyboard shortcuts
+func ShowHelpModal(app *tview.Application, pages *tview.Pages, currentPage PageType) {
+	textView := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true).
+		SetWordWrap(true)
+	textView.SetText(helpText(currentPage))
+
+	textView.SetBorder(true).
+		SetTitle(" Keyboard Shortcuts ").
+		SetTitleAlign(tview.AlignCenter).
+		SetBorderColor(tcell.ColorYellow).
+		SetBackgroundColor(tcell.ColorBlack)
+
+	closeHelp := func() {
+		pages.RemovePage("help")
+		app.SetFocus(pages)
+	}
+
+	textView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEsc:
+			closeHelp()
+			return nil
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case '?', 'q', 'Q':
+				closeHelp()
+				return nil
+			}
+		}
+		return event
+	})
+
+	// Create a centered flex container for the overlay
+	flex := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(textView, 0, 4, true).
+			AddItem(nil, 0, 1, false), 90, 1, true).
+		AddItem(nil, 0, 1, false)
+
+	pages.AddPage("help", flex, true, true)
+	app.SetFocus(textView)
+}
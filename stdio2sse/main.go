@@ -9,8 +9,10 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
+	"regexp"
 	"strings"
 	"sync"
 	"syscall"
@@ -42,8 +44,20 @@ type AsyncStdioBridge struct {
 	messageURL      string
 	pendingRequests map[interface{}]chan JSONRPCMessage
 	requestMutex    sync.RWMutex
+	orderer         *responseOrderer // Non-nil with --preserve-order
+	stats           *bridgeStats     // Non-nil with --stats-interval
+	drainTimeout    time.Duration    // How long shutdown waits for pending responses
+	sessionKey      string           // Sent on every SSE connect so reconnects resume the server session
+	tracer          *bridgeTracer    // Non-nil with --trace-file
 }
 
+// sessionKeyHeader carries --session-key; servers that support it keep the session's state
+// across reconnects that present the same key
+const sessionKeyHeader = "X-Session-Key"
+
+// sessionKeyPattern matches the keys servers accept
+var sessionKeyPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
 func main() {
 	// Handle command-line flags
 	versionFlag := flag.Bool("version", false, "Print version and exit")
@@ -51,6 +65,12 @@ func main() {
 	bridgeName := flag.String("name", "SSE Bridge", "Name for the stdio bridge server")
 	bridgeVersion := flag.String("bridge-version", "1.0.0", "Version for the stdio bridge server")
 	verbose := flag.Bool("verbose", false, "Enable verbose logging")
+	preserveOrder := flag.Bool("preserve-order", false, "Emit responses in request order (responses are held up to --order-window)")
+	statsInterval := flag.Duration("stats-interval", 0, "Log request counts and latency percentiles to stderr at this interval, e.g. 30s (default: 0 = disabled)")
+	orderWindow := flag.Duration("order-window", 10*time.Second, "With --preserve-order, how long to hold later responses behind a slow request")
+	drainTimeout := flag.Duration("drain-timeout", 5*time.Second, "On shutdown or stdin EOF, how long to wait for pending responses before answering them with errors (0 = don't wait)")
+	sessionKey := flag.String("session-key", "", "Stable session key sent on every SSE connect, so the server resumes the same session (and its processes) after a reconnect")
+	traceFile := flag.String("trace-file", "", "Append a timestamped NDJSON transcript of all bridge traffic (stdin, forwards, SSE events, stdout) to this file, with auth headers redacted")
 	flag.Parse()
 
 	if *versionFlag {
@@ -58,6 +78,26 @@ func main() {
 		os.Exit(0)
 	}
 
+	if *preserveOrder && *orderWindow <= 0 {
+		fmt.Fprintf(os.Stderr, "Error: --order-window must be positive\n")
+		os.Exit(1)
+	}
+
+	if *statsInterval < 0 {
+		fmt.Fprintf(os.Stderr, "Error: --stats-interval cannot be negative\n")
+		os.Exit(1)
+	}
+
+	if *drainTimeout < 0 {
+		fmt.Fprintf(os.Stderr, "Error: --drain-timeout cannot be negative\n")
+		os.Exit(1)
+	}
+
+	if *sessionKey != "" && !sessionKeyPattern.MatchString(*sessionKey) {
+		fmt.Fprintf(os.Stderr, "Error: --session-key must be 1-128 letters, digits, '.', '_' or '-'\n")
+		os.Exit(1)
+	}
+
 	if *sseURL == "" {
 		fmt.Fprintf(os.Stderr, "Error: --sse-url is required\n")
 		flag.Usage()
@@ -94,10 +134,32 @@ func main() {
 		stdout:          os.Stdout,
 		verbose:         *verbose,
 		pendingRequests: make(map[interface{}]chan JSONRPCMessage),
+		drainTimeout:    *drainTimeout,
+		sessionKey:      *sessionKey,
+	}
+	if *preserveOrder {
+		bridge.orderer = newResponseOrderer(*orderWindow, bridge.sendResponse)
+	}
+	if *statsInterval > 0 {
+		bridge.stats = newBridgeStats()
+		go bridge.stats.run(ctx, *statsInterval)
+	}
+	if *traceFile != "" {
+		tracer, file, err := openBridgeTracer(*traceFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: cannot open --trace-file: %v\n", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		bridge.tracer = tracer
 	}
 
 	// Initialize and run the bridge
-	if err := bridge.Run(ctx, *bridgeName, *bridgeVersion); err != nil {
+	err := bridge.Run(ctx, *bridgeName, *bridgeVersion)
+	if bridge.stats != nil {
+		log.Printf("Bridge stats (total): %s", bridge.stats.summary())
+	}
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Bridge error: %v\n", err)
 		os.Exit(1)
 	}
@@ -111,32 +173,122 @@ func (b *AsyncStdioBridge) Run(ctx context.Context, name, version string) error
 		return fmt.Errorf("failed to connect to SSE server: %w", err)
 	}
 
+	// The SSE connection outlives ctx so pending responses can still arrive while draining
+	connCtx, stopConn := context.WithCancel(context.WithoutCancel(ctx))
+
 	// Start SSE listener for responses
-	go b.listenSSE(ctx)
+	go b.listenSSE(connCtx)
+
+	// Release held responses when requests time out of the ordering window
+	if b.orderer != nil {
+		go b.orderer.run(connCtx)
+	}
+
+	// Read stdin in the background so shutdown is not blocked on a pending read
+	lines := make(chan []byte)
+	readErr := make(chan error, 1)
+	go func() {
+		for {
+			line, err := b.stdin.ReadBytes('\n')
+			if err != nil {
+				readErr <- err
+				return
+			}
+			lines <- line
+		}
+	}()
 
 	// Main message processing loop
 	for {
 		select {
 		case <-ctx.Done():
 			log.Println("Context cancelled, shutting down...")
+			b.drainPending()
+			stopConn()
 			return nil
-		default:
-			// Read a line from stdin
-			line, err := b.stdin.ReadBytes('\n')
-			if err != nil {
-				if err == io.EOF {
-					log.Println("Stdin closed, shutting down...")
-					return nil
-				}
+		case err := <-readErr:
+			if err == io.EOF {
+				log.Println("Stdin closed, shutting down...")
+			}
+			// Requests already read still get their responses (or shutdown errors) before exiting
+			b.drainPending()
+			stopConn()
+			if err != io.EOF {
 				return fmt.Errorf("failed to read from stdin: %w", err)
 			}
+			return nil
+		case line := <-lines:
+			// Reserve the response slot in stdin order before going async
+			if b.orderer != nil {
+				b.orderer.expectRequest(line)
+			}
+			b.trackPending(line)
 
 			// Process the message asynchronously
-			go b.processMessage(ctx, line)
+			go b.processMessage(connCtx, line)
 		}
 	}
 }
 
+// trackPending records a request read from stdin as waiting for its response.
+// Notifications and unparseable lines are not tracked.
+func (b *AsyncStdioBridge) trackPending(line []byte) {
+	var message JSONRPCMessage
+	if err := json.Unmarshal(line, &message); err != nil || message.ID == nil || message.Method == "" {
+		return
+	}
+
+	b.requestMutex.Lock()
+	defer b.requestMutex.Unlock()
+	b.pendingRequests[message.ID] = make(chan JSONRPCMessage, 1)
+}
+
+// pendingCount returns the number of requests still waiting for a response
+func (b *AsyncStdioBridge) pendingCount() int {
+	b.requestMutex.RLock()
+	defer b.requestMutex.RUnlock()
+	return len(b.pendingRequests)
+}
+
+// drainPending waits up to drainTimeout for pending responses, then answers
+// the remaining requests with JSON-RPC errors so clients are never left hanging
+func (b *AsyncStdioBridge) drainPending() {
+	deadline := time.Now().Add(b.drainTimeout)
+	if pending := b.pendingCount(); pending > 0 && b.drainTimeout > 0 {
+		log.Printf("Waiting up to %s for %d pending responses...", b.drainTimeout, pending)
+	}
+	for b.pendingCount() > 0 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	b.requestMutex.Lock()
+	unanswered := make([]interface{}, 0, len(b.pendingRequests))
+	for id := range b.pendingRequests {
+		unanswered = append(unanswered, id)
+		delete(b.pendingRequests, id)
+	}
+	b.requestMutex.Unlock()
+
+	for _, id := range unanswered {
+		b.emitResponse(JSONRPCMessage{
+			JSONRPC: "2.0",
+			ID:      id,
+			Error: map[string]interface{}{
+				"code":    -32603,
+				"message": "Bridge shutting down: no response received from SSE server",
+			},
+		})
+	}
+	if len(unanswered) > 0 {
+		log.Printf("Answered %d pending requests with shutdown errors", len(unanswered))
+	}
+
+	// Write out anything still held for ordering before the process exits
+	if b.orderer != nil {
+		b.orderer.flushAll()
+	}
+}
+
 func (b *AsyncStdioBridge) testSSEConnection() error {
 	// Try to connect to the SSE endpoint to verify it's available
 	req, err := http.NewRequest("GET", b.sseURL, nil)
@@ -158,6 +310,20 @@ func (b *AsyncStdioBridge) testSSEConnection() error {
 	return nil
 }
 
+// resolveMessageURL resolves the endpoint event's message URL against the SSE URL, so servers that
+// advertise a relative endpoint (e.g. /sidekick/mcp/message?sessionId=...) work behind any host
+func resolveMessageURL(sseURL, endpoint string) string {
+	base, err := url.Parse(sseURL)
+	if err != nil {
+		return endpoint
+	}
+	ref, err := url.Parse(strings.TrimSpace(endpoint))
+	if err != nil {
+		return endpoint
+	}
+	return base.ResolveReference(ref).String()
+}
+
 func (b *AsyncStdioBridge) listenSSE(ctx context.Context) {
 	for {
 		select {
@@ -171,13 +337,18 @@ func (b *AsyncStdioBridge) listenSSE(ctx context.Context) {
 				time.Sleep(5 * time.Second)
 				continue
 			}
+			if b.sessionKey != "" {
+				req.Header.Set(sessionKeyHeader, b.sessionKey)
+			}
 
 			resp, err := b.httpClient.Do(req)
 			if err != nil {
+				b.tracer.record(traceRecord{Event: traceSSEConnect, URL: traceURL(b.sseURL), Headers: redactHeaders(req.Header), Error: err.Error()}, nil)
 				log.Printf("Failed to connect to SSE: %v", err)
 				time.Sleep(5 * time.Second)
 				continue
 			}
+			b.tracer.record(traceRecord{Event: traceSSEConnect, URL: traceURL(b.sseURL), Headers: redactHeaders(req.Header), Status: resp.StatusCode}, nil)
 
 			// Read SSE events
 			scanner := bufio.NewScanner(resp.Body)
@@ -193,7 +364,8 @@ func (b *AsyncStdioBridge) listenSSE(ctx context.Context) {
 					if scanner.Scan() {
 						dataLine := scanner.Text()
 						if strings.HasPrefix(dataLine, "data: ") {
-							messageURL := strings.TrimPrefix(dataLine, "data: ")
+							messageURL := resolveMessageURL(b.sseURL, strings.TrimPrefix(dataLine, "data: "))
+							b.tracer.record(traceRecord{Event: traceSSEEndpoint, URL: traceURL(messageURL)}, nil)
 							b.messageURL = messageURL
 							log.Printf("Received message endpoint: %s", messageURL)
 						}
@@ -223,16 +395,18 @@ func (b *AsyncStdioBridge) handleSSEMessage(data string) {
 	if b.verbose {
 		log.Printf("Received SSE message: %s", data)
 	}
+	b.tracer.record(traceRecord{Event: traceSSEMessage}, []byte(data))
 
 	var message JSONRPCMessage
 	if err := json.Unmarshal([]byte(data), &message); err != nil {
 		log.Printf("Failed to parse SSE message: %v", err)
 		return
 	}
+	b.stats.recordResponse(message)
 
 	// If this is a response to a pending request, send it to the waiting goroutine
-	if message.ID != nil {
-		b.requestMutex.RLock()
+	if message.ID != nil && message.Method == "" {
+		b.requestMutex.Lock()
 		if ch, exists := b.pendingRequests[message.ID]; exists {
 			select {
 			case ch <- message:
@@ -240,12 +414,13 @@ func (b *AsyncStdioBridge) handleSSEMessage(data string) {
 			default:
 				// Channel is full or closed
 			}
+			delete(b.pendingRequests, message.ID)
 		}
-		b.requestMutex.RUnlock()
+		b.requestMutex.Unlock()
 	}
 
 	// Always send the message to stdout as well
-	b.sendResponse(message)
+	b.emitResponse(message)
 }
 
 func (b *AsyncStdioBridge) processMessage(ctx context.Context, messageBytes []byte) {
@@ -259,6 +434,7 @@ func (b *AsyncStdioBridge) processMessage(ctx context.Context, messageBytes []by
 	if b.verbose {
 		log.Printf("Received message: %s", string(messageBytes))
 	}
+	b.tracer.record(traceRecord{Event: traceStdin}, messageBytes)
 
 	// Parse the JSON-RPC message
 	var message JSONRPCMessage
@@ -268,7 +444,22 @@ func (b *AsyncStdioBridge) processMessage(ctx context.Context, messageBytes []by
 	}
 
 	// Forward to SSE server
-	if err := b.forwardToSSE(ctx, messageBytes, message.ID); err != nil {
+	forwardStart := b.stats.startForward(message.ID)
+	err := b.forwardToSSE(ctx, messageBytes, message.ID)
+	b.stats.recordForward(message.ID, forwardStart, err)
+	if err != nil {
+		// Cancelled by shutdown - drainPending answers the request
+		if ctx.Err() != nil {
+			return
+		}
+
+		// The error below is the response - stop waiting for one from the server
+		if message.ID != nil {
+			b.requestMutex.Lock()
+			delete(b.pendingRequests, message.ID)
+			b.requestMutex.Unlock()
+		}
+
 		// Send error response back to client
 		errorResponse := JSONRPCMessage{
 			JSONRPC: "2.0",
@@ -278,7 +469,7 @@ func (b *AsyncStdioBridge) processMessage(ctx context.Context, messageBytes []by
 				"message": fmt.Sprintf("SSE server error: %v", err),
 			},
 		}
-		b.sendResponse(errorResponse)
+		b.emitResponse(errorResponse)
 	}
 }
 
@@ -307,9 +498,11 @@ func (b *AsyncStdioBridge) forwardToSSE(ctx context.Context, messageBytes []byte
 	// Send request
 	resp, err := b.httpClient.Do(req)
 	if err != nil {
+		b.tracer.record(traceRecord{Event: traceForwardError, URL: traceURL(b.messageURL), Headers: redactHeaders(req.Header), Error: err.Error()}, messageBytes)
 		return fmt.Errorf("failed to send request to SSE server: %w", err)
 	}
 	defer resp.Body.Close()
+	b.tracer.record(traceRecord{Event: traceForward, URL: traceURL(b.messageURL), Headers: redactHeaders(req.Header), Status: resp.StatusCode}, messageBytes)
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
 		responseBytes, _ := io.ReadAll(resp.Body)
@@ -319,6 +512,15 @@ func (b *AsyncStdioBridge) forwardToSSE(ctx context.Context, messageBytes []byte
 	return nil
 }
 
+// emitResponse writes a message to stdout, through the orderer when --preserve-order is set
+func (b *AsyncStdioBridge) emitResponse(response JSONRPCMessage) {
+	if b.orderer != nil {
+		b.orderer.deliver(response)
+		return
+	}
+	b.sendResponse(response)
+}
+
 func (b *AsyncStdioBridge) sendResponse(response JSONRPCMessage) {
 	b.mutex.Lock()
 	defer b.mutex.Unlock()
@@ -333,6 +535,8 @@ func (b *AsyncStdioBridge) sendResponse(response JSONRPCMessage) {
 		log.Printf("Sending response: %s", string(responseBytes))
 	}
 
+	b.tracer.record(traceRecord{Event: traceStdout}, responseBytes)
+
 	// Write response with newline
 	if _, err := fmt.Fprintf(b.stdout, "%s\n", string(responseBytes)); err != nil {
 		log.Printf("Failed to write response: %v", err)
@@ -342,3 +342,38 @@ done:
 		t.Fatalf("Failed to connect to SSE server. Output:\n%s", allOutput)
 	}
 }
+
+// TestBridgeStats verifies counters, pending tracking, and percentiles
+func TestBridgeStats(t *testing.T) {
+	stats := newBridgeStats()
+
+	start := stats.startForward(float64(1))
+	stats.recordForward(float64(1), start, nil)
+	start = stats.startForward("2")
+	stats.recordForward("2", start, fmt.Errorf("boom"))
+	stats.startForward(float64(3))
+
+	// Response for request 1, plus a server notification
+	stats.recordResponse(JSONRPCMessage{JSONRPC: "2.0", ID: float64(1)})
+	stats.recordResponse(JSONRPCMessage{JSONRPC: "2.0", Method: "notifications/message"})
+
+	summary := stats.summary()
+	for _, want := range []string{"forwarded=2", "errors=1", "responses=2", "pending=1"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("Expected %q in summary %q", want, summary)
+		}
+	}
+
+	samples := []time.Duration{5, 1, 4, 2, 3}
+	if got := percentile(samples, 50); got != 3 {
+		t.Errorf("Expected p50 of 3, got %d", got)
+	}
+	if got := percentile(samples, 95); got != 4 {
+		t.Errorf("Expected p95 of 4, got %d", got)
+	}
+
+	// A nil collector is a no-op
+	var disabled *bridgeStats
+	disabled.recordForward(1, disabled.startForward(1), nil)
+	disabled.recordResponse(JSONRPCMessage{})
+}
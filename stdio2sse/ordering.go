@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// responseOrderer releases responses in the order their requests were read from stdin.
+// A response that is not back within the window is skipped so one slow request
+// cannot hold back the others; it is written as soon as it arrives.
+type responseOrderer struct {
+	mu     sync.Mutex
+	window time.Duration
+	queue  []*orderSlot          // Outstanding requests in stdin order
+	slots  map[string]*orderSlot // Outstanding requests by ID
+	emit   func(JSONRPCMessage)
+}
+
+// orderSlot is an outstanding request waiting for its response
+type orderSlot struct {
+	key      string
+	deadline time.Time
+	response *JSONRPCMessage
+}
+
+func newResponseOrderer(window time.Duration, emit func(JSONRPCMessage)) *responseOrderer {
+	return &responseOrderer{
+		window: window,
+		slots:  make(map[string]*orderSlot),
+		emit:   emit,
+	}
+}
+
+// orderKey distinguishes numeric and string IDs with the same text (1 vs "1")
+func orderKey(id interface{}) string {
+	return fmt.Sprintf("%T:%v", id, id)
+}
+
+// expectRequest reserves an output slot for a request line read from stdin.
+// Notifications and unparseable lines get no slot.
+func (o *responseOrderer) expectRequest(line []byte) {
+	var message JSONRPCMessage
+	if err := json.Unmarshal(line, &message); err != nil || message.ID == nil || message.Method == "" {
+		return
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	key := orderKey(message.ID)
+	if _, exists := o.slots[key]; exists {
+		return // Duplicate ID - keep the first slot
+	}
+	slot := &orderSlot{key: key, deadline: time.Now().Add(o.window)}
+	o.queue = append(o.queue, slot)
+	o.slots[key] = slot
+}
+
+// deliver queues a response for in-order release, or emits it immediately
+// if no request is waiting on its ID (server requests, late responses)
+func (o *responseOrderer) deliver(message JSONRPCMessage) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if message.ID == nil || message.Method != "" {
+		o.emit(message)
+		return
+	}
+
+	slot, exists := o.slots[orderKey(message.ID)]
+	if !exists || slot.response != nil {
+		o.emit(message)
+		return
+	}
+
+	slot.response = &message
+	o.flushLocked(time.Now())
+}
+
+// flushLocked emits ready responses from the head of the queue, skipping expired slots
+func (o *responseOrderer) flushLocked(now time.Time) {
+	for len(o.queue) > 0 {
+		head := o.queue[0]
+		if head.response == nil && now.Before(head.deadline) {
+			return
+		}
+
+		o.queue = o.queue[1:]
+		delete(o.slots, head.key)
+
+		if head.response != nil {
+			o.emit(*head.response)
+		} else {
+			log.Printf("Response for request %s not received within %s, releasing later responses", head.key, o.window)
+		}
+	}
+}
+
+// flushAll emits every response that has arrived, without waiting on outstanding requests
+func (o *responseOrderer) flushAll() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.flushLocked(time.Now().Add(o.window))
+}
+
+// run periodically releases responses held behind expired requests
+func (o *responseOrderer) run(ctx context.Context) {
+	interval := max(o.window/4, 10*time.Millisecond)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Release whatever has arrived rather than dropping it
+			o.flushAll()
+			return
+		case now := <-ticker.C:
+			o.mu.Lock()
+			o.flushLocked(now)
+			o.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+)
+
+// maxLatencySamples bounds how many recent latencies are kept for percentiles
+const maxLatencySamples = 1000
+
+// bridgeStats collects request counters and latencies for --stats-interval.
+// A nil *bridgeStats is valid and records nothing.
+type bridgeStats struct {
+	mu                sync.Mutex
+	forwarded         int64
+	forwardErrors     int64
+	responses         int64
+	inflight          map[string]time.Time // Request key → forward start, until its response arrives
+	forwardLatencies  []time.Duration      // POST round trip to the server
+	responseLatencies []time.Duration      // Forward start until the response arrives over SSE
+}
+
+func newBridgeStats() *bridgeStats {
+	return &bridgeStats{inflight: make(map[string]time.Time)}
+}
+
+// startForward marks a request as pending and returns its start time.
+// It runs before the POST because the response can arrive over SSE before the POST returns.
+func (s *bridgeStats) startForward(requestID interface{}) time.Time {
+	start := time.Now()
+	if s == nil || requestID == nil {
+		return start
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inflight[orderKey(requestID)] = start
+	return start
+}
+
+// recordForward records a forwarded request and how long the POST took
+func (s *bridgeStats) recordForward(requestID interface{}, start time.Time, err error) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.forwarded++
+	if err != nil {
+		// The bridge answers failed forwards itself, so nothing is pending anymore
+		s.forwardErrors++
+		if requestID != nil {
+			delete(s.inflight, orderKey(requestID))
+		}
+		return
+	}
+	s.forwardLatencies = appendSample(s.forwardLatencies, time.Since(start))
+}
+
+// recordResponse records a message received over SSE
+func (s *bridgeStats) recordResponse(message JSONRPCMessage) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.responses++
+	if message.ID == nil || message.Method != "" {
+		return
+	}
+	key := orderKey(message.ID)
+	if start, exists := s.inflight[key]; exists {
+		s.responseLatencies = appendSample(s.responseLatencies, time.Since(start))
+		delete(s.inflight, key)
+	}
+}
+
+// appendSample adds a latency, dropping the oldest once maxLatencySamples is reached
+func appendSample(samples []time.Duration, sample time.Duration) []time.Duration {
+	if len(samples) >= maxLatencySamples {
+		samples = samples[1:]
+	}
+	return append(samples, sample)
+}
+
+// percentile returns the p-th percentile (0-100) of samples, or 0 when empty
+func percentile(samples []time.Duration, p int) time.Duration {
+	if len(samples) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(samples)
+	slices.Sort(sorted)
+	index := (len(sorted) - 1) * p / 100
+	return sorted[index]
+}
+
+// summary formats the current counters and latency percentiles
+func (s *bridgeStats) summary() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return fmt.Sprintf("forwarded=%d errors=%d responses=%d pending=%d forward_p50=%s forward_p95=%s response_p50=%s response_p95=%s",
+		s.forwarded, s.forwardErrors, s.responses, len(s.inflight),
+		percentile(s.forwardLatencies, 50).Round(time.Microsecond),
+		percentile(s.forwardLatencies, 95).Round(time.Microsecond),
+		percentile(s.responseLatencies, 50).Round(time.Microsecond),
+		percentile(s.responseLatencies, 95).Round(time.Microsecond))
+}
+
+// run logs the stats to stderr every interval until ctx is cancelled
+func (s *bridgeStats) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			log.Printf("Bridge stats: %s", s.summary())
+		}
+	}
+}
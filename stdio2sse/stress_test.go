@@ -44,6 +44,7 @@ func TestBridgeStressTest(t *testing.T) {
 		stdout:          &stdout,
 		verbose:         false, // Reduce noise in stress test
 		pendingRequests: make(map[interface{}]chan JSONRPCMessage),
+		drainTimeout:    5 * time.Second, // stdin hits EOF at once; wait for the responses as --drain-timeout does
 	}
 
 	// Test connection
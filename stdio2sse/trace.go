@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Trace event types, in the order traffic flows through the bridge
+const (
+	traceStdin        = "stdin"         // Line read from the client
+	traceForward      = "forward"       // POST of a message to the server
+	traceForwardError = "forward_error" // POST that failed
+	traceSSEConnect   = "sse_connect"   // SSE stream (re)connect
+	traceSSEEndpoint  = "sse_endpoint"  // endpoint event: the message URL
+	traceSSEMessage   = "sse_message"   // message event from the server
+	traceStdout       = "stdout"        // Line written to the client
+)
+
+// traceRedacted replaces the values of sensitive headers in the trace
+const traceRedacted = "[redacted]"
+
+// sensitiveTraceHeaders are headers whose values never reach the trace file. The session
+// key is included: anyone holding it can resume the session.
+var sensitiveTraceHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"X-Api-Key":           true,
+	http.CanonicalHeaderKey(sessionKeyHeader): true,
+}
+
+// traceRecord is one line of the --trace-file transcript
+type traceRecord struct {
+	Time    string            `json:"time"`
+	Event   string            `json:"event"`
+	URL     string            `json:"url,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Status  int               `json:"status,omitempty"`
+	Error   string            `json:"error,omitempty"`
+	Message json.RawMessage   `json:"message,omitempty"` // The JSON-RPC message, when the data is valid JSON
+	Raw     string            `json:"raw,omitempty"`     // The data as is, when it is not
+}
+
+// bridgeTracer writes every message crossing the bridge, with timestamps, as NDJSON to the
+// --trace-file, independent of --verbose. A nil *bridgeTracer is valid and records nothing.
+type bridgeTracer struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// openBridgeTracer appends the trace to path, readable by the owner only
+func openBridgeTracer(path string) (*bridgeTracer, *os.File, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &bridgeTracer{w: file}, file, nil
+}
+
+// redactHeaders flattens headers for the trace, hiding the values of sensitive ones
+func redactHeaders(header http.Header) map[string]string {
+	if len(header) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	flat := make(map[string]string, len(names))
+	for _, name := range names {
+		if sensitiveTraceHeaders[http.CanonicalHeaderKey(name)] {
+			flat[name] = traceRedacted
+			continue
+		}
+		flat[name] = strings.Join(header[name], ", ")
+	}
+	return flat
+}
+
+// traceURL hides any password in a URL's userinfo
+func traceURL(raw string) string {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	return parsed.Redacted()
+}
+
+// record writes one trace line. data is the message crossing the bridge, if any.
+func (t *bridgeTracer) record(record traceRecord, data []byte) {
+	if t == nil {
+		return
+	}
+	record.Time = time.Now().Format(time.RFC3339Nano)
+	if data = []byte(strings.TrimSpace(string(data))); len(data) > 0 {
+		if json.Valid(data) {
+			record.Message = json.RawMessage(data)
+		} else {
+			record.Raw = string(data)
+		}
+	}
+	line, err := json.Marshal(record)
+	if err != nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, err := t.w.Write(append(line, '\n')); err != nil {
+		log.Printf("Failed to write trace: %v", err)
+	}
+}